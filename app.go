@@ -31,13 +31,20 @@ type App struct {
 	store  *store.Store
 	bqMgr  *bq.Client
 
-	explorer  *ui.Explorer
-	editor    *ui.Editor
-	results   *ui.Results
-	schema    *ui.SchemaView
-	history   *ui.History
-	favorites *ui.Favorites
-	assistant *ui.Assistant
+	explorer    *ui.Explorer
+	editor      *ui.Editor
+	results     *ui.Results
+	chart       *ui.Chart
+	pivot       *ui.Pivot
+	diff        *ui.Diff
+	profile     *ui.Profile
+	execDetails *ui.ExecDetails
+	schema      *ui.SchemaView
+	history     *ui.History
+	favorites   *ui.Favorites
+	assistant   *ui.Assistant
+	jobs        *ui.Jobs
+	spend       *ui.Spend
 
 	aiClient         *ai.Client
 	useTools         bool                       // feature flag: use Claude tool calling
@@ -45,9 +52,29 @@ type App struct {
 	schemaCache      string                     // cached schema context for AI (legacy mode)
 	tableSchemaCache map[string]*bq.TableSchema // cached per-table schemas (legacy mode)
 
+	routinesMu sync.Mutex
+	routines   map[string][]bq.RoutineInfo // dataset node ID -> its routines, for autocomplete; names only until read
+
+	lastResult *bq.QueryResult // result shown in the results pane; UI goroutine only
+	// History entry and run name of lastResult, for snapshots; UI goroutine only.
+	lastHistoryID int64
+	lastRunName   string
+	runs          []ui.DiffSource // recent results for the Diff tab, newest first; UI goroutine only
+	runCount      int
+
 	topArea           *fyne.Container
 	editorSchemaSplit *container.Split
 	rightSplit        *container.Split
+	mainSplit         *container.Split
+	bottomTabs        *container.AppTabs
+	jobsTab           *container.TabItem
+	diffTab           *container.TabItem
+	profileTab        *container.TabItem
+
+	workspaceSelect    *widget.Select
+	workspaceMu        sync.Mutex
+	workspaceName      string // active named workspace
+	lastSavedWorkspace string // serialized state last written, to skip no-op autosaves
 
 	ctx       context.Context
 	cancelRun context.CancelFunc
@@ -64,10 +91,17 @@ func NewApp(window fyne.Window, st *store.Store, ctx context.Context) *App {
 	a.explorer = ui.NewExplorer()
 	a.editor = ui.NewEditor()
 	a.results = ui.NewResults()
+	a.chart = ui.NewChart()
+	a.pivot = ui.NewPivot()
+	a.diff = ui.NewDiff()
+	a.profile = ui.NewProfile()
+	a.execDetails = ui.NewExecDetails()
 	a.schema = ui.NewSchemaView()
 	a.history = ui.NewHistory()
 	a.favorites = ui.NewFavorites()
 	a.assistant = ui.NewAssistant()
+	a.jobs = ui.NewJobs()
+	a.spend = ui.NewSpend()
 
 	a.wireCallbacks()
 	return a
@@ -101,6 +135,9 @@ func (a *App) wireCallbacks() {
 			for i, t := range tables {
 				ids[i] = ui.TableNodeID(project, dataset, t)
 			}
+			for _, r := range a.listRoutines(project, dataset) {
+				ids = append(ids, ui.RoutineNodeID(project, dataset, r.RoutineID))
+			}
 			return ids, nil
 		}
 		return nil, nil
@@ -116,36 +153,16 @@ func (a *App) wireCallbacks() {
 			}
 			sort.Strings(projects)
 			a.explorer.SetAllProjects(projects)
-			a.editor.SetProjects(a.explorer.AllKnownProjects())
+			a.setProjects()
 			a.updateCompletions()
 		}()
 	}
 
 	// Explorer: load datasets+tables for a project (search background loading)
 	a.explorer.OnSearchProject = func(project string) {
-		datasets, err := a.bqMgr.ListDatasets(a.ctx, project)
-		if err != nil {
+		if err := a.loadProjectTree(project); err != nil {
 			return
 		}
-		sort.Strings(datasets)
-
-		result := make(map[string][]string)
-		var mu sync.Mutex
-		var wg sync.WaitGroup
-		for _, ds := range datasets {
-			ds := ds
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				tables, _ := a.bqMgr.ListTables(a.ctx, project, ds)
-				sort.Strings(tables)
-				mu.Lock()
-				result[ds] = tables
-				mu.Unlock()
-			}()
-		}
-		wg.Wait()
-		a.explorer.CacheProjectData(project, result)
 		a.updateCompletions()
 	}
 
@@ -159,6 +176,16 @@ func (a *App) wireCallbacks() {
 		a.loadProjectDataForAutocomplete(project)
 	})
 
+	// Editor: a routine's signature needed for its argument hint → read it
+	a.editor.SetOnRoutineNeeded(func(project, dataset, routine string) {
+		a.loadRoutineSignature(project, dataset, routine)
+	})
+
+	// Explorer: routine selected -> show its signature and body
+	a.explorer.OnRoutineSelected = func(project, dataset, routine string) {
+		go a.showRoutine(project, dataset, routine)
+	}
+
 	// Explorer: table selected -> show schema + generate SELECT query
 	a.explorer.OnTableSelected = func(project, dataset, table string) {
 		go func() {
@@ -198,8 +225,8 @@ func (a *App) wireCallbacks() {
 	}
 
 	// Editor: run query
-	a.editor.RunQuery = func(project, sql string) {
-		go a.runQuery(project, sql)
+	a.editor.RunQuery = func(project, sql string, tab ui.QueryTab) {
+		go a.runQuery(project, sql, tab)
 	}
 
 	// Editor: stop
@@ -209,6 +236,63 @@ func (a *App) wireCallbacks() {
 		}
 	}
 
+	// Editor: open/save .sql files
+	a.wireFileCallbacks()
+
+	// Editor: rename tab from the tab menu
+	a.editor.PromptRename = a.promptTabName
+	a.editor.PromptLabels = a.promptTabLabels
+
+	// Results: export to file or to a BigQuery table
+	a.results.OnExport = a.exportResults
+	a.results.OnSaveAsTable = a.saveResultsAsTable
+	a.chart.OnExport = a.exportChart
+	a.pivot.Results.OnExport = a.exportPivot
+	a.results.OnPin = a.pinResult
+	a.results.OnSaveSnapshot = a.saveSnapshot
+
+	// History: reopen or delete saved snapshots
+	a.history.OnOpenSnapshot = func(id int64) {
+		go a.openSnapshot(id)
+	}
+	a.history.OnDeleteSnapshot = a.deleteSnapshot
+
+	// Profile: column statistics for the results or a table
+	a.profile.OnProfileResults = a.profileResults
+	a.schema.OnProfile = func(project, dataset, table string) {
+		go a.profileTable(project, dataset, table)
+	}
+
+	// Spend: cost ledger dashboard
+	a.spend.OnRefresh = func() {
+		go a.refreshSpend()
+	}
+	a.spend.OnOpenQuery = func(sql, project string) {
+		a.editor.NewTabWithSQL("", project, sql)
+	}
+
+	// Explorer: load local files into tables
+	a.explorer.OnUploadFile = func(project, dataset, table string) {
+		a.uploadFile(project, dataset, table)
+	}
+
+	// Explorer: table copy, snapshot, rename, expiration, edit and delete
+	a.explorer.OnTableAction = a.tableAction
+	// Explorer: dataset creation, details and edits
+	a.explorer.OnDatasetAction = a.datasetAction
+	a.jobs.Audit.OnRefresh = func() {
+		go a.refreshAudit()
+	}
+
+	// Jobs: project job history from INFORMATION_SCHEMA
+	a.jobs.Browser.OnLoad = func(project, region string, allUsers bool, days int) {
+		go a.listProjectJobs(project, region, allUsers, days)
+	}
+	a.jobs.Browser.OnOpenSQL = a.openJobSQL
+	a.jobs.Browser.OnLoadResults = func(job ui.ProjectJob) {
+		go a.loadJobResults(job)
+	}
+
 	// History: select -> load SQL
 	a.history.OnSelect = func(sql string) {
 		a.editor.SetSQL(sql)
@@ -244,7 +328,17 @@ func (a *App) wireCallbacks() {
 	}
 }
 
-func (a *App) runQuery(project, sqlText string) {
+// runQuery runs a query from the editor tab tab; an AI generated query has
+// no tab.
+func (a *App) runQuery(project, sqlText string, tab ui.QueryTab) {
+	a.guardStatements(project, sqlText, func() {
+		a.runQueryWithOptions(project, sqlText, tab, a.editorQueryOptions(project, tab))
+	})
+}
+
+// runQueryWithOptions runs a query from the editor with explicit options,
+// e.g. without the bytes billed limit after the user overrode it once.
+func (a *App) runQueryWithOptions(project, sqlText string, tab ui.QueryTab, opts bq.QueryOptions) {
 	if a.cancelRun != nil {
 		a.cancelRun()
 	}
@@ -256,29 +350,57 @@ func (a *App) runQuery(project, sqlText string) {
 	fyne.Do(func() { a.rightSplit.SetOffset(0.4) })
 	start := time.Now()
 
-	result, err := a.bqMgr.RunQuery(ctx, project, sqlText)
+	result, err := a.bqMgr.RunQuery(ctx, project, sqlText, opts)
 	dur := time.Since(start)
 
 	if err != nil {
 		a.results.SetStatus(fmt.Sprintf("Error: %v", err))
-		_ = a.store.AddHistory(sqlText, project, dur, 0, err.Error())
+		_, _ = a.store.AddHistory(sqlText, project, dur, 0, err.Error())
 		a.refreshHistory()
 		a.refreshRecentProjects()
+		if bq.IsBytesBilledLimit(err) {
+			a.showBytesBilledLimitDialog(project, sqlText, tab, err)
+		}
 		return
 	}
 
-	a.results.SetData(result.Columns, result.Rows)
-	a.results.SetStatus(fmt.Sprintf("%d rows | %s | %.2f MB processed",
-		result.RowCount,
+	a.showResult(result, tab.Name)
+	rows := fmt.Sprintf("%d rows", result.RowCount)
+	if result.Truncated() {
+		rows = fmt.Sprintf("%d of %d rows", result.RowCount, result.TotalRows)
+	}
+	a.results.SetStatus(fmt.Sprintf("%s | %s | %.2f MB processed",
+		rows,
 		result.Duration.Round(time.Millisecond),
 		float64(result.BytesProcessed)/(1024*1024),
 	))
 
-	_ = a.store.AddHistory(sqlText, project, dur, result.RowCount, "")
+	histID, _ := a.store.AddHistory(sqlText, project, dur, result.RowCount, "")
+	a.recordQueryCost(sourceEditor, project, histID, result)
+	fyne.Do(func() {
+		if a.lastResult == result {
+			a.lastHistoryID = histID
+		}
+	})
 	a.refreshHistory()
 	a.refreshRecentProjects()
 }
 
+// showResult makes result the current result set of the Results, Chart,
+// Pivot and Execution Details tabs and keeps it for the Diff tab under runName.
+func (a *App) showResult(result *bq.QueryResult, runName string) {
+	fyne.Do(func() {
+		a.lastResult = result
+		a.lastHistoryID = 0
+		a.lastRunName = runName
+		a.recordRun(runName, result)
+	})
+	a.results.SetData(result.Columns, result.ColumnTypes, result.Rows, result.Nulls)
+	a.chart.SetData(result.Columns, result.ColumnTypes, result.Rows)
+	a.pivot.SetData(result.Columns, result.ColumnTypes, result.Rows)
+	a.execDetails.SetStats(execStats(result.Stats))
+}
+
 func (a *App) refreshHistory() {
 	entries, err := a.store.ListHistory(200)
 	if err != nil {
@@ -294,9 +416,12 @@ func (a *App) refreshHistory() {
 			Duration:  e.Duration,
 			RowCount:  e.RowCount,
 			Error:     e.Error,
+
+			SnapshotName: e.SnapshotName,
 		}
 	}
 	a.history.SetEntries(uiEntries)
+	a.refreshSnapshots()
 }
 
 func (a *App) refreshFavorites() {
@@ -340,6 +465,21 @@ func (a *App) saveFavorite() {
 	)
 }
 
+func (a *App) promptTabName(current string, apply func(name string)) {
+	entry := widget.NewEntry()
+	entry.SetText(current)
+	dialog.ShowForm("Rename Tab", "Rename", "Cancel",
+		[]*widget.FormItem{widget.NewFormItem("Name", entry)},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			apply(strings.TrimSpace(entry.Text))
+		},
+		a.window,
+	)
+}
+
 func (a *App) addProject() {
 	entry := widget.NewEntry()
 	entry.SetPlaceHolder("GCP Project ID")
@@ -350,7 +490,7 @@ func (a *App) addProject() {
 				return
 			}
 			a.explorer.AddProject(entry.Text)
-			a.editor.SetProjects(a.explorer.AllKnownProjects())
+			a.setProjects()
 		},
 		a.window,
 	)
@@ -361,11 +501,19 @@ func (a *App) LoadInitialProjects() {
 	go func() {
 		a.refreshFavProjects()
 		a.refreshRecentProjects()
-		a.editor.SetProjects(a.explorer.AllKnownProjects())
+		a.setProjects()
 		a.updateCompletions()
+		a.refreshSpend()
 	}()
 }
 
+// setProjects offers the known projects in the editor and job browser.
+func (a *App) setProjects() {
+	projects := a.explorer.AllKnownProjects()
+	a.editor.SetProjects(projects)
+	a.jobs.Browser.SetProjects(projects)
+}
+
 func (a *App) refreshFavProjects() {
 	favs, err := a.store.ListFavoriteProjects()
 	if err != nil {
@@ -380,7 +528,7 @@ func (a *App) refreshRecentProjects() {
 		return
 	}
 	a.explorer.SetRecentProjects(projects)
-	a.editor.SetProjects(a.explorer.AllKnownProjects())
+	a.setProjects()
 	a.updateCompletions()
 }
 
@@ -447,12 +595,22 @@ func (a *App) hideSchema() {
 }
 
 func (a *App) BuildUI() fyne.CanvasObject {
-	// Bottom tabs: Results | History | Favorites | AI Assistant
-	bottomTabs := container.NewAppTabs(
+	// Bottom tabs: Results | Chart | Pivot | Diff | Profile | Execution Details | History | Favorites | AI Assistant | Jobs | Spend
+	a.jobsTab = container.NewTabItem("Jobs", a.jobs.Container)
+	a.diffTab = container.NewTabItem("Diff", a.diff.Container)
+	a.profileTab = container.NewTabItem("Profile", a.profile.Container)
+	a.bottomTabs = container.NewAppTabs(
 		container.NewTabItem("Results", a.results.Container),
+		container.NewTabItem("Chart", a.chart.Container),
+		container.NewTabItem("Pivot", a.pivot.Container),
+		a.diffTab,
+		a.profileTab,
+		container.NewTabItem("Execution Details", a.execDetails.Container),
 		container.NewTabItem("History", a.history.Container),
 		container.NewTabItem("Favorites", a.favorites.Container),
 		container.NewTabItem("AI Assistant", a.assistant.Container),
+		a.jobsTab,
+		container.NewTabItem("Spend", a.spend.Container),
 	)
 
 	// Top area: editor only by default, schema appears on demand
@@ -463,19 +621,22 @@ func (a *App) BuildUI() fyne.CanvasObject {
 	a.schema.OnClose = func() { a.hideSchema() }
 
 	// Right side: top area | bottom tabs (minimized until query runs)
-	a.rightSplit = container.NewVSplit(a.topArea, bottomTabs)
+	a.rightSplit = container.NewVSplit(a.topArea, a.bottomTabs)
 	a.rightSplit.Offset = 0.9
 
 	// Main: explorer (left) | right
-	mainSplit := container.NewHSplit(a.explorer.Container, a.rightSplit)
-	mainSplit.Offset = 0.2
+	a.mainSplit = container.NewHSplit(a.explorer.Container, a.rightSplit)
+	a.mainSplit.Offset = 0.2
 
 	// Toolbar
+	a.workspaceSelect = widget.NewSelect(nil, a.switchWorkspace)
+	a.workspaceSelect.PlaceHolder = defaultWorkspace
+
 	runBtn := widget.NewButtonWithIcon("Run", theme.Icon(theme.IconNameMediaPlay), func() {
 		project := a.editor.GetCurrentProject()
 		sql := a.editor.GetCurrentSQL()
 		if project != "" && sql != "" {
-			go a.runQuery(project, sql)
+			go a.runQuery(project, sql, a.editor.CurrentQueryTab())
 		}
 	})
 	runBtn.Importance = widget.HighImportance
@@ -493,11 +654,17 @@ func (a *App) BuildUI() fyne.CanvasObject {
 		widget.NewButtonWithIcon("Save Favorite", theme.Icon(theme.IconNameDocumentSave), a.saveFavorite),
 		widget.NewButton("Star Project", a.toggleFavProject),
 		widget.NewButtonWithIcon("Add Project", theme.Icon(theme.IconNameContentAdd), a.addProject),
+		widget.NewButtonWithIcon("Open Job", theme.Icon(theme.IconNameFolderOpen), a.showOpenJobDialog),
+		widget.NewButtonWithIcon("Query Settings", theme.Icon(theme.IconNameSettings), a.showQuerySettingsDialog),
 		layout.NewSpacer(),
+		widget.NewLabel("Workspace:"),
+		a.workspaceSelect,
+		widget.NewButtonWithIcon("", theme.Icon(theme.IconNameContentAdd), a.newWorkspace),
+		widget.NewButtonWithIcon("", theme.Icon(theme.IconNameDelete), a.deleteWorkspace),
 		widget.NewButtonWithIcon("", theme.Icon(theme.IconNameColorPalette), a.toggleTheme),
 	)
 
-	return container.NewBorder(toolbar, nil, nil, nil, mainSplit)
+	return container.NewBorder(toolbar, nil, nil, nil, a.mainSplit)
 }
 
 func (a *App) showError(title string, err error) {
@@ -517,7 +684,12 @@ func (a *App) updateCompletions(extra ...string) {
 	all = append(all, datasets...)
 	all = append(all, tables...)
 	all = append(all, extra...)
+	routines := a.knownRoutines()
+	for _, r := range routines {
+		all = append(all, r.Name)
+	}
 	a.editor.SetCompletions(all)
+	a.editor.SetRoutines(routines)
 	a.editor.SetProjectData(a.explorer.CachedHierarchy())
 }
 
@@ -525,14 +697,24 @@ func (a *App) updateCompletions(extra ...string) {
 // and updates the editor's autocomplete data. Called when the editor detects
 // a dotted path referencing a project whose data isn't cached yet.
 func (a *App) loadProjectDataForAutocomplete(project string) {
-	datasets, err := a.bqMgr.ListDatasets(a.ctx, project)
-	if err != nil {
+	if err := a.loadProjectTree(project); err != nil {
 		log.Printf("autocomplete: failed to list datasets for %s: %v", project, err)
 		return
 	}
+	a.updateCompletions()
+}
+
+// loadProjectTree loads all datasets of a project with their tables and
+// routines into the explorer's cache.
+func (a *App) loadProjectTree(project string) error {
+	datasets, err := a.bqMgr.ListDatasets(a.ctx, project)
+	if err != nil {
+		return err
+	}
 	sort.Strings(datasets)
 
-	result := make(map[string][]string)
+	tables := make(map[string][]string)
+	routines := make(map[string][]string)
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 	for _, ds := range datasets {
@@ -540,16 +722,21 @@ func (a *App) loadProjectDataForAutocomplete(project string) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			tables, _ := a.bqMgr.ListTables(a.ctx, project, ds)
-			sort.Strings(tables)
+			t, _ := a.bqMgr.ListTables(a.ctx, project, ds)
+			sort.Strings(t)
+			var names []string
+			for _, r := range a.listRoutines(project, ds) {
+				names = append(names, r.RoutineID)
+			}
 			mu.Lock()
-			result[ds] = tables
+			tables[ds] = t
+			routines[ds] = names
 			mu.Unlock()
 		}()
 	}
 	wg.Wait()
-	a.explorer.CacheProjectData(project, result)
-	a.updateCompletions()
+	a.explorer.CacheProjectData(project, tables, routines)
+	return nil
 }
 
 func (a *App) handleAIMessage(userMsg string) {
@@ -609,7 +796,7 @@ func (a *App) handleAIMessage(userMsg string) {
 		}
 		log.Printf("ai: auto-running query on project %s", project)
 		a.assistant.SetStatus("Running generated query...")
-		a.runQuery(project, sql)
+		a.runQuery(project, sql, ui.QueryTab{})
 		a.assistant.SetStatus("")
 		fyne.Do(func() { a.rightSplit.SetOffset(0.4) })
 	} else {
@@ -869,12 +1056,21 @@ func (a *App) buildToolExecutor() ai.ToolExecutor {
 				billingProject = project // fallback to Claude's project if none selected
 			}
 			log.Printf("ai: tool run_sql_query: claude requested project=%s, using billing project=%s", project, billingProject)
+			if mode, modeProject := a.strictestMode(billingProject, sql); mode != safetyOff {
+				if writes := a.writingStatements(ctx, billingProject, sql); len(writes) > 0 {
+					return "", errWritesNotAllowed(modeProject, mode, writes)
+				}
+			}
 			sql = enforceQueryLimit(sql)
 			log.Printf("ai: tool run_sql_query (after limit enforcement):\n%s", sql)
-			result, err := a.bqMgr.RunQuery(ctx, billingProject, sql)
+			result, err := a.bqMgr.RunQuery(ctx, billingProject, sql, a.queryOptions(billingProject, sourceAI))
+			if bq.IsBytesBilledLimit(err) {
+				return "", errBytesBilledLimit(a.bytesBilledLimit(billingProject))
+			}
 			if err != nil {
 				return "", err
 			}
+			a.recordQueryCost(sourceAI, billingProject, 0, result)
 			var b strings.Builder
 			fmt.Fprintf(&b, "Columns: %s\n", strings.Join(result.Columns, ", "))
 			fmt.Fprintf(&b, "Rows: %d | %.2f MB processed\n", result.RowCount, float64(result.BytesProcessed)/(1024*1024))
@@ -15,10 +15,37 @@ const maxRows = 10000
 
 type QueryResult struct {
 	Columns        []string
+	ColumnTypes    []string // BigQuery type per column, e.g. "INTEGER" or "ARRAY<STRING>"
 	Rows           [][]string
+	Nulls          [][]bool // per row, the cells that are SQL NULL; nil for rows without any
 	RowCount       int64
+	TotalRows      uint64 // rows in the full result; may exceed RowCount when truncated
 	Duration       time.Duration
 	BytesProcessed int64
+
+	// SQL is the query text that produced the result.
+	SQL string
+
+	// Stats holds the job's execution statistics; nil when unavailable.
+	Stats *QueryStats
+
+	// Job reference, used to re-read the full result set (e.g. for export).
+	ProjectID string
+	JobID     string
+	Location  string
+}
+
+// RowNulls returns the NULL cells of row i, or nil when it has none.
+func (r *QueryResult) RowNulls(i int) []bool {
+	if i < len(r.Nulls) {
+		return r.Nulls[i]
+	}
+	return nil
+}
+
+// Truncated reports whether Rows holds only part of the job's result.
+func (r *QueryResult) Truncated() bool {
+	return r.TotalRows > uint64(r.RowCount)
 }
 
 type TableSchema struct {
@@ -188,7 +215,7 @@ func (c *Client) GetTableSchema(ctx context.Context, projectID, datasetID, table
 	return schema, nil
 }
 
-func (c *Client) RunQuery(ctx context.Context, projectID, sqlText string) (*QueryResult, error) {
+func (c *Client) RunQuery(ctx context.Context, projectID, sqlText string, opts QueryOptions) (*QueryResult, error) {
 	cl, err := c.getClient(projectID)
 	if err != nil {
 		return nil, err
@@ -196,6 +223,7 @@ func (c *Client) RunQuery(ctx context.Context, projectID, sqlText string) (*Quer
 
 	start := time.Now()
 	q := cl.Query(sqlText)
+	opts.apply(q)
 	job, err := q.Run(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("run query: %w", err)
@@ -217,20 +245,26 @@ func (c *Client) RunQuery(ctx context.Context, projectID, sqlText string) (*Quer
 	}
 
 	result := &QueryResult{
-		Duration: dur,
+		Duration:  dur,
+		SQL:       sqlText,
+		ProjectID: job.ProjectID(),
+		JobID:     job.ID(),
+		Location:  job.Location(),
 	}
 	if status.Statistics != nil {
 		result.BytesProcessed = status.Statistics.TotalBytesProcessed
+		result.Stats = queryStatsFrom(status.Statistics)
 	}
 
-	// Extract column names from schema
-	if it.Schema != nil {
-		for _, f := range it.Schema {
-			result.Columns = append(result.Columns, f.Name)
-		}
+	if err := readResultRows(it, result); err != nil {
+		return nil, err
 	}
+	return result, nil
+}
 
-	// Read rows
+// readResultRows reads up to maxRows rows and the schema from it into
+// result.
+func readResultRows(it *bigquery.RowIterator, result *QueryResult) error {
 	for result.RowCount < maxRows {
 		var row []bigquery.Value
 		err := it.Next(&row)
@@ -238,19 +272,50 @@ func (c *Client) RunQuery(ctx context.Context, projectID, sqlText string) (*Quer
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("read row: %w", err)
-		}
-		strRow := make([]string, len(row))
-		for i, v := range row {
-			if v == nil {
-				strRow[i] = "NULL"
-			} else {
-				strRow[i] = fmt.Sprintf("%v", v)
-			}
+			return fmt.Errorf("read row: %w", err)
 		}
-		result.Rows = append(result.Rows, strRow)
+		values, nulls := formatRow(row, it.Schema)
+		result.Rows = append(result.Rows, values)
+		result.Nulls = append(result.Nulls, nulls)
 		result.RowCount++
 	}
 
-	return result, nil
+	// The schema is populated once the first page has been fetched.
+	for _, f := range it.Schema {
+		result.Columns = append(result.Columns, f.Name)
+		result.ColumnTypes = append(result.ColumnTypes, fieldType(f))
+	}
+	result.TotalRows = it.TotalRows
+	return nil
+}
+
+// ReadJobRows streams every row of a finished query job to fn, in the same
+// display form as RunQuery, with the row's NULL cells marked in nulls. It
+// stops at the first error returned by fn.
+func (c *Client) ReadJobRows(ctx context.Context, projectID, jobID, location string, fn func(row []string, nulls []bool) error) error {
+	cl, err := c.getClient(projectID)
+	if err != nil {
+		return err
+	}
+	job, err := cl.JobFromIDLocation(ctx, jobID, location)
+	if err != nil {
+		return fmt.Errorf("get job: %w", err)
+	}
+	it, err := job.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("read results: %w", err)
+	}
+	for {
+		var row []bigquery.Value
+		err := it.Next(&row)
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read row: %w", err)
+		}
+		if err := fn(formatRow(row, it.Schema)); err != nil {
+			return err
+		}
+	}
 }
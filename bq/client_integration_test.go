@@ -156,7 +156,7 @@ func TestGetTableSchema(t *testing.T) {
 }
 
 func TestRunQuery(t *testing.T) {
-	result, err := testClient.RunQuery(context.Background(), projectID, "SELECT 1 AS num, 'hello' AS greeting")
+	result, err := testClient.RunQuery(context.Background(), projectID, "SELECT 1 AS num, 'hello' AS greeting", QueryOptions{})
 	if err != nil {
 		t.Fatalf("RunQuery: %v", err)
 	}
@@ -177,7 +177,7 @@ func TestRunQuery(t *testing.T) {
 }
 
 func TestRunQueryFromTable(t *testing.T) {
-	result, err := testClient.RunQuery(context.Background(), projectID, "SELECT * FROM test_dataset.users ORDER BY id")
+	result, err := testClient.RunQuery(context.Background(), projectID, "SELECT * FROM test_dataset.users ORDER BY id", QueryOptions{})
 	if err != nil {
 		t.Fatalf("RunQuery: %v", err)
 	}
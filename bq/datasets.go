@@ -0,0 +1,159 @@
+package bq
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// DatasetSpec describes a dataset to create, or the editable part of an
+// existing one.
+type DatasetSpec struct {
+	DatasetID   string
+	Location    string // e.g. "US", "EU" or "europe-west1"; fixed once created
+	Description string
+	Labels      map[string]string
+
+	// DefaultTableExpiration applies to tables created in the dataset
+	// afterwards; zero means they do not expire.
+	DefaultTableExpiration time.Duration
+}
+
+// DatasetInfo is a dataset's metadata and access list.
+type DatasetInfo struct {
+	DatasetSpec
+	Created  time.Time
+	Modified time.Time
+	Access   []DatasetAccess
+	ETag     string // version of the metadata, for UpdateDataset
+}
+
+// DatasetAccess is one entry of a dataset's access list.
+type DatasetAccess struct {
+	Role       string // e.g. OWNER, WRITER, READER, or an IAM role
+	EntityType string // e.g. "user", "group", "domain", "special group", "authorized view"
+	Entity     string
+}
+
+// CreateDataset creates a dataset in projectID.
+func (c *Client) CreateDataset(ctx context.Context, projectID string, spec DatasetSpec) error {
+	cl, err := c.getAnyClient(projectID)
+	if err != nil {
+		return err
+	}
+	md := &bigquery.DatasetMetadata{
+		Location:               spec.Location,
+		Description:            spec.Description,
+		Labels:                 SanitizeLabels(spec.Labels),
+		DefaultTableExpiration: spec.DefaultTableExpiration,
+	}
+	if err := cl.DatasetInProject(projectID, spec.DatasetID).Create(ctx, md); err != nil {
+		return fmt.Errorf("create dataset: %w", err)
+	}
+	return nil
+}
+
+// GetDatasetInfo returns a dataset's metadata and access list. Access
+// entries are sorted by role, then entity.
+func (c *Client) GetDatasetInfo(ctx context.Context, projectID, datasetID string) (*DatasetInfo, error) {
+	cl, err := c.getAnyClient(projectID)
+	if err != nil {
+		return nil, err
+	}
+	md, err := cl.DatasetInProject(projectID, datasetID).Metadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("dataset metadata: %w", err)
+	}
+	info := &DatasetInfo{
+		DatasetSpec: DatasetSpec{
+			DatasetID:              datasetID,
+			Location:               md.Location,
+			Description:            md.Description,
+			Labels:                 md.Labels,
+			DefaultTableExpiration: md.DefaultTableExpiration,
+		},
+		Created:  md.CreationTime,
+		Modified: md.LastModifiedTime,
+		ETag:     md.ETag,
+	}
+	for _, a := range md.Access {
+		if a != nil {
+			info.Access = append(info.Access, datasetAccess(a))
+		}
+	}
+	sort.SliceStable(info.Access, func(i, j int) bool {
+		if info.Access[i].Role != info.Access[j].Role {
+			return info.Access[i].Role < info.Access[j].Role
+		}
+		return info.Access[i].Entity < info.Access[j].Entity
+	})
+	return info, nil
+}
+
+func datasetAccess(a *bigquery.AccessEntry) DatasetAccess {
+	out := DatasetAccess{Role: string(a.Role), Entity: a.Entity}
+	switch a.EntityType {
+	case bigquery.DomainEntity:
+		out.EntityType = "domain"
+	case bigquery.GroupEmailEntity:
+		out.EntityType = "group"
+	case bigquery.UserEmailEntity:
+		out.EntityType = "user"
+	case bigquery.SpecialGroupEntity:
+		out.EntityType = "special group"
+	case bigquery.IAMMemberEntity:
+		out.EntityType = "IAM member"
+	case bigquery.ViewEntity:
+		out.EntityType = "authorized view"
+		if a.View != nil {
+			out.Entity = a.View.ProjectID + "." + a.View.DatasetID + "." + a.View.TableID
+		}
+	case bigquery.RoutineEntity:
+		out.EntityType = "authorized routine"
+		if a.Routine != nil {
+			out.Entity = a.Routine.ProjectID + "." + a.Routine.DatasetID + "." + a.Routine.RoutineID
+		}
+	case bigquery.DatasetEntity:
+		out.EntityType = "authorized dataset"
+		if a.Dataset != nil && a.Dataset.Dataset != nil {
+			out.Entity = a.Dataset.Dataset.ProjectID + "." + a.Dataset.Dataset.DatasetID
+		}
+	}
+	if a.Condition != nil && a.Condition.Expression != "" {
+		out.Entity += " if " + a.Condition.Expression
+	}
+	return out
+}
+
+// UpdateDataset replaces a dataset's description, labels and default table
+// expiration; DatasetID and Location of spec are ignored. from is the
+// metadata the edit started from, as returned by GetDatasetInfo; the update
+// fails if the dataset changed since, rather than overwrite a concurrent
+// edit.
+func (c *Client) UpdateDataset(ctx context.Context, projectID, datasetID string, from *DatasetInfo, spec DatasetSpec) error {
+	cl, err := c.getAnyClient(projectID)
+	if err != nil {
+		return err
+	}
+	ds := cl.DatasetInProject(projectID, datasetID)
+	upd := bigquery.DatasetMetadataToUpdate{
+		Description:            spec.Description,
+		DefaultTableExpiration: spec.DefaultTableExpiration,
+	}
+	labels := SanitizeLabels(spec.Labels)
+	for k := range from.Labels {
+		if _, ok := labels[k]; !ok {
+			upd.DeleteLabel(k)
+		}
+	}
+	for k, v := range labels {
+		upd.SetLabel(k, v)
+	}
+	if _, err := ds.Update(ctx, upd, from.ETag); err != nil {
+		return fmt.Errorf("update dataset: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package bq
+
+import (
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestDatasetAccess(t *testing.T) {
+	for _, tc := range []struct {
+		entry *bigquery.AccessEntry
+		want  DatasetAccess
+	}{
+		{
+			&bigquery.AccessEntry{Role: bigquery.OwnerRole, EntityType: bigquery.UserEmailEntity, Entity: "me@example.com"},
+			DatasetAccess{"OWNER", "user", "me@example.com"},
+		},
+		{
+			&bigquery.AccessEntry{Role: bigquery.ReaderRole, EntityType: bigquery.SpecialGroupEntity, Entity: "projectReaders"},
+			DatasetAccess{"READER", "special group", "projectReaders"},
+		},
+		{
+			&bigquery.AccessEntry{EntityType: bigquery.ViewEntity, View: &bigquery.Table{ProjectID: "p", DatasetID: "ds", TableID: "v"}},
+			DatasetAccess{"", "authorized view", "p.ds.v"},
+		},
+		{
+			&bigquery.AccessEntry{EntityType: bigquery.DatasetEntity, Dataset: &bigquery.DatasetAccessEntry{
+				Dataset: &bigquery.Dataset{ProjectID: "p", DatasetID: "shared"},
+			}},
+			DatasetAccess{"", "authorized dataset", "p.shared"},
+		},
+		{
+			&bigquery.AccessEntry{Role: "roles/bigquery.dataViewer", EntityType: bigquery.GroupEmailEntity, Entity: "team@example.com",
+				Condition: &bigquery.Expr{Expression: "request.time < timestamp('2030-01-01T00:00:00Z')"}},
+			DatasetAccess{"roles/bigquery.dataViewer", "group", "team@example.com if request.time < timestamp('2030-01-01T00:00:00Z')"},
+		},
+	} {
+		if got := datasetAccess(tc.entry); got != tc.want {
+			t.Errorf("datasetAccess(%+v) = %+v, want %+v", tc.entry, got, tc.want)
+		}
+	}
+}
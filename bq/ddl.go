@@ -0,0 +1,352 @@
+package bq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// errNoDDL is returned by buildDDL for tables it cannot describe from
+// metadata alone, such as external tables and snapshots.
+var errNoDDL = errors.New("table kind has no DDL rebuilt from metadata")
+
+// TableDDL returns a CREATE statement that recreates a table, view or
+// materialized view. It is rebuilt from the table's metadata; for other
+// kinds, such as external tables and snapshots, it is read from
+// INFORMATION_SCHEMA.TABLES with a query run in projectID, whose cost is
+// returned. The cost is nil when no query ran.
+func (c *Client) TableDDL(ctx context.Context, projectID, datasetID, tableID string, opts QueryOptions) (string, *QueryCost, error) {
+	cl, err := c.getAnyClient(projectID)
+	if err != nil {
+		return "", nil, err
+	}
+	md, err := cl.DatasetInProject(projectID, datasetID).Table(tableID).Metadata(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("table metadata: %w", err)
+	}
+	ddl, err := buildDDL(projectID+"."+datasetID+"."+tableID, md)
+	if !errors.Is(err, errNoDDL) {
+		return ddl, nil, err
+	}
+
+	if cl, err = c.getClient(projectID); err != nil {
+		return "", nil, err
+	}
+	q := cl.Query(fmt.Sprintf("SELECT ddl FROM `%s.%s`.INFORMATION_SCHEMA.TABLES WHERE table_name = @table", projectID, datasetID))
+	q.Parameters = []bigquery.QueryParameter{{Name: "table", Value: tableID}}
+	opts.apply(q)
+	it, cost, err := runQueryJob(ctx, q)
+	if err != nil {
+		return "", cost, fmt.Errorf("read ddl: %w", err)
+	}
+	var row []bigquery.Value
+	if err := it.Next(&row); err == iterator.Done {
+		return "", cost, fmt.Errorf("no ddl for %s.%s.%s", projectID, datasetID, tableID)
+	} else if err != nil {
+		return "", cost, fmt.Errorf("read ddl: %w", err)
+	}
+	ddl, _ = row[0].(string)
+	return ddl, cost, nil
+}
+
+// buildDDL renders a CREATE TABLE, CREATE VIEW or CREATE MATERIALIZED VIEW
+// statement for table (a fully qualified name without backticks) from its
+// metadata: schema with nested fields, modes, defaults and descriptions,
+// constraints, partitioning, clustering and options.
+func buildDDL(table string, md *bigquery.TableMetadata) (string, error) {
+	var b strings.Builder
+	name := "`" + table + "`"
+	switch md.Type {
+	case bigquery.RegularTable:
+		b.WriteString("CREATE TABLE " + name + "\n(\n")
+		var cols []string
+		for _, f := range md.Schema {
+			cols = append(cols, "  "+columnDDL(f, "  "))
+		}
+		cols = append(cols, constraintsDDL(md.TableConstraints)...)
+		b.WriteString(strings.Join(cols, ",\n"))
+		b.WriteString("\n)")
+		if md.DefaultCollation != "" {
+			b.WriteString("\nDEFAULT COLLATE " + sqlString(md.DefaultCollation))
+		}
+		writeLayoutDDL(&b, md)
+		writeOptionsDDL(&b, tableOptions(md))
+	case bigquery.ViewTable:
+		if md.UseLegacySQL {
+			return "", fmt.Errorf("%s is a legacy SQL view, which has no DDL", table)
+		}
+		b.WriteString("CREATE VIEW " + name)
+		writeOptionsDDL(&b, commonOptions(md))
+		b.WriteString("\nAS " + strings.TrimSpace(md.ViewQuery))
+	case bigquery.MaterializedView:
+		mv := md.MaterializedView
+		if mv == nil {
+			return "", errNoDDL
+		}
+		b.WriteString("CREATE MATERIALIZED VIEW " + name)
+		writeLayoutDDL(&b, md)
+		var opts []string
+		if !mv.EnableRefresh {
+			opts = append(opts, "enable_refresh=false")
+		} else if mv.RefreshInterval > 0 {
+			opts = append(opts, "refresh_interval_minutes="+formatFloat(mv.RefreshInterval.Minutes()))
+		}
+		if mv.AllowNonIncrementalDefinition {
+			opts = append(opts, "allow_non_incremental_definition=true")
+		}
+		if mv.MaxStaleness != nil {
+			opts = append(opts, "max_staleness="+intervalLiteral(mv.MaxStaleness))
+		}
+		writeOptionsDDL(&b, append(opts, commonOptions(md)...))
+		b.WriteString("\nAS " + strings.TrimSpace(mv.Query))
+	default:
+		return "", errNoDDL
+	}
+	b.WriteString(";\n")
+	return b.String(), nil
+}
+
+// columnDDL renders a column or STRUCT field definition; indent is the
+// indentation of the line it starts on.
+func columnDDL(f *bigquery.FieldSchema, indent string) string {
+	s := quoteIdent(f.Name) + " " + typeDDL(f, indent)
+	if f.Collation != "" {
+		s += " COLLATE " + sqlString(f.Collation)
+	}
+	if f.DefaultValueExpression != "" {
+		s += " DEFAULT " + f.DefaultValueExpression
+	}
+	if f.Required && !f.Repeated {
+		s += " NOT NULL"
+	}
+	if f.Description != "" {
+		s += " OPTIONS(description=" + sqlString(f.Description) + ")"
+	}
+	return s
+}
+
+// typeDDL renders a field's type in standard SQL, e.g. INT64, STRING(10),
+// ARRAY<STRUCT<...>>, with each STRUCT field on its own line.
+func typeDDL(f *bigquery.FieldSchema, indent string) string {
+	var t string
+	switch f.Type {
+	case bigquery.IntegerFieldType:
+		t = "INT64"
+	case bigquery.FloatFieldType:
+		t = "FLOAT64"
+	case bigquery.BooleanFieldType:
+		t = "BOOL"
+	case bigquery.RecordFieldType:
+		fields := make([]string, len(f.Schema))
+		for i, sub := range f.Schema {
+			fields[i] = indent + "  " + columnDDL(sub, indent+"  ")
+		}
+		t = "STRUCT<\n" + strings.Join(fields, ",\n") + "\n" + indent + ">"
+	case bigquery.RangeFieldType:
+		t = "RANGE"
+		if f.RangeElementType != nil {
+			t += "<" + string(f.RangeElementType.Type) + ">"
+		}
+	case bigquery.StringFieldType, bigquery.BytesFieldType:
+		t = string(f.Type)
+		if f.MaxLength > 0 {
+			t += fmt.Sprintf("(%d)", f.MaxLength)
+		}
+	case bigquery.NumericFieldType, bigquery.BigNumericFieldType:
+		t = string(f.Type)
+		if f.Precision > 0 && f.Scale > 0 {
+			t += fmt.Sprintf("(%d, %d)", f.Precision, f.Scale)
+		} else if f.Precision > 0 {
+			t += fmt.Sprintf("(%d)", f.Precision)
+		}
+	default:
+		t = string(f.Type)
+	}
+	if f.Repeated {
+		return "ARRAY<" + t + ">"
+	}
+	return t
+}
+
+func constraintsDDL(tc *bigquery.TableConstraints) []string {
+	if tc == nil {
+		return nil
+	}
+	var out []string
+	if pk := tc.PrimaryKey; pk != nil && len(pk.Columns) > 0 {
+		out = append(out, "  PRIMARY KEY ("+identList(pk.Columns)+") NOT ENFORCED")
+	}
+	for _, fk := range tc.ForeignKeys {
+		if fk == nil || fk.ReferencedTable == nil {
+			continue
+		}
+		var from, to []string
+		for _, ref := range fk.ColumnReferences {
+			from = append(from, ref.ReferencingColumn)
+			to = append(to, ref.ReferencedColumn)
+		}
+		s := "  "
+		if fk.Name != "" {
+			s += "CONSTRAINT " + quoteIdent(fk.Name) + " "
+		}
+		rt := fk.ReferencedTable
+		s += fmt.Sprintf("FOREIGN KEY (%s) REFERENCES `%s.%s.%s`(%s) NOT ENFORCED",
+			identList(from), rt.ProjectID, rt.DatasetID, rt.TableID, identList(to))
+		out = append(out, s)
+	}
+	return out
+}
+
+// writeLayoutDDL writes the PARTITION BY and CLUSTER BY clauses.
+func writeLayoutDDL(b *strings.Builder, md *bigquery.TableMetadata) {
+	if tp := md.TimePartitioning; tp != nil {
+		unit := string(tp.Type)
+		if unit == "" {
+			unit = string(bigquery.DayPartitioningType)
+		}
+		var expr string
+		switch {
+		case tp.Field == "" && unit == string(bigquery.DayPartitioningType):
+			expr = "_PARTITIONDATE"
+		case tp.Field == "":
+			expr = "TIMESTAMP_TRUNC(_PARTITIONTIME, " + unit + ")"
+		default:
+			col := quoteIdent(tp.Field)
+			switch schemaFieldType(md.Schema, tp.Field) {
+			case bigquery.DateFieldType:
+				expr = col
+				if unit != string(bigquery.DayPartitioningType) {
+					expr = "DATE_TRUNC(" + col + ", " + unit + ")"
+				}
+			case bigquery.DateTimeFieldType:
+				expr = "DATETIME_TRUNC(" + col + ", " + unit + ")"
+			default:
+				expr = "TIMESTAMP_TRUNC(" + col + ", " + unit + ")"
+			}
+		}
+		b.WriteString("\nPARTITION BY " + expr)
+	} else if rp := md.RangePartitioning; rp != nil && rp.Range != nil {
+		fmt.Fprintf(b, "\nPARTITION BY RANGE_BUCKET(%s, GENERATE_ARRAY(%d, %d, %d))",
+			quoteIdent(rp.Field), rp.Range.Start, rp.Range.End, rp.Range.Interval)
+	}
+	if cl := md.Clustering; cl != nil && len(cl.Fields) > 0 {
+		b.WriteString("\nCLUSTER BY " + identList(cl.Fields))
+	}
+}
+
+func schemaFieldType(schema bigquery.Schema, name string) bigquery.FieldType {
+	for _, f := range schema {
+		if strings.EqualFold(f.Name, name) {
+			return f.Type
+		}
+	}
+	return ""
+}
+
+// tableOptions are the OPTIONS of a CREATE TABLE statement.
+func tableOptions(md *bigquery.TableMetadata) []string {
+	var opts []string
+	if tp := md.TimePartitioning; tp != nil && tp.Expiration > 0 {
+		opts = append(opts, "partition_expiration_days="+formatFloat(tp.Expiration.Hours()/24))
+	}
+	if md.RequirePartitionFilter || (md.TimePartitioning != nil && md.TimePartitioning.RequirePartitionFilter) {
+		opts = append(opts, "require_partition_filter=true")
+	}
+	if md.MaxStaleness != nil {
+		opts = append(opts, "max_staleness="+intervalLiteral(md.MaxStaleness))
+	}
+	if ec := md.EncryptionConfig; ec != nil && ec.KMSKeyName != "" {
+		opts = append(opts, "kms_key_name="+sqlString(ec.KMSKeyName))
+	}
+	return append(opts, commonOptions(md)...)
+}
+
+// commonOptions are the OPTIONS shared by tables and views.
+func commonOptions(md *bigquery.TableMetadata) []string {
+	var opts []string
+	if !md.ExpirationTime.IsZero() {
+		opts = append(opts, `expiration_timestamp=TIMESTAMP "`+md.ExpirationTime.UTC().Format("2006-01-02 15:04:05")+` UTC"`)
+	}
+	if md.Name != "" {
+		opts = append(opts, "friendly_name="+sqlString(md.Name))
+	}
+	if md.Description != "" {
+		opts = append(opts, "description="+sqlString(md.Description))
+	}
+	if len(md.Labels) > 0 {
+		keys := make([]string, 0, len(md.Labels))
+		for k := range md.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = "(" + sqlString(k) + ", " + sqlString(md.Labels[k]) + ")"
+		}
+		opts = append(opts, "labels=["+strings.Join(pairs, ", ")+"]")
+	}
+	return opts
+}
+
+func writeOptionsDDL(b *strings.Builder, opts []string) {
+	if len(opts) == 0 {
+		return
+	}
+	b.WriteString("\nOPTIONS(\n  " + strings.Join(opts, ",\n  ") + "\n)")
+}
+
+func intervalLiteral(iv *bigquery.IntervalValue) string {
+	return `INTERVAL "` + iv.String() + `" YEAR TO SECOND`
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// sqlString quotes s as a SQL string literal. Go's escapes are a subset of
+// the ones BigQuery accepts.
+func sqlString(s string) string {
+	return strconv.Quote(s)
+}
+
+var plainIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedWords are the GoogleSQL reserved keywords, which must be quoted
+// when used as names.
+var reservedWords = func() map[string]bool {
+	words := map[string]bool{}
+	for _, w := range strings.Fields(`ALL AND ANY ARRAY AS ASC ASSERT_ROWS_MODIFIED AT BETWEEN BY CASE CAST
+		COLLATE CONTAINS CREATE CROSS CUBE CURRENT DEFAULT DEFINE DESC DISTINCT ELSE END ENUM ESCAPE
+		EXCEPT EXCLUDE EXISTS EXTRACT FALSE FETCH FOLLOWING FOR FROM FULL GROUP GROUPING GROUPS HASH
+		HAVING IF IGNORE IN INNER INTERSECT INTERVAL INTO IS JOIN LATERAL LEFT LIKE LIMIT LOOKUP MERGE
+		NATURAL NEW NO NOT NULL NULLS OF ON OR ORDER OUTER OVER PARTITION PRECEDING PROTO QUALIFY RANGE
+		RECURSIVE RESPECT RIGHT ROLLUP ROWS SELECT SET SOME STRUCT TABLESAMPLE THEN TO TREAT TRUE
+		UNBOUNDED UNION UNNEST USING WHEN WHERE WINDOW WITH WITHIN`) {
+		words[w] = true
+	}
+	return words
+}()
+
+// quoteIdent returns name as is when it is a plain identifier, and quoted
+// with backticks otherwise.
+func quoteIdent(name string) string {
+	if plainIdent.MatchString(name) && !reservedWords[strings.ToUpper(name)] {
+		return name
+	}
+	return "`" + strings.ReplaceAll(name, "`", "\\`") + "`"
+}
+
+func identList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = quoteIdent(n)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,161 @@
+package bq
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestBuildDDLTable(t *testing.T) {
+	md := &bigquery.TableMetadata{
+		Type: bigquery.RegularTable,
+		Schema: bigquery.Schema{
+			{Name: "id", Type: bigquery.IntegerFieldType, Required: true, Description: `the "key"`},
+			{Name: "code", Type: bigquery.StringFieldType, MaxLength: 3, Collation: "und:ci"},
+			{Name: "amount", Type: bigquery.NumericFieldType, Precision: 10, Scale: 2},
+			{Name: "created", Type: bigquery.TimestampFieldType, DefaultValueExpression: "CURRENT_TIMESTAMP()"},
+			{Name: "tags", Type: bigquery.StringFieldType, Repeated: true},
+			{Name: "order", Type: bigquery.BooleanFieldType},
+			{Name: "items", Type: bigquery.RecordFieldType, Repeated: true, Schema: bigquery.Schema{
+				{Name: "sku", Type: bigquery.StringFieldType, Required: true},
+				{Name: "price", Type: bigquery.FloatFieldType},
+			}},
+		},
+		TableConstraints: &bigquery.TableConstraints{
+			PrimaryKey: &bigquery.PrimaryKey{Columns: []string{"id"}},
+			ForeignKeys: []*bigquery.ForeignKey{{
+				Name:             "fk_code",
+				ReferencedTable:  &bigquery.Table{ProjectID: "p", DatasetID: "ds", TableID: "codes"},
+				ColumnReferences: []*bigquery.ColumnReference{{ReferencingColumn: "code", ReferencedColumn: "code"}},
+			}},
+		},
+		TimePartitioning:       &bigquery.TimePartitioning{Type: bigquery.MonthPartitioningType, Field: "created", Expiration: 90 * 24 * time.Hour},
+		RequirePartitionFilter: true,
+		Clustering:             &bigquery.Clustering{Fields: []string{"code", "order"}},
+		Description:            "Orders",
+		Labels:                 map[string]string{"team": "sales", "env": "prod"},
+		ExpirationTime:         time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	got, err := buildDDL("p.ds.orders", md)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "CREATE TABLE `p.ds.orders`\n(\n" +
+		"  id INT64 NOT NULL OPTIONS(description=\"the \\\"key\\\"\"),\n" +
+		"  code STRING(3) COLLATE \"und:ci\",\n" +
+		"  amount NUMERIC(10, 2),\n" +
+		"  created TIMESTAMP DEFAULT CURRENT_TIMESTAMP(),\n" +
+		"  tags ARRAY<STRING>,\n" +
+		"  `order` BOOL,\n" +
+		"  items ARRAY<STRUCT<\n" +
+		"    sku STRING NOT NULL,\n" +
+		"    price FLOAT64\n" +
+		"  >>,\n" +
+		"  PRIMARY KEY (id) NOT ENFORCED,\n" +
+		"  CONSTRAINT fk_code FOREIGN KEY (code) REFERENCES `p.ds.codes`(code) NOT ENFORCED\n" +
+		")\n" +
+		"PARTITION BY TIMESTAMP_TRUNC(created, MONTH)\n" +
+		"CLUSTER BY code, `order`\n" +
+		"OPTIONS(\n" +
+		"  partition_expiration_days=90,\n" +
+		"  require_partition_filter=true,\n" +
+		"  expiration_timestamp=TIMESTAMP \"2030-01-02 03:04:05 UTC\",\n" +
+		"  description=\"Orders\",\n" +
+		"  labels=[(\"env\", \"prod\"), (\"team\", \"sales\")]\n" +
+		");\n"
+	if got != want {
+		t.Errorf("buildDDL =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestBuildDDLPartitioning(t *testing.T) {
+	schema := bigquery.Schema{
+		{Name: "d", Type: bigquery.DateFieldType},
+		{Name: "dt", Type: bigquery.DateTimeFieldType},
+		{Name: "n", Type: bigquery.IntegerFieldType},
+	}
+	for _, tc := range []struct {
+		md   bigquery.TableMetadata
+		want string
+	}{
+		{bigquery.TableMetadata{TimePartitioning: &bigquery.TimePartitioning{}}, "PARTITION BY _PARTITIONDATE"},
+		{bigquery.TableMetadata{TimePartitioning: &bigquery.TimePartitioning{Type: bigquery.HourPartitioningType}},
+			"PARTITION BY TIMESTAMP_TRUNC(_PARTITIONTIME, HOUR)"},
+		{bigquery.TableMetadata{TimePartitioning: &bigquery.TimePartitioning{Field: "d"}}, "PARTITION BY d;"},
+		{bigquery.TableMetadata{TimePartitioning: &bigquery.TimePartitioning{Type: bigquery.YearPartitioningType, Field: "d"}},
+			"PARTITION BY DATE_TRUNC(d, YEAR)"},
+		{bigquery.TableMetadata{TimePartitioning: &bigquery.TimePartitioning{Field: "dt"}}, "PARTITION BY DATETIME_TRUNC(dt, DAY)"},
+		{bigquery.TableMetadata{RangePartitioning: &bigquery.RangePartitioning{Field: "n",
+			Range: &bigquery.RangePartitioningRange{Start: 0, End: 100, Interval: 10}}},
+			"PARTITION BY RANGE_BUCKET(n, GENERATE_ARRAY(0, 100, 10))"},
+	} {
+		tc.md.Type = bigquery.RegularTable
+		tc.md.Schema = schema
+		got, err := buildDDL("p.ds.t", &tc.md)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(got, tc.want) {
+			t.Errorf("buildDDL = %s, want it to contain %q", got, tc.want)
+		}
+	}
+}
+
+func TestBuildDDLViews(t *testing.T) {
+	got, err := buildDDL("p.ds.v", &bigquery.TableMetadata{
+		Type:        bigquery.ViewTable,
+		ViewQuery:   "SELECT 1 AS x\n",
+		Description: "One",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "CREATE VIEW `p.ds.v`\nOPTIONS(\n  description=\"One\"\n)\nAS SELECT 1 AS x;\n"; got != want {
+		t.Errorf("view DDL =\n%s\nwant\n%s", got, want)
+	}
+
+	got, err = buildDDL("p.ds.mv", &bigquery.TableMetadata{
+		Type:       bigquery.MaterializedView,
+		Clustering: &bigquery.Clustering{Fields: []string{"k"}},
+		MaterializedView: &bigquery.MaterializedViewDefinition{
+			Query:           "SELECT k, COUNT(*) AS n FROM `p.ds.t` GROUP BY k",
+			EnableRefresh:   true,
+			RefreshInterval: 30 * time.Minute,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "CREATE MATERIALIZED VIEW `p.ds.mv`\nCLUSTER BY k\nOPTIONS(\n  refresh_interval_minutes=30\n)\n" +
+		"AS SELECT k, COUNT(*) AS n FROM `p.ds.t` GROUP BY k;\n"
+	if got != want {
+		t.Errorf("materialized view DDL =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := buildDDL("p.ds.legacy", &bigquery.TableMetadata{Type: bigquery.ViewTable, UseLegacySQL: true}); err == nil {
+		t.Error("legacy SQL view: expected an error")
+	}
+	for _, typ := range []bigquery.TableType{bigquery.ExternalTable, bigquery.Snapshot} {
+		if _, err := buildDDL("p.ds.x", &bigquery.TableMetadata{Type: typ}); !errors.Is(err, errNoDDL) {
+			t.Errorf("%s: err = %v, want errNoDDL", typ, err)
+		}
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	for in, want := range map[string]string{
+		"name":       "name",
+		"_x1":        "_x1",
+		"select":     "`select`",
+		"1st":        "`1st`",
+		"with space": "`with space`",
+		"a`b":        "`a\\`b`",
+	} {
+		if got := quoteIdent(in); got != want {
+			t.Errorf("quoteIdent(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
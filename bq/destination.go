@@ -0,0 +1,180 @@
+package bq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+)
+
+// Write dispositions for SaveJobToTable and CopyTable.
+const (
+	WriteTruncate = "WRITE_TRUNCATE" // replace the table's contents
+	WriteAppend   = "WRITE_APPEND"   // add rows to the table
+	WriteEmpty    = "WRITE_EMPTY"    // fail unless the table is empty or missing
+)
+
+// TableDestination describes where SaveJobToTable writes a query result.
+type TableDestination struct {
+	ProjectID string
+	DatasetID string
+	TableID   string
+
+	WriteDisposition string        // WriteTruncate, WriteAppend or WriteEmpty
+	Expiration       time.Duration // table expires this long after the job; 0 keeps it forever
+
+	// Partitioning applies when the table is created. PartitionField is a
+	// DATE/TIMESTAMP/DATETIME column, or "_PARTITIONTIME" for ingestion-time
+	// partitioning; empty means unpartitioned.
+	PartitionField string
+	PartitionType  string // "DAY", "HOUR", "MONTH" or "YEAR"; defaults to DAY
+}
+
+// FullName returns the table as project.dataset.table.
+func (d TableDestination) FullName() string {
+	return fmt.Sprintf("%s.%s.%s", d.ProjectID, d.DatasetID, d.TableID)
+}
+
+// TableWriteResult summarizes a finished SaveJobToTable job.
+type TableWriteResult struct {
+	JobID     string
+	TotalRows uint64 // rows in the destination table after the job
+	Duration  time.Duration
+}
+
+// SaveJobToTable copies the result of a finished query job to dst, so the
+// saved rows are exactly those the job returned and the query is not billed
+// again. Partitioning applies only when dst does not exist yet; the table is
+// then created with the result's schema before the copy. onStart, if set,
+// receives the copy job's ID as soon as it is submitted.
+func (c *Client) SaveJobToTable(ctx context.Context, projectID, jobID, location string, dst TableDestination, labels map[string]string, onStart func(jobID string)) (*TableWriteResult, error) {
+	cl, err := c.getClient(projectID)
+	if err != nil {
+		return nil, err
+	}
+	queryJob, err := cl.JobFromIDLocation(ctx, jobID, location)
+	if err != nil {
+		return nil, fmt.Errorf("get job: %w", err)
+	}
+	cfg, err := queryJob.Config()
+	if err != nil {
+		return nil, fmt.Errorf("job config: %w", err)
+	}
+	qc, ok := cfg.(*bigquery.QueryConfig)
+	if !ok || qc.Dst == nil {
+		return nil, fmt.Errorf("job %s has no result table", jobID)
+	}
+	src := cl.DatasetInProject(qc.Dst.ProjectID, qc.Dst.DatasetID).Table(qc.Dst.TableID)
+	table := cl.DatasetInProject(dst.ProjectID, dst.DatasetID).Table(dst.TableID)
+
+	start := time.Now()
+	if tp := timePartitioning(dst); tp != nil {
+		if err := createPartitioned(ctx, src, table, tp); err != nil {
+			return nil, err
+		}
+	}
+
+	copier := table.CopierFrom(src)
+	copier.CreateDisposition = bigquery.CreateIfNeeded
+	copier.WriteDisposition = writeDisposition(dst.WriteDisposition)
+	if labels := SanitizeLabels(labels); len(labels) > 0 {
+		copier.Labels = labels
+	}
+	job, err := copier.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("copy results: %w", err)
+	}
+	if onStart != nil {
+		onStart(job.ID())
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("wait copy: %w", err)
+	}
+	if status.Err() != nil {
+		return nil, fmt.Errorf("copy error: %w", status.Err())
+	}
+
+	result := &TableWriteResult{
+		JobID:    job.ID(),
+		Duration: time.Since(start),
+	}
+	var md *bigquery.TableMetadata
+	if dst.Expiration > 0 {
+		md, err = table.Update(ctx, bigquery.TableMetadataToUpdate{
+			ExpirationTime: time.Now().Add(dst.Expiration),
+		}, "")
+		if err != nil {
+			return nil, fmt.Errorf("set expiration: %w", err)
+		}
+	} else {
+		md, err = table.Metadata(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("table metadata: %w", err)
+		}
+	}
+	result.TotalRows = md.NumRows
+	return result, nil
+}
+
+// createPartitioned creates table with src's schema and partitioning tp,
+// unless table already exists.
+func createPartitioned(ctx context.Context, src, table *bigquery.Table, tp *bigquery.TimePartitioning) error {
+	if _, err := table.Metadata(ctx); err == nil {
+		return nil
+	} else if !isNotFound(err) {
+		return fmt.Errorf("table metadata: %w", err)
+	}
+	md, err := src.Metadata(ctx)
+	if err != nil {
+		return fmt.Errorf("result metadata: %w", err)
+	}
+	if err := table.Create(ctx, &bigquery.TableMetadata{Schema: md.Schema, TimePartitioning: tp}); err != nil {
+		return fmt.Errorf("create table: %w", err)
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
+
+func writeDisposition(d string) bigquery.TableWriteDisposition {
+	switch d {
+	case WriteTruncate:
+		return bigquery.WriteTruncate
+	case WriteAppend:
+		return bigquery.WriteAppend
+	}
+	return bigquery.WriteEmpty
+}
+
+// timePartitioning returns the partitioning of a table created at dst, or
+// nil for an unpartitioned one.
+func timePartitioning(dst TableDestination) *bigquery.TimePartitioning {
+	if dst.PartitionField == "" {
+		return nil
+	}
+	tp := &bigquery.TimePartitioning{Type: partitioningType(dst.PartitionType)}
+	if dst.PartitionField != "_PARTITIONTIME" {
+		tp.Field = dst.PartitionField
+	}
+	return tp
+}
+
+func partitioningType(t string) bigquery.TimePartitioningType {
+	switch t {
+	case "HOUR":
+		return bigquery.HourPartitioningType
+	case "MONTH":
+		return bigquery.MonthPartitioningType
+	case "YEAR":
+		return bigquery.YearPartitioningType
+	}
+	return bigquery.DayPartitioningType
+}
@@ -0,0 +1,151 @@
+package bq
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// fieldType returns the BigQuery type name of a result column, wrapping
+// repeated fields as ARRAY<T>.
+func fieldType(f *bigquery.FieldSchema) string {
+	t := string(f.Type)
+	if f.Repeated {
+		return "ARRAY<" + t + ">"
+	}
+	return t
+}
+
+// formatRow renders a result row as display strings, with SQL NULL as
+// "NULL". STRUCT and ARRAY values are rendered as JSON, using the schema for
+// field names and value types. nulls marks the NULL cells, so they can be
+// told apart from strings that read "NULL"; it is nil when there are none.
+func formatRow(row []bigquery.Value, schema bigquery.Schema) (out []string, nulls []bool) {
+	out = make([]string, len(row))
+	for i, v := range row {
+		if v == nil {
+			if nulls == nil {
+				nulls = make([]bool, len(row))
+			}
+			nulls[i] = true
+		}
+		if i < len(schema) {
+			out[i] = formatField(v, schema[i])
+		} else {
+			out[i] = formatScalar(v)
+		}
+	}
+	return out, nulls
+}
+
+func formatField(v bigquery.Value, f *bigquery.FieldSchema) string {
+	if v == nil {
+		return "NULL"
+	}
+	if f.Repeated || f.Type == bigquery.RecordFieldType {
+		var b bytes.Buffer
+		writeJSON(&b, v, f, f.Repeated)
+		return b.String()
+	}
+	return formatScalar(v)
+}
+
+// formatScalar renders a non-nested value the way it is shown in the grid.
+func formatScalar(v bigquery.Value) string {
+	switch x := v.(type) {
+	case nil:
+		return "NULL"
+	case *big.Rat:
+		// NUMERIC and BIGNUMERIC; %v would print a fraction like "1/3".
+		return bigquery.NumericString(x)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// writeJSON encodes a value of field f as JSON. repeated is set when v is
+// the array of a REPEATED field rather than one of its elements.
+func writeJSON(b *bytes.Buffer, v bigquery.Value, f *bigquery.FieldSchema, repeated bool) {
+	if v == nil {
+		b.WriteString("null")
+		return
+	}
+	if repeated {
+		items, ok := v.([]bigquery.Value)
+		if !ok {
+			writeJSONString(b, formatScalar(v))
+			return
+		}
+		b.WriteByte('[')
+		for i, item := range items {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeJSON(b, item, f, false)
+		}
+		b.WriteByte(']')
+		return
+	}
+
+	switch f.Type {
+	case bigquery.RecordFieldType:
+		fields, ok := v.([]bigquery.Value)
+		if !ok {
+			writeJSONString(b, formatScalar(v))
+			return
+		}
+		b.WriteByte('{')
+		for i, sub := range f.Schema {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeJSONString(b, sub.Name)
+			b.WriteByte(':')
+			var fv bigquery.Value
+			if i < len(fields) {
+				fv = fields[i]
+			}
+			writeJSON(b, fv, sub, sub.Repeated)
+		}
+		b.WriteByte('}')
+	case bigquery.IntegerFieldType:
+		if n, ok := v.(int64); ok {
+			b.WriteString(strconv.FormatInt(n, 10))
+			return
+		}
+		writeJSONString(b, formatScalar(v))
+	case bigquery.FloatFieldType:
+		if x, ok := v.(float64); ok && !math.IsNaN(x) && !math.IsInf(x, 0) {
+			b.WriteString(strconv.FormatFloat(x, 'g', -1, 64))
+			return
+		}
+		writeJSONString(b, formatScalar(v))
+	case bigquery.BooleanFieldType:
+		if x, ok := v.(bool); ok {
+			b.WriteString(strconv.FormatBool(x))
+			return
+		}
+		writeJSONString(b, formatScalar(v))
+	case bigquery.JSONFieldType:
+		if s, ok := v.(string); ok && json.Valid([]byte(s)) {
+			b.WriteString(s)
+			return
+		}
+		writeJSONString(b, formatScalar(v))
+	default:
+		writeJSONString(b, formatScalar(v))
+	}
+}
+
+// writeJSONString writes s as a JSON string without HTML escaping, so
+// values like "<a>" stay readable.
+func writeJSONString(b *bytes.Buffer, s string) {
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	b.Truncate(b.Len() - 1) // Encode appends a newline
+}
@@ -0,0 +1,83 @@
+package bq
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestFormatRow_Nested(t *testing.T) {
+	schema := bigquery.Schema{
+		{Name: "id", Type: bigquery.IntegerFieldType},
+		{Name: "tags", Type: bigquery.StringFieldType, Repeated: true},
+		{Name: "user", Type: bigquery.RecordFieldType, Schema: bigquery.Schema{
+			{Name: "name", Type: bigquery.StringFieldType},
+			{Name: "score", Type: bigquery.FloatFieldType},
+			{Name: "active", Type: bigquery.BooleanFieldType},
+			{Name: "meta", Type: bigquery.JSONFieldType},
+		}},
+		{Name: "events", Type: bigquery.RecordFieldType, Repeated: true, Schema: bigquery.Schema{
+			{Name: "n", Type: bigquery.IntegerFieldType},
+			{Name: "at", Type: bigquery.TimestampFieldType},
+		}},
+		{Name: "payload", Type: bigquery.JSONFieldType},
+	}
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	row := []bigquery.Value{
+		int64(7),
+		[]bigquery.Value{"a", "<b>"},
+		[]bigquery.Value{"ann", 1.5, true, `{"k":[1,2]}`},
+		[]bigquery.Value{
+			[]bigquery.Value{int64(1), at},
+			[]bigquery.Value{nil, nil},
+		},
+		`{"x":1}`,
+	}
+
+	got, nulls := formatRow(row, schema)
+	if nulls != nil {
+		t.Errorf("nulls = %v, want nil for a row without NULL", nulls)
+	}
+	want := []string{
+		"7",
+		`["a","<b>"]`,
+		`{"name":"ann","score":1.5,"active":true,"meta":{"k":[1,2]}}`,
+		`[{"n":1,"at":"2024-01-02 03:04:05 +0000 UTC"},{"n":null,"at":null}]`,
+		`{"x":1}`,
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("column %s = %s, want %s", schema[i].Name, got[i], want[i])
+		}
+	}
+}
+
+func TestFormatRow_Scalars(t *testing.T) {
+	schema := bigquery.Schema{
+		{Name: "n", Type: bigquery.NumericFieldType},
+		{Name: "s", Type: bigquery.StringFieldType},
+		{Name: "empty", Type: bigquery.StringFieldType, Repeated: true},
+	}
+	got, nulls := formatRow([]bigquery.Value{big.NewRat(1, 4), nil, []bigquery.Value{}}, schema)
+	want := []string{"0.250000000", "NULL", "[]"}
+	if !reflect.DeepEqual(nulls, []bool{false, true, false}) {
+		t.Errorf("nulls = %v, want only the STRING column", nulls)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("column %s = %q, want %q", schema[i].Name, got[i], want[i])
+		}
+	}
+}
+
+func TestFieldType(t *testing.T) {
+	if got := fieldType(&bigquery.FieldSchema{Type: bigquery.StringFieldType, Repeated: true}); got != "ARRAY<STRING>" {
+		t.Errorf("repeated STRING = %s", got)
+	}
+	if got := fieldType(&bigquery.FieldSchema{Type: bigquery.RecordFieldType}); got != "RECORD" {
+		t.Errorf("RECORD = %s", got)
+	}
+}
@@ -0,0 +1,236 @@
+package bq
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// JobInfo describes a job listed from INFORMATION_SCHEMA.JOBS, run by any
+// client.
+type JobInfo struct {
+	ProjectID     string
+	JobID         string
+	Location      string
+	User          string
+	JobType       string // QUERY, LOAD, EXTRACT, COPY
+	StatementType string
+	State         string // PENDING, RUNNING, DONE
+	Query         string
+
+	Created, Started, Ended time.Time
+
+	BytesProcessed int64
+	BytesBilled    int64
+	SlotMillis     int64
+	CacheHit       bool
+
+	ErrorReason  string
+	ErrorMessage string
+
+	// Destination table of a query job; for plain queries this is an
+	// anonymous table that holds the cached results for about a day.
+	DestProject, DestDataset, DestTable string
+}
+
+// Failed reports whether the job finished with an error.
+func (j JobInfo) Failed() bool { return j.ErrorReason != "" || j.ErrorMessage != "" }
+
+// RegionLocation maps an INFORMATION_SCHEMA region qualifier such as "us"
+// or "europe-west1" to the job location BigQuery reports ("US",
+// "europe-west1").
+func RegionLocation(region string) string {
+	region = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(region)), "region-")
+	if region == "us" || region == "eu" {
+		return strings.ToUpper(region)
+	}
+	return region
+}
+
+// JobsSQL builds a query listing the most recent jobs of the last days in
+// projectID and region. It reads JOBS_BY_USER, the current user's jobs, or
+// JOBS (every user's, which needs bigquery.jobs.listAll) when allUsers is set.
+func JobsSQL(projectID, region string, allUsers bool, days, limit int) string {
+	view := "JOBS_BY_USER"
+	if allUsers {
+		view = "JOBS"
+	}
+	region = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(region)), "region-")
+	return fmt.Sprintf(`SELECT
+  job_id, user_email, job_type, statement_type, state, query,
+  creation_time, start_time, end_time,
+  total_bytes_processed, total_bytes_billed, total_slot_ms, cache_hit,
+  error_result.reason AS error_reason, error_result.message AS error_message,
+  destination_table.project_id AS dest_project,
+  destination_table.dataset_id AS dest_dataset,
+  destination_table.table_id AS dest_table
+FROM `+"`%s`.`region-%s`"+`.INFORMATION_SCHEMA.%s
+WHERE creation_time >= TIMESTAMP_SUB(CURRENT_TIMESTAMP(), INTERVAL %d DAY)
+ORDER BY creation_time DESC
+LIMIT %d`, projectID, region, view, days, limit)
+}
+
+// ListJobs lists recent jobs of projectID in region, newest first; see
+// JobsSQL. The cost of the INFORMATION_SCHEMA query is returned once it ran.
+func (c *Client) ListJobs(ctx context.Context, projectID, region string, allUsers bool, days, limit int, opts QueryOptions) ([]JobInfo, *QueryCost, error) {
+	cl, err := c.getClient(projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	q := cl.Query(JobsSQL(projectID, region, allUsers, days, limit))
+	opts.apply(q)
+	it, cost, err := runQueryJob(ctx, q)
+	if err != nil {
+		return nil, cost, fmt.Errorf("list jobs: %w", err)
+	}
+	location := RegionLocation(region)
+	var out []JobInfo
+	for {
+		var row map[string]bigquery.Value
+		err := it.Next(&row)
+		if err == iterator.Done {
+			return out, cost, nil
+		}
+		if err != nil {
+			return nil, cost, fmt.Errorf("read jobs: %w", err)
+		}
+		out = append(out, parseJobRow(row, projectID, location))
+	}
+}
+
+func parseJobRow(row map[string]bigquery.Value, projectID, location string) JobInfo {
+	str := func(k string) string {
+		s, _ := row[k].(string)
+		return s
+	}
+	ts := func(k string) time.Time {
+		t, _ := row[k].(time.Time)
+		return t
+	}
+	cacheHit, _ := row["cache_hit"].(bool)
+	return JobInfo{
+		ProjectID:      projectID,
+		JobID:          str("job_id"),
+		Location:       location,
+		User:           str("user_email"),
+		JobType:        str("job_type"),
+		StatementType:  str("statement_type"),
+		State:          str("state"),
+		Query:          str("query"),
+		Created:        ts("creation_time"),
+		Started:        ts("start_time"),
+		Ended:          ts("end_time"),
+		BytesProcessed: toInt64(row["total_bytes_processed"]),
+		BytesBilled:    toInt64(row["total_bytes_billed"]),
+		SlotMillis:     toInt64(row["total_slot_ms"]),
+		CacheHit:       cacheHit,
+		ErrorReason:    str("error_reason"),
+		ErrorMessage:   str("error_message"),
+		DestProject:    str("dest_project"),
+		DestDataset:    str("dest_dataset"),
+		DestTable:      str("dest_table"),
+	}
+}
+
+// JobRef identifies a job. Location may be empty for jobs in the US and EU
+// multi-regions, which BigQuery finds without it.
+type JobRef struct {
+	ProjectID string
+	Location  string
+	JobID     string
+}
+
+// String formats the reference the way the bq CLI and the console do, e.g.
+// "my-project:US.bquxjob_1a2b3c".
+func (r JobRef) String() string {
+	s := r.JobID
+	if r.Location != "" {
+		s = r.Location + "." + s
+	}
+	if r.ProjectID != "" {
+		s = r.ProjectID + ":" + s
+	}
+	return s
+}
+
+var jobIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ParseJobRef parses a job reference as printed by the bq CLI and the
+// console ("project:LOCATION.job_id"), or a shorter "LOCATION.job_id" or
+// bare "job_id", which belong to defaultProject. Domain-scoped project IDs
+// such as "example.com:project" are supported.
+func ParseJobRef(s, defaultProject string) (JobRef, error) {
+	s = strings.Trim(strings.TrimSpace(s), "`'\"")
+	ref := JobRef{ProjectID: defaultProject}
+	if i := strings.LastIndex(s, ":"); i >= 0 {
+		ref.ProjectID, s = s[:i], s[i+1:]
+	}
+	if i := strings.LastIndex(s, "."); i >= 0 {
+		ref.Location, s = s[:i], s[i+1:]
+	}
+	ref.JobID = s
+	switch {
+	case ref.JobID == "":
+		return JobRef{}, fmt.Errorf("missing job ID")
+	case len(ref.JobID) > 1024 || !jobIDPattern.MatchString(ref.JobID):
+		return JobRef{}, fmt.Errorf("invalid job ID %q: only letters, digits, - and _ are allowed", ref.JobID)
+	case ref.ProjectID == "":
+		return JobRef{}, fmt.Errorf("missing project for job %s", ref.JobID)
+	}
+	return ref, nil
+}
+
+// JobResult looks up a finished query job and reads up to the first 10,000
+// rows of its results from the job's destination table, with its SQL and
+// statistics. Nothing is re-run or billed; results of plain queries are
+// only kept for about a day.
+func (c *Client) JobResult(ctx context.Context, ref JobRef) (*QueryResult, error) {
+	cl, err := c.getClient(ref.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	job, err := cl.JobFromIDLocation(ctx, ref.JobID, ref.Location)
+	if err != nil {
+		return nil, fmt.Errorf("get job %s: %w", ref, err)
+	}
+	cfg, err := job.Config()
+	if err != nil {
+		return nil, fmt.Errorf("job config: %w", err)
+	}
+	qc, ok := cfg.(*bigquery.QueryConfig)
+	if !ok {
+		return nil, fmt.Errorf("job %s is not a query job", ref)
+	}
+	status := job.LastStatus()
+	if status == nil || !status.Done() {
+		return nil, fmt.Errorf("job %s has not finished yet", ref)
+	}
+	if status.Err() != nil {
+		return nil, fmt.Errorf("job %s failed: %w", ref, status.Err())
+	}
+
+	it, err := job.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read results: %w", err)
+	}
+	result := &QueryResult{
+		SQL:       qc.Q,
+		ProjectID: job.ProjectID(),
+		JobID:     job.ID(),
+		Location:  job.Location(),
+	}
+	if js := status.Statistics; js != nil {
+		result.Duration = js.EndTime.Sub(js.StartTime)
+		result.BytesProcessed = js.TotalBytesProcessed
+		result.Stats = queryStatsFrom(js)
+	}
+	if err := readResultRows(it, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
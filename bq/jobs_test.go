@@ -0,0 +1,106 @@
+package bq
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestJobsSQL(t *testing.T) {
+	sql := JobsSQL("my-proj", "region-EU", false, 7, 200)
+	for _, want := range []string{
+		"FROM `my-proj`.`region-eu`.INFORMATION_SCHEMA.JOBS_BY_USER",
+		"INTERVAL 7 DAY",
+		"LIMIT 200",
+		"error_result.reason AS error_reason",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("missing %q in:\n%s", want, sql)
+		}
+	}
+	if !strings.Contains(JobsSQL("p", "us", true, 1, 10), "INFORMATION_SCHEMA.JOBS\n") {
+		t.Error("allUsers should read JOBS")
+	}
+}
+
+func TestRegionLocation(t *testing.T) {
+	for in, want := range map[string]string{"us": "US", "region-eu": "EU", "europe-west1": "europe-west1"} {
+		if got := RegionLocation(in); got != want {
+			t.Errorf("RegionLocation(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseJobRow(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	j := parseJobRow(map[string]bigquery.Value{
+		"job_id":                "bquxjob_1",
+		"user_email":            "a@b.c",
+		"job_type":              "QUERY",
+		"state":                 "DONE",
+		"query":                 "SELECT 1",
+		"creation_time":         created,
+		"total_bytes_billed":    int64(10485760),
+		"total_slot_ms":         int64(1500),
+		"cache_hit":             true,
+		"error_reason":          nil,
+		"dest_project":          "p",
+		"dest_dataset":          "_abc",
+		"dest_table":            "anon123",
+		"total_bytes_processed": nil,
+	}, "p", "US")
+	if j.JobID != "bquxjob_1" || j.User != "a@b.c" || !j.Created.Equal(created) || j.BytesBilled != 10485760 ||
+		j.SlotMillis != 1500 || !j.CacheHit || j.Location != "US" || j.DestTable != "anon123" {
+		t.Errorf("parsed job = %+v", j)
+	}
+	if j.Failed() {
+		t.Error("job without error should not be failed")
+	}
+}
+
+func TestParseJobRef(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    JobRef
+		wantErr bool
+	}{
+		{in: "my-proj:US.bquxjob_1a2b_3c", want: JobRef{"my-proj", "US", "bquxjob_1a2b_3c"}},
+		{in: "  `other:europe-west1.job-9`  ", want: JobRef{"other", "europe-west1", "job-9"}},
+		{in: "example.com:proj:EU.abc", want: JobRef{"example.com:proj", "EU", "abc"}},
+		{in: "US.abc", want: JobRef{"def", "US", "abc"}},
+		{in: "abc", want: JobRef{"def", "", "abc"}},
+		{in: "other:abc", want: JobRef{"other", "", "abc"}},
+		{in: "", wantErr: true},
+		{in: "US.", wantErr: true},
+		{in: "my-proj:US.bad id", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseJobRef(tt.in, "def")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseJobRef(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseJobRef(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+	if _, err := ParseJobRef("abc", ""); err == nil {
+		t.Error("a bare job ID without a default project should fail")
+	}
+}
+
+func TestJobRefString(t *testing.T) {
+	ref := JobRef{"p", "US", "j"}
+	if ref.String() != "p:US.j" {
+		t.Errorf("String() = %q", ref.String())
+	}
+	back, err := ParseJobRef(ref.String(), "")
+	if err != nil || back != ref {
+		t.Errorf("round trip = %+v, %v", back, err)
+	}
+	if got := (JobRef{ProjectID: "p", JobID: "j"}).String(); got != "p:j" {
+		t.Errorf("String() without location = %q", got)
+	}
+}
@@ -0,0 +1,116 @@
+package bq
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BigQuery label limits.
+const (
+	maxLabels      = 64
+	maxLabelLength = 63
+)
+
+// SanitizeLabelValue lowercases s and replaces characters BigQuery does not
+// allow in labels (anything but letters, digits, '_' and '-') with '_',
+// truncating to 63 characters.
+func SanitizeLabelValue(s string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		if n == maxLabelLength {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+		n++
+	}
+	return b.String()
+}
+
+// SanitizeLabelKey is SanitizeLabelValue for keys, which must also be
+// non-empty and start with a letter; other keys get an "l_" prefix.
+func SanitizeLabelKey(s string) string {
+	k := SanitizeLabelValue(s)
+	if k == "" {
+		return ""
+	}
+	if r := []rune(k)[0]; !unicode.IsLetter(r) {
+		k = SanitizeLabelValue("l_" + k)
+	}
+	return k
+}
+
+// SanitizeLabels returns labels with valid keys and values, dropping empty
+// keys and keeping at most 64 labels in key order.
+func SanitizeLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make(map[string]string, len(labels))
+	for _, k := range keys {
+		sk := SanitizeLabelKey(k)
+		if sk == "" || len(out) == maxLabels {
+			continue
+		}
+		out[sk] = SanitizeLabelValue(labels[k])
+	}
+	return out
+}
+
+// ParseLabels parses comma-separated key=value pairs such as
+// "team=finance, cost_center=42". A key without '=' has an empty value.
+func ParseLabels(text string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(part, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if k == "" {
+			return nil, fmt.Errorf("label %q has no key", part)
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+// FormatLabels formats labels as ParseLabels reads them, sorted by key.
+func FormatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + labels[k]
+	}
+	return strings.Join(parts, ", ")
+}
+
+// withCommentHeader prefixes sqlText with a comment naming the labels, so
+// the job is recognizable from its query text too. A leading #standardSQL
+// or #legacySQL directive stays on the first line.
+func withCommentHeader(sqlText string, labels map[string]string) string {
+	header := "-- delephon: " + FormatLabels(labels) + "\n"
+	trimmed := strings.TrimLeft(sqlText, " \t\r\n")
+	lower := strings.ToLower(trimmed)
+	if strings.HasPrefix(lower, "#standardsql") || strings.HasPrefix(lower, "#legacysql") {
+		first, rest, _ := strings.Cut(trimmed, "\n")
+		return first + "\n" + header + rest
+	}
+	return header + sqlText
+}
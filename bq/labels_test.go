@@ -0,0 +1,72 @@
+package bq
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestSanitizeLabels(t *testing.T) {
+	got := SanitizeLabels(map[string]string{
+		"Tool":      "Delephon",
+		"tab":       "Daily Revenue (v2)",
+		"2fa":       "on",
+		"user":      "jane.doe@example.com",
+		"  ":        "dropped",
+		"long":      strings.Repeat("x", 80),
+		"ümlaut-ok": "ÄÖ",
+	})
+	want := map[string]string{
+		"tool":      "delephon",
+		"tab":       "daily_revenue__v2_",
+		"l_2fa":     "on",
+		"user":      "jane_doe_example_com",
+		"long":      strings.Repeat("x", 63),
+		"ümlaut-ok": "äö",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SanitizeLabels = %v, want %v", got, want)
+	}
+
+	many := map[string]string{}
+	for i := 0; i < 70; i++ {
+		many[fmt.Sprintf("k%d", i)] = ""
+	}
+	if n := len(SanitizeLabels(many)); n != maxLabels {
+		t.Errorf("kept %d labels, want %d", n, maxLabels)
+	}
+}
+
+func TestParseLabels(t *testing.T) {
+	got, err := ParseLabels(" team=finance, cost_center = 42,,adhoc ")
+	want := map[string]string{"team": "finance", "cost_center": "42", "adhoc": ""}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseLabels = %v, %v", got, err)
+	}
+	if FormatLabels(got) != "adhoc=, cost_center=42, team=finance" {
+		t.Errorf("FormatLabels = %q", FormatLabels(got))
+	}
+	if _, err := ParseLabels("=x"); err == nil {
+		t.Error("a label without a key should fail")
+	}
+}
+
+func TestQueryOptionsApply(t *testing.T) {
+	q := &bigquery.Query{QueryConfig: bigquery.QueryConfig{Q: "#standardSQL\nSELECT 1"}}
+	QueryOptions{MaxBytesBilled: 10, Labels: map[string]string{"Tool": "delephon"}, CommentHeader: true}.apply(q)
+	if q.MaxBytesBilled != 10 || !reflect.DeepEqual(q.Labels, map[string]string{"tool": "delephon"}) {
+		t.Errorf("query config = %+v", q.QueryConfig)
+	}
+	if q.Q != "#standardSQL\n-- delephon: tool=delephon\nSELECT 1" {
+		t.Errorf("query text = %q", q.Q)
+	}
+
+	q = &bigquery.Query{QueryConfig: bigquery.QueryConfig{Q: "SELECT 1"}}
+	QueryOptions{Labels: map[string]string{"a": "b"}}.apply(q)
+	if q.Q != "SELECT 1" {
+		t.Errorf("comment added without CommentHeader: %q", q.Q)
+	}
+}
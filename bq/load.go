@@ -0,0 +1,457 @@
+package bq
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// Source formats LoadFile accepts.
+const (
+	FormatCSV     = "CSV"
+	FormatJSON    = "NEWLINE_DELIMITED_JSON"
+	FormatAvro    = "AVRO"
+	FormatParquet = "PARQUET"
+)
+
+// DetectFormat guesses a file's source format from its extension, or
+// returns "" when it is not one LoadFile accepts.
+func DetectFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv", ".tsv", ".txt":
+		return FormatCSV
+	case ".json", ".ndjson", ".jsonl":
+		return FormatJSON
+	case ".avro":
+		return FormatAvro
+	case ".parquet", ".parq":
+		return FormatParquet
+	}
+	return ""
+}
+
+// SelfDescribing reports whether files of format carry their own schema.
+func SelfDescribing(format string) bool {
+	return format == FormatAvro || format == FormatParquet
+}
+
+// FilePreview is the first rows of a CSV or JSON file with a schema inferred
+// from them.
+type FilePreview struct {
+	Fields []SchemaField
+	Rows   [][]string // one value per field
+}
+
+// PreviewFile reads up to maxRows rows of a CSV or newline-delimited JSON
+// file and infers a column type from the values of each. CSV column names
+// come from the first row when header is set. Avro and Parquet files carry
+// their own schema and are not previewed.
+func PreviewFile(path, format, delimiter string, header bool, maxRows int) (*FilePreview, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch format {
+	case FormatCSV:
+		return previewCSV(f, delimiter, header, maxRows)
+	case FormatJSON:
+		return previewJSON(f, maxRows)
+	}
+	return nil, fmt.Errorf("%s files carry their own schema and are not previewed", format)
+}
+
+func previewCSV(r io.Reader, delimiter string, header bool, maxRows int) (*FilePreview, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	if d := []rune(delimiter); len(d) == 1 {
+		cr.Comma = d[0]
+	}
+
+	var names []string
+	p := &FilePreview{}
+	for len(p.Rows) < maxRows {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read CSV: %w", err)
+		}
+		if header && names == nil {
+			names = rec
+			continue
+		}
+		p.Rows = append(p.Rows, rec)
+	}
+
+	width := len(names)
+	for _, row := range p.Rows {
+		width = max(width, len(row))
+	}
+	types := make([]string, width)
+	for _, row := range p.Rows {
+		for i, v := range row {
+			types[i] = mergeTypes(types[i], inferType(v))
+		}
+	}
+	cols := make([]string, width)
+	copy(cols, names)
+	cols = ColumnNames(cols)
+	for i := range cols {
+		p.Fields = append(p.Fields, SchemaField{Name: cols[i], Type: orString(types[i]), Mode: "NULLABLE"})
+	}
+	for i, row := range p.Rows {
+		if len(row) < width {
+			p.Rows[i] = append(row, make([]string, width-len(row))...)
+		}
+	}
+	return p, nil
+}
+
+func previewJSON(r io.Reader, maxRows int) (*FilePreview, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var keys []string
+	index := map[string]int{}
+	var types []string
+	var records []map[string]any
+	for line := 1; len(records) < maxRows && sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.UseNumber()
+		var rec map[string]any
+		if err := dec.Decode(&rec); err != nil {
+			return nil, fmt.Errorf("line %d is not a JSON object: %w", line, err)
+		}
+		for k, v := range rec {
+			i, ok := index[k]
+			if !ok {
+				i = len(keys)
+				index[k] = i
+				keys = append(keys, k)
+				types = append(types, "")
+			}
+			types[i] = mergeTypes(types[i], jsonType(v))
+		}
+		records = append(records, rec)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read JSON: %w", err)
+	}
+
+	p := &FilePreview{}
+	for i, k := range keys {
+		f := SchemaField{Name: k, Type: orString(types[i]), Mode: "NULLABLE"}
+		if strings.HasPrefix(f.Type, "ARRAY<") {
+			f.Type, f.Mode = strings.TrimSuffix(strings.TrimPrefix(f.Type, "ARRAY<"), ">"), "REPEATED"
+		}
+		p.Fields = append(p.Fields, f)
+	}
+	for _, rec := range records {
+		row := make([]string, len(keys))
+		for i, k := range keys {
+			switch v := rec[k].(type) {
+			case nil:
+			case string:
+				row[i] = v
+			case json.Number:
+				row[i] = v.String()
+			default:
+				b, _ := json.Marshal(v)
+				row[i] = string(b)
+			}
+		}
+		p.Rows = append(p.Rows, row)
+	}
+	return p, nil
+}
+
+var (
+	intValue       = regexp.MustCompile(`^[-+]?\d+$`)
+	floatValue     = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+	dateValue      = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	timestampValue = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}[ T]\d{2}:\d{2}(:\d{2}(\.\d+)?)?( ?(Z|UTC|[-+]\d{2}(:?\d{2})?))?$`)
+)
+
+// inferType returns the narrowest BigQuery type of a text value, or "" for
+// an empty value, which fits any type.
+func inferType(v string) string {
+	v = strings.TrimSpace(v)
+	switch {
+	case v == "":
+		return ""
+	case intValue.MatchString(v) && len(strings.TrimLeft(v, "+-")) <= 18:
+		return "INTEGER"
+	case floatValue.MatchString(v):
+		return "FLOAT"
+	case strings.EqualFold(v, "true") || strings.EqualFold(v, "false"):
+		return "BOOLEAN"
+	case dateValue.MatchString(v):
+		return "DATE"
+	case timestampValue.MatchString(v):
+		return "TIMESTAMP"
+	}
+	return "STRING"
+}
+
+// jsonType returns the BigQuery type of a decoded JSON value.
+func jsonType(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case bool:
+		return "BOOLEAN"
+	case json.Number:
+		return inferType(v.String())
+	case string:
+		if t := inferType(v); t == "DATE" || t == "TIMESTAMP" {
+			return t
+		}
+		return "STRING"
+	case map[string]any:
+		return "RECORD"
+	case []any:
+		elem := ""
+		for _, e := range v {
+			elem = mergeTypes(elem, jsonType(e))
+		}
+		return "ARRAY<" + orString(elem) + ">"
+	}
+	return "STRING"
+}
+
+// mergeTypes returns a type that holds values of both a and b.
+func mergeTypes(a, b string) string {
+	switch {
+	case a == "" || a == b:
+		return b
+	case b == "":
+		return a
+	case (a == "INTEGER" && b == "FLOAT") || (a == "FLOAT" && b == "INTEGER"):
+		return "FLOAT"
+	case (a == "DATE" && b == "TIMESTAMP") || (a == "TIMESTAMP" && b == "DATE"):
+		return "TIMESTAMP"
+	}
+	return "STRING"
+}
+
+func orString(t string) string {
+	if t == "" {
+		return "STRING"
+	}
+	return t
+}
+
+var invalidColumnChars = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// ColumnNames turns header values into valid, unique BigQuery column names:
+// other characters become underscores, names starting with a digit get a
+// leading underscore and empty names become column_N.
+func ColumnNames(header []string) []string {
+	out := make([]string, len(header))
+	seen := map[string]bool{}
+	for i, h := range header {
+		name := strings.Trim(invalidColumnChars.ReplaceAllString(strings.TrimSpace(h), "_"), "_")
+		if name == "" {
+			name = fmt.Sprintf("column_%d", i+1)
+		} else if name[0] >= '0' && name[0] <= '9' {
+			name = "_" + name
+		}
+		if len(name) > 300 {
+			name = name[:300]
+		}
+		base := name
+		for n := 2; seen[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s_%d", base, n)
+		}
+		seen[strings.ToLower(name)] = true
+		out[i] = name
+	}
+	return out
+}
+
+// LoadOptions describe a local file for LoadFile.
+type LoadOptions struct {
+	Format string // FormatCSV, FormatJSON, FormatAvro or FormatParquet
+
+	// Schema is the table schema for CSV and JSON files. When empty,
+	// BigQuery detects it, or uses the existing table's when appending.
+	Schema []SchemaField
+
+	// CSV options.
+	FieldDelimiter  string
+	SkipLeadingRows int64
+
+	// MaxBadRecords rows that cannot be parsed are skipped, and reported in
+	// LoadResult.BadRows, before the job fails.
+	MaxBadRecords int64
+
+	Labels map[string]string
+}
+
+// LoadResult summarizes a finished LoadFile job.
+type LoadResult struct {
+	JobID      string
+	OutputRows int64
+	InputBytes int64
+	TotalRows  uint64 // rows in the table after the job
+	Duration   time.Duration
+	BadRows    []string // errors of skipped rows, e.g. "Line 7: Could not parse 'x' as INT64"
+}
+
+// LoadError is a failed load job, with the errors BigQuery reported for
+// individual rows.
+type LoadError struct {
+	JobID string
+	Err   error
+	Rows  []string
+}
+
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("load job %s failed: %v", e.JobID, e.Err)
+}
+
+func (e *LoadError) Unwrap() error { return e.Err }
+
+// LoadFile uploads a local file into dst with a load job, creating the
+// table if needed. onProgress, if set, receives the bytes uploaded so far
+// and the file size; onStart receives the job ID once the upload is done
+// and the job is running.
+func (c *Client) LoadFile(ctx context.Context, dst TableDestination, path string, opts LoadOptions, onProgress func(done, total int64), onStart func(jobID string)) (*LoadResult, error) {
+	cl, err := c.getClient(dst.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	src := bigquery.NewReaderSource(&progressReader{r: f, total: fi.Size(), onProgress: onProgress})
+	src.SourceFormat = bigquery.DataFormat(opts.Format)
+	src.MaxBadRecords = opts.MaxBadRecords
+	if !SelfDescribing(opts.Format) {
+		if len(opts.Schema) > 0 {
+			src.Schema = bigquerySchema(opts.Schema)
+		} else {
+			src.AutoDetect = true
+		}
+	}
+	if opts.Format == FormatCSV {
+		src.FieldDelimiter = opts.FieldDelimiter
+		src.SkipLeadingRows = opts.SkipLeadingRows
+		src.AllowQuotedNewlines = true
+	}
+
+	table := cl.DatasetInProject(dst.ProjectID, dst.DatasetID).Table(dst.TableID)
+	loader := table.LoaderFrom(src)
+	loader.CreateDisposition = bigquery.CreateIfNeeded
+	loader.WriteDisposition = writeDisposition(dst.WriteDisposition)
+	loader.TimePartitioning = timePartitioning(dst)
+	loader.UseAvroLogicalTypes = opts.Format == FormatAvro
+	if labels := SanitizeLabels(opts.Labels); len(labels) > 0 {
+		loader.Labels = labels
+	}
+
+	start := time.Now()
+	job, err := loader.Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("upload: %w", err)
+	}
+	if onStart != nil {
+		onStart(job.ID())
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("wait load: %w", err)
+	}
+	rows := loadErrorRows(status.Errors)
+	if status.Err() != nil {
+		return nil, &LoadError{JobID: job.ID(), Err: status.Err(), Rows: rows}
+	}
+
+	result := &LoadResult{JobID: job.ID(), Duration: time.Since(start), BadRows: rows}
+	if status.Statistics != nil {
+		if ls, ok := status.Statistics.Details.(*bigquery.LoadStatistics); ok {
+			result.OutputRows = ls.OutputRows
+			result.InputBytes = ls.InputFileBytes
+		}
+	}
+	md, err := table.Metadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("table metadata: %w", err)
+	}
+	result.TotalRows = md.NumRows
+	return result, nil
+}
+
+// loadErrorRows formats the errors of a load job, one per bad row or
+// problem.
+func loadErrorRows(errs []*bigquery.Error) []string {
+	var out []string
+	for _, e := range errs {
+		if e == nil {
+			continue
+		}
+		s := e.Message
+		if e.Location != "" && !strings.Contains(s, e.Location) {
+			s = e.Location + ": " + s
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// bigquerySchema converts fields back to a client schema. Types are the
+// legacy names GetTableSchema returns, such as INTEGER or RECORD.
+func bigquerySchema(fields []SchemaField) bigquery.Schema {
+	schema := make(bigquery.Schema, len(fields))
+	for i, f := range fields {
+		schema[i] = &bigquery.FieldSchema{
+			Name:        f.Name,
+			Type:        bigquery.FieldType(f.Type),
+			Required:    f.Mode == "REQUIRED",
+			Repeated:    f.Mode == "REPEATED",
+			Description: f.Description,
+		}
+	}
+	return schema
+}
+
+// progressReader reports how much of a file has been read for upload.
+type progressReader struct {
+	r          io.Reader
+	done       int64
+	total      int64
+	onProgress func(done, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.done += int64(n)
+	if p.onProgress != nil && n > 0 {
+		p.onProgress(p.done, p.total)
+	}
+	return n, err
+}
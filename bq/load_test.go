@@ -0,0 +1,167 @@
+package bq
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := map[string]string{
+		"lookup.csv":        FormatCSV,
+		"/tmp/Data.TSV":     FormatCSV,
+		"events.ndjson":     FormatJSON,
+		"events.jsonl":      FormatJSON,
+		"rows.json":         FormatJSON,
+		"part-0001.parquet": FormatParquet,
+		"users.avro":        FormatAvro,
+		"notes.xlsx":        "",
+		"no-extension":      "",
+	}
+	for path, want := range tests {
+		if got := DetectFormat(path); got != want {
+			t.Errorf("DetectFormat(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestInferType(t *testing.T) {
+	tests := map[string]string{
+		"":                          "",
+		"42":                        "INTEGER",
+		"-7":                        "INTEGER",
+		"12345678901234567890":      "FLOAT",
+		"3.14":                      "FLOAT",
+		"1e-3":                      "FLOAT",
+		"TRUE":                      "BOOLEAN",
+		"2024-03-01":                "DATE",
+		"2024-03-01 10:20:30":       "TIMESTAMP",
+		"2024-03-01T10:20:30.5Z":    "TIMESTAMP",
+		"2024-03-01 10:20:30 UTC":   "TIMESTAMP",
+		"2024-03-01T10:20:30+02:00": "TIMESTAMP",
+		"03/01/2024":                "STRING",
+		"abc":                       "STRING",
+	}
+	for v, want := range tests {
+		if got := inferType(v); got != want {
+			t.Errorf("inferType(%q) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestMergeTypes(t *testing.T) {
+	tests := []struct{ a, b, want string }{
+		{"", "INTEGER", "INTEGER"},
+		{"INTEGER", "", "INTEGER"},
+		{"INTEGER", "FLOAT", "FLOAT"},
+		{"DATE", "TIMESTAMP", "TIMESTAMP"},
+		{"BOOLEAN", "INTEGER", "STRING"},
+		{"DATE", "DATE", "DATE"},
+	}
+	for _, tt := range tests {
+		if got := mergeTypes(tt.a, tt.b); got != tt.want {
+			t.Errorf("mergeTypes(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestColumnNames(t *testing.T) {
+	got := ColumnNames([]string{"Country Code", "2024 sales", "", "name", "NAME", "price ($)"})
+	want := []string{"Country_Code", "_2024_sales", "column_3", "name", "NAME_2", "price"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ColumnNames = %v, want %v", got, want)
+	}
+}
+
+func writeTemp(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPreviewFile_CSV(t *testing.T) {
+	path := writeTemp(t, "lookup.csv", "id,name,price,active,since\n"+
+		"1,Widget,9.5,true,2024-01-02\n"+
+		"2,\"Gadget, large\",10,false,\n"+
+		"3,Thing,,TRUE,2024-02-03 04:05:06\n")
+
+	p, err := PreviewFile(path, FormatCSV, ",", true, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields []string
+	for _, f := range p.Fields {
+		fields = append(fields, f.Name+" "+f.Type)
+	}
+	want := "id INTEGER, name STRING, price FLOAT, active BOOLEAN, since TIMESTAMP"
+	if got := strings.Join(fields, ", "); got != want {
+		t.Errorf("fields = %s, want %s", got, want)
+	}
+	if len(p.Rows) != 3 || p.Rows[1][1] != "Gadget, large" {
+		t.Errorf("rows = %q", p.Rows)
+	}
+
+	p, err = PreviewFile(path, FormatCSV, ",", false, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Rows) != 2 || p.Fields[0].Name != "column_1" || p.Fields[0].Type != "STRING" {
+		t.Errorf("without header: fields %+v, %d rows", p.Fields, len(p.Rows))
+	}
+}
+
+func TestPreviewFile_CSVRaggedAndDelimiter(t *testing.T) {
+	path := writeTemp(t, "data.tsv", "a\tb\n1\n2\t3\t4\n")
+	p, err := PreviewFile(path, FormatCSV, "\t", true, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Fields) != 3 || p.Fields[2].Name != "column_3" {
+		t.Fatalf("fields = %+v", p.Fields)
+	}
+	if len(p.Rows[0]) != 3 {
+		t.Errorf("short rows should be padded: %q", p.Rows[0])
+	}
+}
+
+func TestPreviewFile_JSON(t *testing.T) {
+	path := writeTemp(t, "events.ndjson", `{"id": 1, "tags": ["a", "b"], "at": "2024-01-02T03:04:05Z"}`+"\n\n"+
+		`{"id": 2.5, "meta": {"k": "v"}, "ok": true}`+"\n")
+	p, err := PreviewFile(path, FormatJSON, "", false, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := map[string]string{}
+	for _, f := range p.Fields {
+		types[f.Name] = f.Mode + " " + f.Type
+	}
+	want := map[string]string{
+		"id":   "NULLABLE FLOAT",
+		"tags": "REPEATED STRING",
+		"at":   "NULLABLE TIMESTAMP",
+		"meta": "NULLABLE RECORD",
+		"ok":   "NULLABLE BOOLEAN",
+	}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("fields = %v, want %v", types, want)
+	}
+	if len(p.Rows) != 2 {
+		t.Fatalf("rows = %q", p.Rows)
+	}
+
+	bad := writeTemp(t, "bad.json", "[1, 2]\n")
+	if _, err := PreviewFile(bad, FormatJSON, "", false, 10); err == nil {
+		t.Error("expected an error for a JSON array line")
+	}
+}
+
+func TestPreviewFile_SelfDescribing(t *testing.T) {
+	if _, err := PreviewFile(writeTemp(t, "x.parquet", "PAR1"), FormatParquet, "", false, 10); err == nil {
+		t.Error("expected Parquet files not to be previewed")
+	}
+}
@@ -0,0 +1,55 @@
+package bq
+
+import (
+	"errors"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+)
+
+// QueryOptions are settings applied to every query job the client starts.
+type QueryOptions struct {
+	// MaxBytesBilled makes BigQuery fail a query that would bill more than
+	// this many bytes, before it reads any data. Zero means no limit.
+	MaxBytesBilled int64
+
+	// Labels are attached to the job, after SanitizeLabels, so it can be
+	// found in INFORMATION_SCHEMA.JOBS and billing exports.
+	Labels map[string]string
+	// CommentHeader also names the labels in a comment before the SQL.
+	CommentHeader bool
+}
+
+func (o QueryOptions) apply(q *bigquery.Query) {
+	if o.MaxBytesBilled > 0 {
+		q.MaxBytesBilled = o.MaxBytesBilled
+	}
+	if labels := SanitizeLabels(o.Labels); len(labels) > 0 {
+		q.Labels = labels
+		if o.CommentHeader {
+			q.Q = withCommentHeader(q.Q, labels)
+		}
+	}
+}
+
+// bytesBilledLimitReason is the error reason BigQuery reports for a query
+// refused by MaxBytesBilled.
+const bytesBilledLimitReason = "bytesBilledLimitExceeded"
+
+// IsBytesBilledLimit reports whether err is BigQuery refusing a query because
+// it would bill more than QueryOptions.MaxBytesBilled.
+func IsBytesBilledLimit(err error) bool {
+	var bqErr *bigquery.Error
+	if errors.As(err, &bqErr) && bqErr.Reason == bytesBilledLimitReason {
+		return true
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		for _, item := range apiErr.Errors {
+			if item.Reason == bytesBilledLimitReason {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package bq
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsBytesBilledLimit(t *testing.T) {
+	limit := &bigquery.Error{Reason: "bytesBilledLimitExceeded", Message: "Query exceeded limit for bytes billed"}
+	if !IsBytesBilledLimit(fmt.Errorf("query error: %w", limit)) {
+		t.Error("job error with the limit reason not detected")
+	}
+	api := &googleapi.Error{Code: 400, Errors: []googleapi.ErrorItem{{Reason: "bytesBilledLimitExceeded"}}}
+	if !IsBytesBilledLimit(fmt.Errorf("run query: %w", api)) {
+		t.Error("API error with the limit reason not detected")
+	}
+	for _, err := range []error{
+		nil,
+		errors.New("bytesBilledLimitExceeded"),
+		&bigquery.Error{Reason: "invalidQuery"},
+		&googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}},
+	} {
+		if IsBytesBilledLimit(err) {
+			t.Errorf("IsBytesBilledLimit(%v) = true", err)
+		}
+	}
+}
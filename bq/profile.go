@@ -0,0 +1,239 @@
+package bq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// profileTopK is how many most frequent values a profile reports.
+const profileTopK = 5
+
+// profileQuantiles is the number of quantile intervals fetched per numeric
+// column, which is also the number of histogram bins.
+const profileQuantiles = 10
+
+// ColumnProfile holds summary statistics for one column of a table. Counts
+// of distinct and top values are approximate.
+type ColumnProfile struct {
+	Name      string
+	Type      string
+	Rows      int64
+	Nulls     int64
+	Distinct  int64 // -1 when not computed for the type
+	Min, Max  string
+	Numeric   bool
+	Mean      float64
+	StdDev    float64
+	Top       []ValueCount
+	Histogram []HistogramBin
+}
+
+type ValueCount struct {
+	Value string
+	Count int64
+}
+
+// HistogramBin counts the values in [Lo, Hi).
+type HistogramBin struct {
+	Lo, Hi float64
+	Count  int64
+}
+
+func isNumericField(typ string) bool {
+	switch typ {
+	case "INTEGER", "INT64", "FLOAT", "FLOAT64", "NUMERIC", "BIGNUMERIC":
+		return true
+	}
+	return false
+}
+
+// isGroupableField reports whether a column supports DISTINCT and GROUP BY,
+// which the APPROX_* aggregates need.
+func isGroupableField(f SchemaField) bool {
+	if f.Mode == "REPEATED" {
+		return false
+	}
+	switch f.Type {
+	case "RECORD", "STRUCT", "JSON", "GEOGRAPHY":
+		return false
+	}
+	return true
+}
+
+// TableProfileSQL builds a single query that profiles the top-level columns
+// of table (a fully qualified name without backticks) using approximate
+// aggregates. Column i's results use the alias prefix "c<i>_".
+func TableProfileSQL(table string, fields []SchemaField) string {
+	var b strings.Builder
+	b.WriteString("SELECT\n  COUNT(*) AS row_count")
+	for i, f := range fields {
+		col := "`" + strings.ReplaceAll(f.Name, "`", "\\`") + "`"
+		p := fmt.Sprintf("c%d_", i)
+		fmt.Fprintf(&b, ",\n  COUNTIF(%s IS NULL) AS %snulls", col, p)
+		if !isGroupableField(f) {
+			continue
+		}
+		fmt.Fprintf(&b, ",\n  APPROX_COUNT_DISTINCT(%s) AS %sdistinct", col, p)
+		fmt.Fprintf(&b, ",\n  APPROX_TOP_COUNT(%s, %d) AS %stop", col, profileTopK, p)
+		fmt.Fprintf(&b, ",\n  MIN(%s) AS %smin,\n  MAX(%s) AS %smax", col, p, col, p)
+		if isNumericField(f.Type) {
+			num := "CAST(" + col + " AS FLOAT64)"
+			fmt.Fprintf(&b, ",\n  AVG(%s) AS %smean,\n  STDDEV(%s) AS %sstddev", num, p, num, p)
+			fmt.Fprintf(&b, ",\n  APPROX_QUANTILES(%s, %d) AS %squantiles", num, profileQuantiles, p)
+		}
+	}
+	fmt.Fprintf(&b, "\nFROM `%s`", table)
+	return b.String()
+}
+
+// ProfileTable computes column statistics for a table on the server with
+// one query of approximate aggregates. The query scans every column of the
+// table and is billed to projectID; its cost is returned once it ran.
+func (c *Client) ProfileTable(ctx context.Context, projectID, datasetID, tableID string, opts QueryOptions) ([]ColumnProfile, *QueryCost, error) {
+	schema, err := c.GetTableSchema(ctx, projectID, datasetID, tableID)
+	if err != nil {
+		return nil, nil, err
+	}
+	cl, err := c.getClient(projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	sqlText := TableProfileSQL(fmt.Sprintf("%s.%s.%s", projectID, datasetID, tableID), schema.Fields)
+	q := cl.Query(sqlText)
+	opts.apply(q)
+	it, cost, err := runQueryJob(ctx, q)
+	if err != nil {
+		return nil, cost, fmt.Errorf("profile query: %w", err)
+	}
+	var row map[string]bigquery.Value
+	if err := it.Next(&row); err != nil {
+		if err == iterator.Done {
+			return nil, cost, fmt.Errorf("profile query returned no rows")
+		}
+		return nil, cost, fmt.Errorf("read profile: %w", err)
+	}
+	return parseTableProfile(row, schema.Fields), cost, nil
+}
+
+// parseTableProfile reads the single row returned by TableProfileSQL.
+func parseTableProfile(row map[string]bigquery.Value, fields []SchemaField) []ColumnProfile {
+	rows := toInt64(row["row_count"])
+	out := make([]ColumnProfile, len(fields))
+	for i, f := range fields {
+		p := fmt.Sprintf("c%d_", i)
+		cp := ColumnProfile{
+			Name:     f.Name,
+			Type:     f.Type,
+			Rows:     rows,
+			Nulls:    toInt64(row[p+"nulls"]),
+			Distinct: -1,
+			Numeric:  isNumericField(f.Type),
+		}
+		if f.Mode == "REPEATED" {
+			cp.Type = "ARRAY<" + f.Type + ">"
+			cp.Numeric = false
+		}
+		if v, ok := row[p+"distinct"]; ok {
+			cp.Distinct = toInt64(v)
+		}
+		if v := row[p+"min"]; v != nil {
+			cp.Min = formatScalar(v)
+		}
+		if v := row[p+"max"]; v != nil {
+			cp.Max = formatScalar(v)
+		}
+		cp.Mean = toFloat64(row[p+"mean"])
+		cp.StdDev = toFloat64(row[p+"stddev"])
+		if top, ok := row[p+"top"].([]bigquery.Value); ok {
+			for _, t := range top {
+				m, ok := t.(map[string]bigquery.Value)
+				if !ok {
+					continue
+				}
+				cp.Top = append(cp.Top, ValueCount{Value: formatScalar(m["value"]), Count: toInt64(m["count"])})
+			}
+		}
+		if qs, ok := row[p+"quantiles"].([]bigquery.Value); ok {
+			bounds := make([]float64, 0, len(qs))
+			for _, q := range qs {
+				if q != nil {
+					bounds = append(bounds, toFloat64(q))
+				}
+			}
+			cp.Histogram = histogramFromQuantiles(bounds, rows-cp.Nulls, profileQuantiles)
+		}
+		out[i] = cp
+	}
+	return out
+}
+
+// histogramFromQuantiles estimates an equal-width histogram from quantile
+// boundaries, assuming values are spread evenly within each quantile
+// interval. n is the number of non-NULL values.
+func histogramFromQuantiles(bounds []float64, n int64, bins int) []HistogramBin {
+	if len(bounds) < 2 || n <= 0 || bins <= 0 {
+		return nil
+	}
+	lo, hi := bounds[0], bounds[len(bounds)-1]
+	if hi <= lo {
+		return []HistogramBin{{Lo: lo, Hi: lo, Count: n}}
+	}
+	// cdf returns the estimated fraction of values below x.
+	intervals := float64(len(bounds) - 1)
+	cdf := func(x float64) float64 {
+		if x <= lo {
+			return 0
+		}
+		if x >= hi {
+			return 1
+		}
+		for i := 1; i < len(bounds); i++ {
+			if x < bounds[i] {
+				frac := 0.0
+				if w := bounds[i] - bounds[i-1]; w > 0 {
+					frac = (x - bounds[i-1]) / w
+				}
+				return (float64(i-1) + frac) / intervals
+			}
+		}
+		return 1
+	}
+	width := (hi - lo) / float64(bins)
+	out := make([]HistogramBin, bins)
+	var assigned int64
+	for i := range out {
+		a, b := lo+float64(i)*width, lo+float64(i+1)*width
+		out[i] = HistogramBin{Lo: a, Hi: b}
+		if i == bins-1 {
+			out[i].Count = n - assigned
+			break
+		}
+		out[i].Count = int64(float64(n)*cdf(b)+0.5) - assigned
+		assigned += out[i].Count
+	}
+	return out
+}
+
+func toInt64(v bigquery.Value) int64 {
+	switch x := v.(type) {
+	case int64:
+		return x
+	case float64:
+		return int64(x)
+	}
+	return 0
+}
+
+func toFloat64(v bigquery.Value) float64 {
+	switch x := v.(type) {
+	case float64:
+		return x
+	case int64:
+		return float64(x)
+	}
+	return 0
+}
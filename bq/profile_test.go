@@ -0,0 +1,86 @@
+package bq
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+var profileFields = []SchemaField{
+	{Name: "id", Type: "INTEGER", Mode: "REQUIRED"},
+	{Name: "odd`name", Type: "STRING"},
+	{Name: "tags", Type: "STRING", Mode: "REPEATED"},
+}
+
+func TestTableProfileSQL(t *testing.T) {
+	sql := TableProfileSQL("p.d.t", profileFields)
+	for _, want := range []string{
+		"COUNT(*) AS row_count",
+		"APPROX_QUANTILES(CAST(`id` AS FLOAT64), 10) AS c0_quantiles",
+		"APPROX_TOP_COUNT(`odd\\`name`, 5) AS c1_top",
+		"COUNTIF(`tags` IS NULL) AS c2_nulls",
+		"FROM `p.d.t`",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("missing %q in:\n%s", want, sql)
+		}
+	}
+	if strings.Contains(sql, "c1_mean") || strings.Contains(sql, "c2_distinct") {
+		t.Errorf("numeric or grouped aggregates on unsupported columns:\n%s", sql)
+	}
+}
+
+func TestParseTableProfile(t *testing.T) {
+	row := map[string]bigquery.Value{
+		"row_count":    int64(100),
+		"c0_nulls":     int64(0),
+		"c0_distinct":  int64(98),
+		"c0_min":       int64(1),
+		"c0_max":       int64(100),
+		"c0_mean":      50.5,
+		"c0_stddev":    29.0,
+		"c0_quantiles": []bigquery.Value{1.0, 10.0, 20.0, 30.0, 40.0, 50.0, 60.0, 70.0, 80.0, 90.0, 100.0},
+		"c0_top": []bigquery.Value{
+			map[string]bigquery.Value{"value": int64(7), "count": int64(2)},
+		},
+		"c1_nulls": int64(25),
+		"c2_nulls": int64(0),
+	}
+	got := parseTableProfile(row, profileFields)
+	id := got[0]
+	if id.Rows != 100 || id.Distinct != 98 || id.Min != "1" || id.Max != "100" || !id.Numeric || id.Mean != 50.5 {
+		t.Errorf("id profile = %+v", id)
+	}
+	if !reflect.DeepEqual(id.Top, []ValueCount{{Value: "7", Count: 2}}) {
+		t.Errorf("top = %+v", id.Top)
+	}
+	if len(id.Histogram) != 10 {
+		t.Errorf("histogram has %d bins, want 10", len(id.Histogram))
+	}
+	if got[1].Nulls != 25 || got[1].Numeric {
+		t.Errorf("string profile = %+v", got[1])
+	}
+	if got[2].Type != "ARRAY<STRING>" || got[2].Distinct != -1 {
+		t.Errorf("repeated profile = %+v", got[2])
+	}
+}
+
+func TestHistogramFromQuantiles(t *testing.T) {
+	// Half the values fall in [0, 1] and half in (1, 10].
+	bins := histogramFromQuantiles([]float64{0, 1, 10}, 100, 10)
+	var total int64
+	for _, b := range bins {
+		total += b.Count
+	}
+	if total != 100 {
+		t.Errorf("counts sum to %d, want 100", total)
+	}
+	if bins[0].Count <= bins[5].Count {
+		t.Errorf("dense first bin %d should outweigh sparse bins like %d", bins[0].Count, bins[5].Count)
+	}
+	if bins := histogramFromQuantiles([]float64{5, 5}, 3, 10); len(bins) != 1 || bins[0].Count != 3 {
+		t.Errorf("constant column = %+v", bins)
+	}
+}
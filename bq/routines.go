@@ -0,0 +1,167 @@
+package bq
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// Routine types.
+const (
+	RoutineScalarFunction    = "SCALAR_FUNCTION"
+	RoutineAggregateFunction = "AGGREGATE_FUNCTION"
+	RoutineTableFunction     = "TABLE_VALUED_FUNCTION"
+	RoutineProcedure         = "PROCEDURE"
+)
+
+// RoutineArgument is one argument of a routine.
+type RoutineArgument struct {
+	Name string
+	Type string // e.g. "INT64", "ARRAY<STRING>", or "ANY TYPE" for templated arguments
+	Mode string // "IN", "OUT" or "INOUT" for procedures; empty for functions
+}
+
+// RoutineInfo describes a user-defined function, table function or stored
+// procedure.
+type RoutineInfo struct {
+	ProjectID   string
+	DatasetID   string
+	RoutineID   string
+	Type        string // one of the Routine* types
+	Language    string // e.g. "SQL", "JAVASCRIPT", "PYTHON"
+	Arguments   []RoutineArgument
+	ReturnType  string // empty for procedures and when inferred; "TABLE<...>" for table functions
+	Body        string
+	Description string
+	Libraries   []string // JavaScript libraries the body imports
+	Created     time.Time
+	Modified    time.Time
+}
+
+// FullName returns the routine's "project.dataset.routine" name.
+func (r RoutineInfo) FullName() string {
+	return r.ProjectID + "." + r.DatasetID + "." + r.RoutineID
+}
+
+// Signature formats the routine's name, arguments and return type, e.g.
+// "add_tax(amount NUMERIC, rate FLOAT64) RETURNS NUMERIC".
+func (r RoutineInfo) Signature() string {
+	args := make([]string, len(r.Arguments))
+	for i, a := range r.Arguments {
+		s := a.Name + " " + a.Type
+		if a.Mode != "" && a.Mode != "IN" {
+			s = a.Mode + " " + s
+		}
+		args[i] = strings.TrimSpace(s)
+	}
+	sig := r.RoutineID + "(" + strings.Join(args, ", ") + ")"
+	if r.ReturnType != "" {
+		sig += " RETURNS " + r.ReturnType
+	}
+	return sig
+}
+
+// ListRoutines returns the names of a dataset's routines, sorted. Only
+// ProjectID, DatasetID and RoutineID are set; GetRoutine reads a routine's
+// type, arguments and body.
+func (c *Client) ListRoutines(ctx context.Context, projectID, datasetID string) ([]RoutineInfo, error) {
+	cl, err := c.getAnyClient(projectID)
+	if err != nil {
+		return nil, err
+	}
+	var out []RoutineInfo
+	it := cl.DatasetInProject(projectID, datasetID).Routines(ctx)
+	for {
+		r, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("list routines: %w", err)
+		}
+		out = append(out, RoutineInfo{ProjectID: projectID, DatasetID: datasetID, RoutineID: r.RoutineID})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].RoutineID < out[j].RoutineID })
+	return out, nil
+}
+
+// GetRoutine returns a routine's metadata.
+func (c *Client) GetRoutine(ctx context.Context, projectID, datasetID, routineID string) (*RoutineInfo, error) {
+	cl, err := c.getAnyClient(projectID)
+	if err != nil {
+		return nil, err
+	}
+	md, err := cl.DatasetInProject(projectID, datasetID).Routine(routineID).Metadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("routine metadata: %w", err)
+	}
+	info := routineInfo(projectID, datasetID, routineID, md)
+	return &info, nil
+}
+
+func routineInfo(projectID, datasetID, routineID string, md *bigquery.RoutineMetadata) RoutineInfo {
+	info := RoutineInfo{
+		ProjectID:   projectID,
+		DatasetID:   datasetID,
+		RoutineID:   routineID,
+		Type:        md.Type,
+		Language:    md.Language,
+		Body:        md.Body,
+		Description: md.Description,
+		Libraries:   md.ImportedLibraries,
+		Created:     md.CreationTime,
+		Modified:    md.LastModifiedTime,
+	}
+	for _, a := range md.Arguments {
+		if a == nil {
+			continue
+		}
+		arg := RoutineArgument{Name: a.Name, Mode: a.Mode, Type: standardSQLType(a.DataType)}
+		if a.Kind == "ANY_TYPE" {
+			arg.Type = "ANY TYPE"
+		}
+		info.Arguments = append(info.Arguments, arg)
+	}
+	switch {
+	case md.ReturnTableType != nil:
+		info.ReturnType = "TABLE<" + standardSQLFields(md.ReturnTableType.Columns) + ">"
+	case md.ReturnType != nil:
+		info.ReturnType = standardSQLType(md.ReturnType)
+	}
+	return info
+}
+
+// standardSQLType formats a type as written in SQL, e.g. ARRAY<STRUCT<a
+// INT64>>.
+func standardSQLType(t *bigquery.StandardSQLDataType) string {
+	if t == nil {
+		return ""
+	}
+	switch t.TypeKind {
+	case "ARRAY":
+		return "ARRAY<" + standardSQLType(t.ArrayElementType) + ">"
+	case "STRUCT":
+		if t.StructType == nil {
+			return "STRUCT"
+		}
+		return "STRUCT<" + standardSQLFields(t.StructType.Fields) + ">"
+	case "RANGE":
+		return "RANGE<" + standardSQLType(t.RangeElementType) + ">"
+	}
+	return t.TypeKind
+}
+
+func standardSQLFields(fields []*bigquery.StandardSQLField) string {
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f != nil {
+			out = append(out, strings.TrimSpace(f.Name+" "+standardSQLType(f.Type)))
+		}
+	}
+	return strings.Join(out, ", ")
+}
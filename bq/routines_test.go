@@ -0,0 +1,55 @@
+package bq
+
+import (
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestRoutineInfoSignature(t *testing.T) {
+	md := &bigquery.RoutineMetadata{
+		Type:     RoutineScalarFunction,
+		Language: "SQL",
+		Arguments: []*bigquery.RoutineArgument{
+			{Name: "amount", DataType: &bigquery.StandardSQLDataType{TypeKind: "NUMERIC"}},
+			{Name: "tags", DataType: &bigquery.StandardSQLDataType{TypeKind: "ARRAY",
+				ArrayElementType: &bigquery.StandardSQLDataType{TypeKind: "STRING"}}},
+			{Name: "x", Kind: "ANY_TYPE"},
+		},
+		ReturnType: &bigquery.StandardSQLDataType{TypeKind: "STRUCT", StructType: &bigquery.StandardSQLStructType{
+			Fields: []*bigquery.StandardSQLField{
+				{Name: "total", Type: &bigquery.StandardSQLDataType{TypeKind: "NUMERIC"}},
+				{Name: "n", Type: &bigquery.StandardSQLDataType{TypeKind: "INT64"}},
+			},
+		}},
+	}
+	info := routineInfo("p", "ds", "add_tax", md)
+	want := "add_tax(amount NUMERIC, tags ARRAY<STRING>, x ANY TYPE) RETURNS STRUCT<total NUMERIC, n INT64>"
+	if got := info.Signature(); got != want {
+		t.Errorf("Signature() = %q, want %q", got, want)
+	}
+	if info.FullName() != "p.ds.add_tax" {
+		t.Errorf("FullName() = %q", info.FullName())
+	}
+
+	proc := routineInfo("p", "ds", "refresh", &bigquery.RoutineMetadata{
+		Type: RoutineProcedure,
+		Arguments: []*bigquery.RoutineArgument{
+			{Name: "day", Mode: "IN", DataType: &bigquery.StandardSQLDataType{TypeKind: "DATE"}},
+			{Name: "rows", Mode: "OUT", DataType: &bigquery.StandardSQLDataType{TypeKind: "INT64"}},
+		},
+	})
+	if got, want := proc.Signature(), "refresh(day DATE, OUT rows INT64)"; got != want {
+		t.Errorf("procedure Signature() = %q, want %q", got, want)
+	}
+
+	tvf := routineInfo("p", "ds", "recent", &bigquery.RoutineMetadata{
+		Type: RoutineTableFunction,
+		ReturnTableType: &bigquery.StandardSQLTableType{Columns: []*bigquery.StandardSQLField{
+			{Name: "id", Type: &bigquery.StandardSQLDataType{TypeKind: "INT64"}},
+		}},
+	})
+	if got, want := tvf.Signature(), "recent() RETURNS TABLE<id INT64>"; got != want {
+		t.Errorf("table function Signature() = %q, want %q", got, want)
+	}
+}
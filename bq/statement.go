@@ -0,0 +1,166 @@
+package bq
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// StatementKind is the broad class of a SQL statement.
+type StatementKind int
+
+const (
+	StatementQuery  StatementKind = iota // SELECT and WITH queries
+	StatementDML                         // INSERT, UPDATE, DELETE, MERGE, TRUNCATE
+	StatementDDL                         // CREATE, ALTER, DROP, ...
+	StatementScript                      // procedural or unrecognized statements
+	StatementTemp                        // CREATE TEMP FUNCTION/TABLE, gone when the script ends
+)
+
+func (k StatementKind) String() string {
+	switch k {
+	case StatementQuery:
+		return "query"
+	case StatementDML:
+		return "DML"
+	case StatementDDL:
+		return "DDL"
+	case StatementTemp:
+		return "temporary"
+	}
+	return "script"
+}
+
+// Statement is one classified statement of a query or script.
+type Statement struct {
+	Kind   StatementKind
+	Type   string // leading keywords, e.g. "DELETE" or "DROP TABLE"
+	Target string // table or object written, without backticks; may be empty
+}
+
+// Writes reports whether the statement may change data or objects that
+// outlive the query.
+func (s Statement) Writes() bool { return s.Kind != StatementQuery && s.Kind != StatementTemp }
+
+// statementToken matches the parts of SQL the classifier skips or keeps
+// whole: comments, string literals and quoted identifiers.
+var statementToken = regexp.MustCompile("(?s)--[^\\n]*|#[^\\n]*|/\\*.*?\\*/" +
+	"|'''.*?'''|\"\"\".*?\"\"\"|'(?:\\\\.|[^'\\\\\\n])*'|\"(?:\\\\.|[^\"\\\\\\n])*\"" +
+	"|`[^`]*`")
+
+// objectName matches a possibly qualified object name, quoted whole or
+// per part.
+const objectName = "((?:`[^`]+`|[\\w-]+)(?:\\.(?:`[^`]+`|[\\w-]+)){0,2})"
+
+// statementPatterns recognize writing statements and capture their target.
+// Order matters: the first match wins.
+var statementPatterns = []struct {
+	kind StatementKind
+	re   *regexp.Regexp
+}{
+	{StatementDML, regexp.MustCompile(`(?i)^(INSERT)\s+(?:INTO\s+)?` + objectName)},
+	{StatementDML, regexp.MustCompile(`(?i)^(UPDATE)\s+` + objectName)},
+	{StatementDML, regexp.MustCompile(`(?i)^(DELETE)\s+(?:FROM\s+)?` + objectName)},
+	{StatementDML, regexp.MustCompile(`(?i)^(MERGE)\s+(?:INTO\s+)?` + objectName)},
+	{StatementDML, regexp.MustCompile(`(?i)^(TRUNCATE\s+TABLE)\s+` + objectName)},
+	{StatementTemp, regexp.MustCompile(`(?i)^(CREATE)\s+(?:OR\s+REPLACE\s+)?(?:TEMP|TEMPORARY)\s+(FUNCTION|TABLE)\s+(?:IF\s+NOT\s+EXISTS\s+)?` + objectName)},
+	{StatementDDL, regexp.MustCompile(`(?i)^(CREATE)\s+(?:OR\s+REPLACE\s+)?(?:EXTERNAL\s+|MATERIALIZED\s+|SNAPSHOT\s+|AGGREGATE\s+|TABLE\s+)?` +
+		`(TABLE|VIEW|SCHEMA|FUNCTION|PROCEDURE|MODEL|SEARCH\s+INDEX|VECTOR\s+INDEX|INDEX|ROW\s+ACCESS\s+POLICY|CAPACITY|RESERVATION|ASSIGNMENT)\s+(?:IF\s+NOT\s+EXISTS\s+)?` + objectName)},
+	{StatementDDL, regexp.MustCompile(`(?i)^(ALTER)\s+(?:MATERIALIZED\s+)?(TABLE|VIEW|SCHEMA|ORGANIZATION|PROJECT|BI_CAPACITY|CAPACITY|RESERVATION)\s+(?:IF\s+EXISTS\s+)?` + objectName)},
+	{StatementDDL, regexp.MustCompile(`(?i)^(DROP)\s+(?:EXTERNAL\s+|MATERIALIZED\s+|SNAPSHOT\s+|TABLE\s+|SEARCH\s+|VECTOR\s+)?` +
+		`(TABLE|VIEW|SCHEMA|FUNCTION|PROCEDURE|MODEL|INDEX|ROW\s+ACCESS\s+POLICY|ALL\s+ROW\s+ACCESS\s+POLICIES|CAPACITY|RESERVATION|ASSIGNMENT)\s+(?:IF\s+EXISTS\s+)?` + objectName)},
+	{StatementDDL, regexp.MustCompile(`(?i)^(UNDROP)\s+(SCHEMA)\s+(?:IF\s+NOT\s+EXISTS\s+)?` + objectName)},
+	{StatementDDL, regexp.MustCompile(`(?i)^(GRANT|REVOKE)\b()()`)},
+	{StatementDDL, regexp.MustCompile(`(?i)^(EXPORT\s+DATA|LOAD\s+DATA)\b()()`)},
+}
+
+var statementQuery = regexp.MustCompile(`(?i)^(\(\s*)*(SELECT|WITH)\b`)
+
+var statementFirstWord = regexp.MustCompile(`^[A-Za-z_]+`)
+
+// ClassifyStatements splits sqlText into statements and classifies each one
+// from its leading keywords. Comments and string literals are ignored.
+// Statements it cannot recognize, such as EXECUTE IMMEDIATE or CALL, are
+// StatementScript.
+func ClassifyStatements(sqlText string) []Statement {
+	var out []Statement
+	for _, stmt := range splitStatements(sqlText) {
+		out = append(out, classifyOne(stmt))
+	}
+	return out
+}
+
+func classifyOne(stmt string) Statement {
+	if statementQuery.MatchString(stmt) {
+		return Statement{Kind: StatementQuery, Type: "SELECT"}
+	}
+	for _, p := range statementPatterns {
+		m := p.re.FindStringSubmatch(stmt)
+		if m == nil {
+			continue
+		}
+		typ := strings.ToUpper(strings.Join(strings.Fields(m[1]), " "))
+		target := m[len(m)-1]
+		if len(m) == 4 && m[2] != "" {
+			typ += " " + strings.ToUpper(strings.Join(strings.Fields(m[2]), " "))
+		}
+		return Statement{Kind: p.kind, Type: typ, Target: strings.ReplaceAll(target, "`", "")}
+	}
+	return Statement{Kind: StatementScript, Type: strings.ToUpper(statementFirstWord.FindString(stmt))}
+}
+
+// splitStatements blanks out comments and string literals, then splits on
+// semicolons. Empty statements are dropped.
+func splitStatements(sqlText string) []string {
+	var b strings.Builder
+	last := 0
+	for _, m := range statementToken.FindAllStringIndex(sqlText, -1) {
+		b.WriteString(sqlText[last:m[0]])
+		tok := sqlText[m[0]:m[1]]
+		switch {
+		case strings.HasPrefix(tok, "`"):
+			b.WriteString(tok)
+		case strings.HasPrefix(tok, "--"), strings.HasPrefix(tok, "#"), strings.HasPrefix(tok, "/*"):
+			b.WriteString(" ")
+		default:
+			b.WriteString("''")
+		}
+		last = m[1]
+	}
+	b.WriteString(sqlText[last:])
+	var out []string
+	for _, s := range strings.Split(b.String(), ";") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// DryRunStatementType asks BigQuery for the statement type of sqlText
+// without running it, e.g. "SELECT", "DELETE" or "SCRIPT". Dry runs are
+// free.
+func (c *Client) DryRunStatementType(ctx context.Context, projectID, sqlText string) (string, error) {
+	cl, err := c.getClient(projectID)
+	if err != nil {
+		return "", err
+	}
+	q := cl.Query(sqlText)
+	q.DryRun = true
+	job, err := q.Run(ctx)
+	if err != nil {
+		return "", fmt.Errorf("dry run: %w", err)
+	}
+	status := job.LastStatus()
+	if status == nil || status.Statistics == nil {
+		return "", fmt.Errorf("dry run returned no statistics")
+	}
+	qs, ok := status.Statistics.Details.(*bigquery.QueryStatistics)
+	if !ok || qs == nil {
+		return "", fmt.Errorf("dry run returned no query statistics")
+	}
+	return qs.StatementType, nil
+}
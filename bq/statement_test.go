@@ -0,0 +1,60 @@
+package bq
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassifyStatements(t *testing.T) {
+	for sql, want := range map[string]Statement{
+		"SELECT * FROM t": {Kind: StatementQuery, Type: "SELECT"},
+		"  -- DELETE FROM t\n(WITH a AS (SELECT 1) SELECT * FROM a)":      {Kind: StatementQuery, Type: "SELECT"},
+		"select 'DROP TABLE x; DELETE FROM y'":                            {Kind: StatementQuery, Type: "SELECT"},
+		"DELETE FROM `my-proj.ds.t` WHERE true":                           {Kind: StatementDML, Type: "DELETE", Target: "my-proj.ds.t"},
+		"DELETE FROM `my-proj`.ds.`t` WHERE true":                         {Kind: StatementDML, Type: "DELETE", Target: "my-proj.ds.t"},
+		"delete ds.t where x = 1":                                         {Kind: StatementDML, Type: "DELETE", Target: "ds.t"},
+		"INSERT INTO ds.t (a) VALUES (1)":                                 {Kind: StatementDML, Type: "INSERT", Target: "ds.t"},
+		"UPDATE ds.t SET a = 1 WHERE true":                                {Kind: StatementDML, Type: "UPDATE", Target: "ds.t"},
+		"MERGE ds.t T USING ds.s S ON T.id = S.id":                        {Kind: StatementDML, Type: "MERGE", Target: "ds.t"},
+		"truncate table ds.t":                                             {Kind: StatementDML, Type: "TRUNCATE TABLE", Target: "ds.t"},
+		"DROP TABLE IF EXISTS ds.t":                                       {Kind: StatementDDL, Type: "DROP TABLE", Target: "ds.t"},
+		"CREATE OR REPLACE TABLE ds.t AS SELECT 1":                        {Kind: StatementDDL, Type: "CREATE TABLE", Target: "ds.t"},
+		"CREATE TEMP FUNCTION f(x INT64) AS (x)":                          {Kind: StatementTemp, Type: "CREATE FUNCTION", Target: "f"},
+		"create or replace temporary table tmp as select 1":               {Kind: StatementTemp, Type: "CREATE TABLE", Target: "tmp"},
+		"CREATE FUNCTION ds.f(x INT64) AS (x)":                            {Kind: StatementDDL, Type: "CREATE FUNCTION", Target: "ds.f"},
+		"create materialized view if not exists ds.v as select 1":         {Kind: StatementDDL, Type: "CREATE VIEW", Target: "ds.v"},
+		"ALTER TABLE ds.t ADD COLUMN c INT64":                             {Kind: StatementDDL, Type: "ALTER TABLE", Target: "ds.t"},
+		"DROP SCHEMA ds CASCADE":                                          {Kind: StatementDDL, Type: "DROP SCHEMA", Target: "ds"},
+		"GRANT `roles/bigquery.dataViewer` ON TABLE ds.t TO 'user:a@b.c'": {Kind: StatementDDL, Type: "GRANT"},
+		"EXECUTE IMMEDIATE 'DELETE FROM t WHERE true'":                    {Kind: StatementScript, Type: "EXECUTE"},
+		"CALL ds.proc()":                                                  {Kind: StatementScript, Type: "CALL"},
+	} {
+		got := ClassifyStatements(sql)
+		if len(got) != 1 || !reflect.DeepEqual(got[0], want) {
+			t.Errorf("ClassifyStatements(%q) = %+v, want %+v", sql, got, want)
+		}
+	}
+}
+
+func TestClassifyScript(t *testing.T) {
+	got := ClassifyStatements("SELECT 1;\n/* cleanup; */ DELETE FROM ds.t WHERE day < '2020-01-01;';\n;")
+	want := []Statement{
+		{Kind: StatementQuery, Type: "SELECT"},
+		{Kind: StatementDML, Type: "DELETE", Target: "ds.t"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if got[0].Writes() || !got[1].Writes() {
+		t.Error("Writes should be false only for queries")
+	}
+	temp := ClassifyStatements("CREATE TEMP FUNCTION f(x INT64) AS (x + 1);\nCREATE TEMP TABLE t AS SELECT f(1) AS y;\nSELECT * FROM t")
+	for _, s := range temp {
+		if s.Writes() {
+			t.Errorf("%+v should not count as a write", s)
+		}
+	}
+	if ClassifyStatements(" -- nothing\n ") != nil {
+		t.Error("comments only should have no statements")
+	}
+}
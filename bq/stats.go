@@ -0,0 +1,181 @@
+package bq
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// QueryCost is what a query run on the user's behalf, outside the editor,
+// processed and billed.
+type QueryCost struct {
+	SQL            string
+	BytesProcessed int64
+	BytesBilled    int64
+	CacheHit       bool
+}
+
+// runQueryJob runs q, waits for it to finish and returns its rows with its
+// cost. Errors are returned as is, for the caller to wrap.
+func runQueryJob(ctx context.Context, q *bigquery.Query) (*bigquery.RowIterator, *QueryCost, error) {
+	job, err := q.Run(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := status.Err(); err != nil {
+		return nil, nil, err
+	}
+	cost := &QueryCost{SQL: q.Q}
+	if js := status.Statistics; js != nil {
+		cost.BytesProcessed = js.TotalBytesProcessed
+		cost.BytesBilled = js.TotalBytesProcessed
+		if qs := queryStatsFrom(js); qs != nil {
+			cost.BytesBilled, cost.CacheHit = qs.BytesBilled, qs.CacheHit
+		}
+	}
+	it, err := job.Read(ctx)
+	if err != nil {
+		return nil, cost, err
+	}
+	return it, cost, nil
+}
+
+// QueryStats holds the execution statistics of a finished query job.
+type QueryStats struct {
+	Created, Started, Ended time.Time
+
+	StatementType    string
+	CacheHit         bool
+	BytesProcessed   int64
+	BytesBilled      int64
+	SlotMillis       int64
+	ReferencedTables []string // fully qualified, project.dataset.table
+
+	Stages   []QueryStage
+	Timeline []TimelineSample
+}
+
+// QueryStage is one stage of the query plan. Ratios are relative to the
+// longest time any worker spent in any stage of the query.
+type QueryStage struct {
+	ID          int64
+	Name        string
+	Status      string
+	InputStages []int64
+
+	RecordsRead    int64
+	RecordsWritten int64
+	ShuffleBytes   int64
+	ShuffleSpilled int64
+
+	ParallelInputs  int64
+	CompletedInputs int64
+
+	WaitAvg, WaitMax       time.Duration
+	ReadAvg, ReadMax       time.Duration
+	ComputeAvg, ComputeMax time.Duration
+	WriteAvg, WriteMax     time.Duration
+
+	WaitRatioAvg, WaitRatioMax       float64
+	ReadRatioAvg, ReadRatioMax       float64
+	ComputeRatioAvg, ComputeRatioMax float64
+	WriteRatioAvg, WriteRatioMax     float64
+
+	Start, End time.Time
+	Steps      []string // e.g. "READ: $1, $2 FROM t"
+}
+
+// TimelineSample is a point of the query's slot usage over time.
+type TimelineSample struct {
+	Elapsed        time.Duration
+	ActiveUnits    int64
+	PendingUnits   int64
+	CompletedUnits int64
+	SlotMillis     int64 // cumulative
+}
+
+// queryStatsFrom extracts query statistics from a job's status. It returns
+// nil when the job has no query statistics.
+func queryStatsFrom(js *bigquery.JobStatistics) *QueryStats {
+	if js == nil {
+		return nil
+	}
+	qs, ok := js.Details.(*bigquery.QueryStatistics)
+	if !ok || qs == nil {
+		return nil
+	}
+	s := &QueryStats{
+		Created:        js.CreationTime,
+		Started:        js.StartTime,
+		Ended:          js.EndTime,
+		StatementType:  qs.StatementType,
+		CacheHit:       qs.CacheHit,
+		BytesProcessed: qs.TotalBytesProcessed,
+		BytesBilled:    qs.TotalBytesBilled,
+		SlotMillis:     qs.SlotMillis,
+	}
+	for _, t := range qs.ReferencedTables {
+		if t != nil {
+			s.ReferencedTables = append(s.ReferencedTables, t.ProjectID+"."+t.DatasetID+"."+t.TableID)
+		}
+	}
+	for _, st := range qs.QueryPlan {
+		if st == nil {
+			continue
+		}
+		stage := QueryStage{
+			ID:              st.ID,
+			Name:            st.Name,
+			Status:          st.Status,
+			InputStages:     st.InputStages,
+			RecordsRead:     st.RecordsRead,
+			RecordsWritten:  st.RecordsWritten,
+			ShuffleBytes:    st.ShuffleOutputBytes,
+			ShuffleSpilled:  st.ShuffleOutputBytesSpilled,
+			ParallelInputs:  st.ParallelInputs,
+			CompletedInputs: st.CompletedParallelInputs,
+			WaitAvg:         st.WaitAvg,
+			WaitMax:         st.WaitMax,
+			ReadAvg:         st.ReadAvg,
+			ReadMax:         st.ReadMax,
+			ComputeAvg:      st.ComputeAvg,
+			ComputeMax:      st.ComputeMax,
+			WriteAvg:        st.WriteAvg,
+			WriteMax:        st.WriteMax,
+			WaitRatioAvg:    st.WaitRatioAvg,
+			WaitRatioMax:    st.WaitRatioMax,
+			ReadRatioAvg:    st.ReadRatioAvg,
+			ReadRatioMax:    st.ReadRatioMax,
+			ComputeRatioAvg: st.ComputeRatioAvg,
+			ComputeRatioMax: st.ComputeRatioMax,
+			WriteRatioAvg:   st.WriteRatioAvg,
+			WriteRatioMax:   st.WriteRatioMax,
+			Start:           st.StartTime,
+			End:             st.EndTime,
+		}
+		for _, step := range st.Steps {
+			if step != nil {
+				stage.Steps = append(stage.Steps, step.Kind+": "+strings.Join(step.Substeps, ", "))
+			}
+		}
+		s.Stages = append(s.Stages, stage)
+	}
+	for _, t := range qs.Timeline {
+		if t != nil {
+			s.Timeline = append(s.Timeline, TimelineSample{
+				Elapsed:        t.Elapsed,
+				ActiveUnits:    t.ActiveUnits,
+				PendingUnits:   t.PendingUnits,
+				CompletedUnits: t.CompletedUnits,
+				SlotMillis:     t.SlotMillis,
+			})
+		}
+	}
+	return s
+}
@@ -0,0 +1,55 @@
+package bq
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func TestQueryStatsFrom(t *testing.T) {
+	js := &bigquery.JobStatistics{
+		StartTime: time.Unix(100, 0),
+		EndTime:   time.Unix(103, 0),
+		Details: &bigquery.QueryStatistics{
+			StatementType:       "SELECT",
+			TotalBytesProcessed: 2048,
+			SlotMillis:          5000,
+			ReferencedTables:    []*bigquery.Table{{ProjectID: "p", DatasetID: "d", TableID: "t"}},
+			QueryPlan: []*bigquery.ExplainQueryStage{
+				{ID: 0, Name: "S00: Input", RecordsRead: 10, RecordsWritten: 10,
+					Steps: []*bigquery.ExplainQueryStep{{Kind: "READ", Substeps: []string{"$1", "FROM d.t"}}}},
+				nil,
+				{ID: 1, Name: "S01: Output", InputStages: []int64{0}, ComputeMax: 4 * time.Second, ComputeAvg: time.Second},
+			},
+			Timeline: []*bigquery.QueryTimelineSample{{Elapsed: time.Second, ActiveUnits: 3, SlotMillis: 900}},
+		},
+	}
+	s := queryStatsFrom(js)
+	if s == nil {
+		t.Fatal("no stats")
+	}
+	if s.StatementType != "SELECT" || s.BytesProcessed != 2048 || s.SlotMillis != 5000 {
+		t.Errorf("summary = %+v", s)
+	}
+	if !reflect.DeepEqual(s.ReferencedTables, []string{"p.d.t"}) {
+		t.Errorf("tables = %v", s.ReferencedTables)
+	}
+	if len(s.Stages) != 2 {
+		t.Fatalf("stages = %d, want nil stages skipped", len(s.Stages))
+	}
+	if !reflect.DeepEqual(s.Stages[0].Steps, []string{"READ: $1, FROM d.t"}) {
+		t.Errorf("steps = %q", s.Stages[0].Steps)
+	}
+	if s.Stages[1].ComputeMax != 4*time.Second || !reflect.DeepEqual(s.Stages[1].InputStages, []int64{0}) {
+		t.Errorf("stage 1 = %+v", s.Stages[1])
+	}
+	if len(s.Timeline) != 1 || s.Timeline[0].SlotMillis != 900 {
+		t.Errorf("timeline = %+v", s.Timeline)
+	}
+
+	if queryStatsFrom(nil) != nil || queryStatsFrom(&bigquery.JobStatistics{}) != nil {
+		t.Error("jobs without query statistics should have no stats")
+	}
+}
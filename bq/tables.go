@@ -0,0 +1,171 @@
+package bq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// Operations for CopyTable.
+const (
+	OperationCopy     = "COPY"     // a full, independent copy
+	OperationSnapshot = "SNAPSHOT" // a read-only, point-in-time snapshot
+	OperationClone    = "CLONE"    // a writable copy billed only for changed data
+)
+
+// TableInfo is the editable metadata of a table.
+type TableInfo struct {
+	Type        string // e.g. TABLE, VIEW, SNAPSHOT, MATERIALIZED_VIEW
+	Description string
+	Labels      map[string]string
+	Expiration  time.Time // zero if the table does not expire
+	Created     time.Time
+	Modified    time.Time
+	NumRows     uint64
+	NumBytes    int64
+	Location    string
+	ETag        string // version of the metadata, for UpdateTableMetadata
+}
+
+// GetTableInfo returns a table's metadata.
+func (c *Client) GetTableInfo(ctx context.Context, projectID, datasetID, tableID string) (*TableInfo, error) {
+	cl, err := c.getAnyClient(projectID)
+	if err != nil {
+		return nil, err
+	}
+	md, err := cl.DatasetInProject(projectID, datasetID).Table(tableID).Metadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("table metadata: %w", err)
+	}
+	return &TableInfo{
+		Type:        string(md.Type),
+		Description: md.Description,
+		Labels:      md.Labels,
+		Expiration:  md.ExpirationTime,
+		Created:     md.CreationTime,
+		Modified:    md.LastModifiedTime,
+		NumRows:     md.NumRows,
+		NumBytes:    md.NumBytes,
+		Location:    md.Location,
+		ETag:        md.ETag,
+	}, nil
+}
+
+// CopyTable copies a table to dst with a copy job run in dst's project.
+// operation is OperationCopy, OperationSnapshot or OperationClone;
+// snapshots and clones need a new destination table. A dst.Expiration is
+// set on the new table once it exists. It returns the job ID.
+func (c *Client) CopyTable(ctx context.Context, projectID, datasetID, tableID string, dst TableDestination, operation string, labels map[string]string) (string, error) {
+	cl, err := c.getClient(dst.ProjectID)
+	if err != nil {
+		return "", err
+	}
+	src := cl.DatasetInProject(projectID, datasetID).Table(tableID)
+	table := cl.DatasetInProject(dst.ProjectID, dst.DatasetID).Table(dst.TableID)
+	copier := table.CopierFrom(src)
+	copier.OperationType = bigquery.TableCopyOperationType(operation)
+	copier.CreateDisposition = bigquery.CreateIfNeeded
+	copier.WriteDisposition = writeDisposition(dst.WriteDisposition)
+	if operation != OperationCopy {
+		copier.WriteDisposition = bigquery.WriteEmpty
+	}
+	if labels := SanitizeLabels(labels); len(labels) > 0 {
+		copier.Labels = labels
+	}
+
+	job, err := copier.Run(ctx)
+	if err != nil {
+		return "", fmt.Errorf("copy table: %w", err)
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return job.ID(), fmt.Errorf("wait copy: %w", err)
+	}
+	if status.Err() != nil {
+		return job.ID(), fmt.Errorf("copy error: %w", status.Err())
+	}
+	if dst.Expiration > 0 {
+		if _, err := table.Update(ctx, bigquery.TableMetadataToUpdate{
+			ExpirationTime: time.Now().Add(dst.Expiration),
+		}, ""); err != nil {
+			return job.ID(), fmt.Errorf("set expiration: %w", err)
+		}
+	}
+	return job.ID(), nil
+}
+
+// RenameTable renames a table within its dataset with an ALTER TABLE
+// statement and returns the statement's cost.
+func (c *Client) RenameTable(ctx context.Context, projectID, datasetID, tableID, newTableID string, opts QueryOptions) (*QueryCost, error) {
+	cl, err := c.getClient(projectID)
+	if err != nil {
+		return nil, err
+	}
+	q := cl.Query(fmt.Sprintf("ALTER TABLE `%s.%s.%s` RENAME TO `%s`", projectID, datasetID, tableID, newTableID))
+	opts.apply(q)
+	_, cost, err := runQueryJob(ctx, q)
+	if err != nil {
+		return cost, fmt.Errorf("rename table: %w", err)
+	}
+	return cost, nil
+}
+
+// SetTableExpiration sets when a table is deleted; the zero time removes
+// its expiration.
+func (c *Client) SetTableExpiration(ctx context.Context, projectID, datasetID, tableID string, expiration time.Time) error {
+	cl, err := c.getAnyClient(projectID)
+	if err != nil {
+		return err
+	}
+	if expiration.IsZero() {
+		expiration = bigquery.NeverExpire
+	}
+	_, err = cl.DatasetInProject(projectID, datasetID).Table(tableID).Update(ctx, bigquery.TableMetadataToUpdate{
+		ExpirationTime: expiration,
+	}, "")
+	if err != nil {
+		return fmt.Errorf("set expiration: %w", err)
+	}
+	return nil
+}
+
+// UpdateTableMetadata replaces a table's description and labels. from is
+// the metadata the edit started from, as returned by GetTableInfo; the
+// update fails if the table changed since, rather than overwrite a
+// concurrent edit.
+func (c *Client) UpdateTableMetadata(ctx context.Context, projectID, datasetID, tableID string, from *TableInfo, description string, labels map[string]string) error {
+	cl, err := c.getAnyClient(projectID)
+	if err != nil {
+		return err
+	}
+	table := cl.DatasetInProject(projectID, datasetID).Table(tableID)
+	var upd bigquery.TableMetadataToUpdate
+	upd.Description = description
+	labels = SanitizeLabels(labels)
+	for k := range from.Labels {
+		if _, ok := labels[k]; !ok {
+			upd.DeleteLabel(k)
+		}
+	}
+	for k, v := range labels {
+		upd.SetLabel(k, v)
+	}
+	if _, err := table.Update(ctx, upd, from.ETag); err != nil {
+		return fmt.Errorf("update table: %w", err)
+	}
+	return nil
+}
+
+// DeleteTable deletes a table, view or snapshot.
+func (c *Client) DeleteTable(ctx context.Context, projectID, datasetID, tableID string) error {
+	cl, err := c.getAnyClient(projectID)
+	if err != nil {
+		return err
+	}
+	if err := cl.DatasetInProject(projectID, datasetID).Table(tableID).Delete(ctx); err != nil {
+		return fmt.Errorf("delete table: %w", err)
+	}
+	return nil
+}
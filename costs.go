@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/farbodahm/delephon/bq"
+	"github.com/farbodahm/delephon/store"
+	"github.com/farbodahm/delephon/ui"
+)
+
+// defaultTiBRate is BigQuery's list on-demand price per TiB billed, in
+// dollars, used when no rate is configured.
+const defaultTiBRate = 6.25
+
+// Settings keys for the on-demand rate; a project's own rate takes
+// precedence over the global one.
+const (
+	tibRateKey        = "tib_rate"
+	tibRateProjectKey = "tib_rate/"
+)
+
+// tibRate returns the on-demand price per TiB for queries billed to project.
+func (a *App) tibRate(project string) float64 {
+	for _, key := range []string{tibRateProjectKey + project, tibRateKey} {
+		if v, _ := a.store.GetSetting(key); v != "" {
+			if f, err := strconv.ParseFloat(v, 64); err == nil && f >= 0 {
+				return f
+			}
+		}
+	}
+	return defaultTiBRate
+}
+
+// estimateCost returns the on-demand cost of billing bytes at rate dollars
+// per TiB.
+func estimateCost(bytes int64, rate float64) float64 {
+	return float64(bytes) / (1 << 40) * rate
+}
+
+// rateSetting validates a dollars per TiB entry; empty means unset.
+func rateSetting(text string) (string, error) {
+	text = strings.TrimPrefix(strings.TrimSpace(text), "$")
+	if text == "" {
+		return "", nil
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil || f < 0 {
+		return "", fmt.Errorf("invalid rate %q; enter dollars per TiB, e.g. 6.25", text)
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64), nil
+}
+
+// recordCost adds a finished query to the cost ledger. Cached queries are
+// recorded too, at no cost, so repeated runs show up.
+func (a *App) recordCost(source, project, sqlText string, historyID, bytesProcessed, bytesBilled int64, cacheHit bool) {
+	rate := a.tibRate(project)
+	_ = a.store.AddCost(store.CostEntry{
+		HistoryID:      historyID,
+		Source:         source,
+		Project:        project,
+		SQL:            sqlText,
+		BytesProcessed: bytesProcessed,
+		BytesBilled:    bytesBilled,
+		CacheHit:       cacheHit,
+		RatePerTiB:     rate,
+		Cost:           estimateCost(bytesBilled, rate),
+	})
+	a.refreshSpend()
+}
+
+// recordQueryCost records a RunQuery result, falling back to bytes
+// processed when the job reported no bytes billed.
+func (a *App) recordQueryCost(source, project string, historyID int64, result *bq.QueryResult) {
+	billed, cacheHit := result.BytesProcessed, false
+	if s := result.Stats; s != nil {
+		billed, cacheHit = s.BytesBilled, s.CacheHit
+	}
+	a.recordCost(source, project, result.SQL, historyID, result.BytesProcessed, billed, cacheHit)
+}
+
+// recordJobCost records a query Delephon ran on the user's behalf, such as a
+// profile scan. A nil cost, for a query that did not run, is ignored.
+func (a *App) recordJobCost(source, project string, cost *bq.QueryCost) {
+	if cost == nil {
+		return
+	}
+	a.recordCost(source, project, cost.SQL, 0, cost.BytesProcessed, cost.BytesBilled, cost.CacheHit)
+}
+
+// refreshSpend reloads the Spend tab for its selected grouping and period.
+func (a *App) refreshSpend() {
+	view, since := a.spend.View(), a.spend.Since()
+	group := store.SpendByDay
+	switch view {
+	case ui.SpendProjects:
+		group = store.SpendByProject
+	case ui.SpendQueries, ui.SpendRepeated:
+		group = store.SpendByQuery
+	}
+	groups, err := a.store.SpendBy(group, since)
+	if err != nil {
+		a.spend.SetStatus(fmt.Sprintf("Error: %v", err))
+		return
+	}
+	rows := make([]ui.SpendRow, len(groups))
+	for i, g := range groups {
+		rows[i] = ui.SpendRow{
+			Key:         g.Key,
+			SQL:         g.SQL,
+			Projects:    g.Projects,
+			Runs:        g.Runs,
+			BytesBilled: g.BytesBilled,
+			Cost:        g.Cost,
+			Last:        g.Last,
+		}
+	}
+	a.spend.SetRows(rows)
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestEstimateCost(t *testing.T) {
+	if got := estimateCost(1<<40, defaultTiBRate); got != defaultTiBRate {
+		t.Errorf("1 TiB = $%v, want $%v", got, defaultTiBRate)
+	}
+	if got := estimateCost(10<<30, 5); got != 5.0/1024*10 {
+		t.Errorf("10 GiB at $5 = $%v", got)
+	}
+	if estimateCost(0, 6.25) != 0 {
+		t.Error("cached queries should cost nothing")
+	}
+}
+
+func TestRateSetting(t *testing.T) {
+	for in, want := range map[string]string{"": "", " $6.25 ": "6.25", "5": "5", "0": "0"} {
+		if got, err := rateSetting(in); err != nil || got != want {
+			t.Errorf("rateSetting(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"abc", "-1"} {
+		if _, err := rateSetting(in); err == nil {
+			t.Errorf("rateSetting(%q) should fail", in)
+		}
+	}
+}
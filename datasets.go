@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/farbodahm/delephon/bq"
+	"github.com/farbodahm/delephon/store"
+	"github.com/farbodahm/delephon/ui"
+)
+
+// datasetIDPattern is what BigQuery accepts as a dataset name.
+var datasetIDPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// datasetLocations are offered when creating a dataset: the job browser's
+// regions, with the multi-regions spelled as BigQuery reports them.
+func datasetLocations() []string {
+	locations := make([]string, len(ui.JobRegions))
+	for i, r := range ui.JobRegions {
+		if !strings.Contains(r, "-") {
+			r = strings.ToUpper(r)
+		}
+		locations[i] = r
+	}
+	return locations
+}
+
+// datasetAction starts a change to a dataset chosen from the explorer.
+func (a *App) datasetAction(action ui.DatasetAction, project, dataset string) {
+	switch action {
+	case ui.DatasetCreate:
+		a.showCreateDatasetDialog(project)
+	case ui.DatasetDetails:
+		go a.showDatasetInfo(project, dataset)
+	case ui.DatasetEdit:
+		go a.showEditDatasetDialog(project, dataset)
+	}
+}
+
+// parseExpirationDays parses a default table expiration given in days;
+// blank means tables do not expire.
+func parseExpirationDays(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	days, err := strconv.ParseFloat(s, 64)
+	if err != nil || days <= 0 {
+		return 0, errors.New("enter a number of days, or leave blank for never")
+	}
+	d := time.Duration(days * 24 * float64(time.Hour))
+	if d < time.Hour {
+		return 0, errors.New("must be at least one hour")
+	}
+	return d, nil
+}
+
+// formatExpirationDays is the inverse of parseExpirationDays.
+func formatExpirationDays(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(d.Hours()/24, 'f', -1, 64)
+}
+
+// describeExpiration formats a default table expiration for display and
+// the audit log.
+func describeExpiration(d time.Duration) string {
+	if d <= 0 {
+		return "never"
+	}
+	days := formatExpirationDays(d)
+	if days == "1" {
+		return "1 day"
+	}
+	return days + " days"
+}
+
+// newDatasetFields returns the entries shared by the create and edit
+// dialogs, filled from spec.
+func newDatasetFields(spec bq.DatasetSpec) (desc, labels, expiration *widget.Entry) {
+	desc = widget.NewMultiLineEntry()
+	desc.Wrapping = fyne.TextWrapWord
+	desc.SetMinRowsVisible(3)
+	desc.SetText(spec.Description)
+	labels = widget.NewEntry()
+	labels.SetText(bq.FormatLabels(spec.Labels))
+	labels.SetPlaceHolder("e.g. owner=finance, tier=gold")
+	labels.Validator = func(s string) error {
+		_, err := bq.ParseLabels(s)
+		return err
+	}
+	expiration = widget.NewEntry()
+	expiration.SetText(formatExpirationDays(spec.DefaultTableExpiration))
+	expiration.SetPlaceHolder("never")
+	expiration.Validator = func(s string) error {
+		_, err := parseExpirationDays(s)
+		return err
+	}
+	return desc, labels, expiration
+}
+
+// showCreateDatasetDialog asks for a new dataset's name, location and
+// defaults, then creates it in project.
+func (a *App) showCreateDatasetDialog(project string) {
+	idEntry := widget.NewEntry()
+	idEntry.SetPlaceHolder("dataset")
+	idEntry.Validator = func(s string) error {
+		s = strings.TrimSpace(s)
+		switch {
+		case s == "":
+			return errors.New("name is required")
+		case len(s) > 1024 || !datasetIDPattern.MatchString(s):
+			return errors.New("use letters, digits and underscores")
+		}
+		return nil
+	}
+	locationEntry := widget.NewSelectEntry(datasetLocations())
+	locationEntry.SetText("US")
+	descEntry, labelsEntry, expirationEntry := newDatasetFields(bq.DatasetSpec{})
+	hint := widget.NewLabel("The location cannot be changed later. Tables created in the dataset " +
+		"are deleted after the default expiration unless they set their own.")
+	hint.Wrapping = fyne.TextWrapWord
+
+	d := dialog.NewForm("Create Dataset", "Create", "Cancel",
+		[]*widget.FormItem{
+			widget.NewFormItem("Project", widget.NewLabel(project)),
+			widget.NewFormItem("Dataset", idEntry),
+			widget.NewFormItem("Location", locationEntry),
+			widget.NewFormItem("Default table expiration (days)", expirationEntry),
+			widget.NewFormItem("Labels", labelsEntry),
+			widget.NewFormItem("Description", descEntry),
+			widget.NewFormItem("", hint),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			spec := bq.DatasetSpec{
+				DatasetID:   strings.TrimSpace(idEntry.Text),
+				Location:    strings.TrimSpace(locationEntry.Text),
+				Description: descEntry.Text,
+			}
+			var err error
+			if spec.Labels, err = bq.ParseLabels(labelsEntry.Text); err != nil {
+				a.showError("Create Dataset", err)
+				return
+			}
+			if spec.DefaultTableExpiration, err = parseExpirationDays(expirationEntry.Text); err != nil {
+				a.showError("Create Dataset", fmt.Errorf("default table expiration: %w", err))
+				return
+			}
+			target := project + "." + spec.DatasetID
+			if !a.projectWritable(project, auditCreateDataset, target) {
+				return
+			}
+			e := store.AuditEntry{Action: auditCreateDataset, Project: project, Target: target,
+				Detail: fmt.Sprintf("location %s | default expiration %s | labels: %s",
+					spec.Location, describeExpiration(spec.DefaultTableExpiration), bq.FormatLabels(spec.Labels))}
+			go a.changeTable(e, "Create dataset "+target, []string{ui.ProjectNodeID(project)},
+				func(ctx context.Context) (string, error) {
+					return "", a.bqMgr.CreateDataset(ctx, project, spec)
+				})
+		}, a.window)
+	d.Resize(fyne.NewSize(560, 0))
+	d.Show()
+}
+
+// showDatasetInfo shows a dataset's metadata and who can access it.
+func (a *App) showDatasetInfo(project, dataset string) {
+	info, err := a.bqMgr.GetDatasetInfo(a.ctx, project, dataset)
+	if err != nil {
+		a.showError("Dataset Info", err)
+		return
+	}
+	fyne.Do(func() {
+		value := func(s string) *widget.Label {
+			l := widget.NewLabel(s)
+			l.Wrapping = fyne.TextWrapWord
+			l.Selectable = true
+			return l
+		}
+		labels := bq.FormatLabels(info.Labels)
+		if labels == "" {
+			labels = "none"
+		}
+		form := widget.NewForm(
+			widget.NewFormItem("Dataset", value(project+"."+dataset)),
+			widget.NewFormItem("Location", value(info.Location)),
+			widget.NewFormItem("Description", value(info.Description)),
+			widget.NewFormItem("Labels", value(labels)),
+			widget.NewFormItem("Default table expiration", value(describeExpiration(info.DefaultTableExpiration))),
+			widget.NewFormItem("Created", value(info.Created.Local().Format("2006-01-02 15:04"))),
+			widget.NewFormItem("Last modified", value(info.Modified.Local().Format("2006-01-02 15:04"))),
+		)
+
+		headers := []string{"Role", "Type", "Member"}
+		access := widget.NewTableWithHeaders(
+			func() (int, int) { return len(info.Access), len(headers) },
+			func() fyne.CanvasObject {
+				l := widget.NewLabel("")
+				l.Truncation = fyne.TextTruncateEllipsis
+				return l
+			},
+			func(id widget.TableCellID, obj fyne.CanvasObject) {
+				e := info.Access[id.Row]
+				obj.(*widget.Label).SetText([]string{e.Role, e.EntityType, e.Entity}[id.Col])
+			},
+		)
+		access.ShowHeaderColumn = false
+		access.UpdateHeader = func(id widget.TableCellID, template fyne.CanvasObject) {
+			if id.Row < 0 && id.Col >= 0 && id.Col < len(headers) {
+				template.(*widget.Label).SetText(headers[id.Col])
+			}
+		}
+		access.SetColumnWidth(0, 180)
+		access.SetColumnWidth(1, 140)
+		access.SetColumnWidth(2, 340)
+
+		accessTitle := widget.NewLabelWithStyle(fmt.Sprintf("Access (%d entries)", len(info.Access)),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		content := container.NewBorder(container.NewVBox(form, accessTitle), nil, nil, nil, access)
+		d := dialog.NewCustom("Dataset Info", "Close", content, a.window)
+		d.Resize(fyne.NewSize(720, 560))
+		d.Show()
+	})
+}
+
+// showEditDatasetDialog edits a dataset's description, labels and default
+// table expiration.
+func (a *App) showEditDatasetDialog(project, dataset string) {
+	target := project + "." + dataset
+	if !a.projectWritable(project, auditEditDataset, target) {
+		return
+	}
+	info, err := a.bqMgr.GetDatasetInfo(a.ctx, project, dataset)
+	if err != nil {
+		a.showError("Edit Dataset", err)
+		return
+	}
+	fyne.Do(func() {
+		descEntry, labelsEntry, expirationEntry := newDatasetFields(info.DatasetSpec)
+		hint := widget.NewLabel("A new default expiration applies only to tables created afterwards.")
+		hint.Wrapping = fyne.TextWrapWord
+		d := dialog.NewForm("Edit Dataset", "Save", "Cancel",
+			[]*widget.FormItem{
+				widget.NewFormItem("Dataset", widget.NewLabel(target)),
+				widget.NewFormItem("Description", descEntry),
+				widget.NewFormItem("Labels", labelsEntry),
+				widget.NewFormItem("Default table expiration (days)", expirationEntry),
+				widget.NewFormItem("", hint),
+			},
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				spec := bq.DatasetSpec{Description: descEntry.Text}
+				var err error
+				if spec.Labels, err = bq.ParseLabels(labelsEntry.Text); err != nil {
+					a.showError("Edit Dataset", err)
+					return
+				}
+				if spec.DefaultTableExpiration, err = parseExpirationDays(expirationEntry.Text); err != nil {
+					a.showError("Edit Dataset", fmt.Errorf("default table expiration: %w", err))
+					return
+				}
+				e := store.AuditEntry{Action: auditEditDataset, Project: project, Target: target,
+					Detail: fmt.Sprintf("default expiration %s | labels: %s",
+						describeExpiration(spec.DefaultTableExpiration), bq.FormatLabels(spec.Labels))}
+				go a.changeTable(e, "Edit dataset "+target, nil,
+					func(ctx context.Context) (string, error) {
+						return "", a.bqMgr.UpdateDataset(ctx, project, dataset, info, spec)
+					})
+			}, a.window)
+		d.Resize(fyne.NewSize(560, 0))
+		d.Show()
+	})
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseExpirationDays(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"":     0,
+		"  ":   0,
+		"7":    7 * 24 * time.Hour,
+		"0.5":  12 * time.Hour,
+		" 30 ": 30 * 24 * time.Hour,
+	} {
+		got, err := parseExpirationDays(in)
+		if err != nil || got != want {
+			t.Errorf("parseExpirationDays(%q) = %v, %v; want %v", in, got, err, want)
+		}
+		if back, _ := parseExpirationDays(formatExpirationDays(got)); back != got {
+			t.Errorf("round trip of %v gave %v", got, back)
+		}
+	}
+	for _, in := range []string{"0", "-1", "week", "0.01"} {
+		if _, err := parseExpirationDays(in); err == nil {
+			t.Errorf("parseExpirationDays(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestDescribeExpiration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		0:                   "never",
+		24 * time.Hour:      "1 day",
+		60 * 24 * time.Hour: "60 days",
+		36 * time.Hour:      "1.5 days",
+	} {
+		if got := describeExpiration(d); got != want {
+			t.Errorf("describeExpiration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestDatasetLocations(t *testing.T) {
+	locations := datasetLocations()
+	if locations[0] != "US" || locations[1] != "EU" {
+		t.Errorf("multi-regions = %q, want US and EU first", locations[:2])
+	}
+	for _, l := range locations[2:] {
+		if l != "" && l[0] >= 'A' && l[0] <= 'Z' {
+			t.Errorf("region %q should stay lower case", l)
+		}
+	}
+}
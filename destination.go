@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/farbodahm/delephon/bq"
+	"github.com/farbodahm/delephon/store"
+	"github.com/farbodahm/delephon/ui"
+)
+
+// Choices offered by the "Save as BigQuery Table" dialog.
+var (
+	writeDispositionLabels = map[string]string{
+		"Fail if the table has data": bq.WriteEmpty,
+		"Overwrite the table":        bq.WriteTruncate,
+		"Append to the table":        bq.WriteAppend,
+	}
+	writeDispositionOptions = []string{"Fail if the table has data", "Overwrite the table", "Append to the table"}
+
+	expirationOptions = []string{"Never", "1 day", "7 days", "30 days", "90 days"}
+	expirations       = map[string]time.Duration{
+		"1 day":   24 * time.Hour,
+		"7 days":  7 * 24 * time.Hour,
+		"30 days": 30 * 24 * time.Hour,
+		"90 days": 90 * 24 * time.Hour,
+	}
+)
+
+const (
+	partitionNone      = "None"
+	partitionIngestion = "Ingestion time"
+)
+
+// saveResultsAsTable asks for a destination table and copies the full
+// result of the query on screen to it, without running the query again.
+func (a *App) saveResultsAsTable() {
+	result := a.lastResult
+	if result == nil || result.JobID == "" {
+		return
+	}
+
+	projectEntry, datasetEntry := a.newDatasetEntries(result.ProjectID, "")
+	tableEntry := widget.NewEntry()
+	tableEntry.SetPlaceHolder("table")
+
+	writeSelect := widget.NewSelect(writeDispositionOptions, nil)
+	writeSelect.SetSelectedIndex(0)
+	expirationSelect := widget.NewSelect(expirationOptions, nil)
+	expirationSelect.SetSelectedIndex(0)
+
+	partitionOptions := []string{partitionNone, partitionIngestion}
+	for i, col := range result.Columns {
+		if i >= len(result.ColumnTypes) {
+			break
+		}
+		switch result.ColumnTypes[i] {
+		case "DATE", "TIMESTAMP", "DATETIME":
+			partitionOptions = append(partitionOptions, col)
+		}
+	}
+	partitionSelect := widget.NewSelect(partitionOptions, nil)
+	partitionSelect.SetSelectedIndex(0)
+	granularitySelect := widget.NewSelect([]string{"DAY", "HOUR", "MONTH", "YEAR"}, nil)
+	granularitySelect.SetSelectedIndex(0)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Project", projectEntry),
+		widget.NewFormItem("Dataset", datasetEntry),
+		widget.NewFormItem("Table", tableEntry),
+		widget.NewFormItem("If table exists", writeSelect),
+		widget.NewFormItem("Expires", expirationSelect),
+		widget.NewFormItem("Partition by", partitionSelect),
+		widget.NewFormItem("Granularity", granularitySelect),
+	}
+	d := dialog.NewForm("Save Results as Table", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		dst := bq.TableDestination{
+			ProjectID:        strings.TrimSpace(projectEntry.Text),
+			DatasetID:        strings.TrimSpace(datasetEntry.Text),
+			TableID:          strings.TrimSpace(tableEntry.Text),
+			WriteDisposition: writeDispositionLabels[writeSelect.Selected],
+			Expiration:       expirations[expirationSelect.Selected],
+			PartitionType:    granularitySelect.Selected,
+		}
+		switch partitionSelect.Selected {
+		case partitionNone:
+		case partitionIngestion:
+			dst.PartitionField = "_PARTITIONTIME"
+		default:
+			dst.PartitionField = partitionSelect.Selected
+		}
+		if err := validateDestination(dst); err != nil {
+			a.showError("Save as Table", err)
+			return
+		}
+		if !a.projectWritable(dst.ProjectID, auditSaveResults, dst.FullName()) {
+			return
+		}
+		run := func() { a.runSaveToTable(result, dst) }
+		if dst.WriteDisposition == bq.WriteTruncate {
+			a.confirmTyped("Overwrite Table", "Overwrite",
+				fmt.Sprintf("All rows of %s will be replaced with the query results.\n\nType %s.%s to overwrite it.",
+					dst.FullName(), dst.DatasetID, dst.TableID),
+				dst.DatasetID+"."+dst.TableID, run)
+			return
+		}
+		go run()
+	}, a.window)
+	d.Resize(fyne.NewSize(480, 0))
+	d.Show()
+}
+
+// newDatasetEntries returns project and dataset boxes for choosing a
+// destination, offering the known projects and the chosen project's
+// datasets.
+func (a *App) newDatasetEntries(project, dataset string) (*widget.SelectEntry, *widget.SelectEntry) {
+	projectEntry := widget.NewSelectEntry(a.explorer.AllKnownProjects())
+	projectEntry.SetText(project)
+	datasetEntry := widget.NewSelectEntry(nil)
+	datasetEntry.SetPlaceHolder("dataset")
+	datasetEntry.SetText(dataset)
+
+	loadDatasets := func(project string) {
+		go func() {
+			datasets, err := a.bqMgr.ListDatasets(a.ctx, project)
+			if err != nil {
+				return
+			}
+			sort.Strings(datasets)
+			fyne.Do(func() { datasetEntry.SetOptions(datasets) })
+		}()
+	}
+	projectEntry.OnChanged = func(p string) {
+		datasetEntry.SetOptions(nil)
+		if p = strings.TrimSpace(p); p != "" {
+			loadDatasets(p)
+		}
+	}
+	loadDatasets(project)
+	return projectEntry, datasetEntry
+}
+
+func validateDestination(dst bq.TableDestination) error {
+	for _, part := range []struct{ name, value string }{
+		{"project", dst.ProjectID},
+		{"dataset", dst.DatasetID},
+		{"table", dst.TableID},
+	} {
+		if part.value == "" {
+			return fmt.Errorf("%s is required", part.name)
+		}
+		if strings.ContainsAny(part.value, ".`") {
+			return fmt.Errorf("%s %q must not contain dots or backticks", part.name, part.value)
+		}
+	}
+	return nil
+}
+
+// runSaveToTable runs the save as a background job shown in the Jobs tab.
+func (a *App) runSaveToTable(result *bq.QueryResult, dst bq.TableDestination) {
+	ctx, cancel := context.WithCancel(a.ctx)
+	defer cancel()
+
+	id := a.jobs.Start("Save results to "+dst.FullName(), cancel)
+	fyne.Do(func() { a.bottomTabs.Select(a.jobsTab) })
+
+	labels := a.queryOptions(result.ProjectID, sourceSave).Labels
+	res, err := a.bqMgr.SaveJobToTable(ctx, result.ProjectID, result.JobID, result.Location, dst, labels, func(jobID string) {
+		a.jobs.SetProgress(id, "job "+jobID+" running")
+	})
+	if errors.Is(err, context.Canceled) {
+		err = errors.New("cancelled")
+	}
+	e := store.AuditEntry{Action: auditSaveResults, Project: dst.ProjectID, Target: dst.FullName(), Detail: "from job " + result.JobID}
+	if dst.WriteDisposition == bq.WriteTruncate {
+		e.Detail += " (overwrite)"
+	}
+	if err != nil {
+		e.Error = err.Error()
+		a.audit(e)
+		a.jobs.Finish(id, err, "")
+		return
+	}
+	a.audit(e)
+	a.jobs.Finish(id, nil, fmt.Sprintf("%d rows in table | job %s", res.TotalRows, res.JobID))
+	a.explorer.ReloadChildren(ui.DatasetNodeID(dst.ProjectID, dst.DatasetID))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/farbodahm/delephon/bq"
+	"github.com/farbodahm/delephon/ui"
+)
+
+// maxDiffRuns is how many recent results are kept for comparison.
+const maxDiffRuns = 20
+
+// recordRun keeps a successful result so it can be compared in the Diff
+// tab. Runs on the UI goroutine.
+func (a *App) recordRun(tabName string, result *bq.QueryResult) {
+	a.runCount++
+	run := ui.DiffSource{
+		Label:   fmt.Sprintf("%s at %s (run %d)", tabName, time.Now().Format("15:04:05"), a.runCount),
+		Columns: result.Columns,
+		Types:   result.ColumnTypes,
+		Rows:    result.Rows,
+	}
+	a.runs = append([]ui.DiffSource{run}, a.runs...)
+	if len(a.runs) > maxDiffRuns {
+		a.runs = a.runs[:maxDiffRuns]
+	}
+	a.diff.SetSources(a.runs)
+}
+
+// pinResult pins the result shown in the Results tab as the baseline of the
+// Diff tab and switches to it.
+func (a *App) pinResult() {
+	if len(a.runs) == 0 {
+		return
+	}
+	a.diff.SetBaseline(a.runs[0])
+	a.diff.SetSources(a.runs)
+	a.bottomTabs.Select(a.diffTab)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+
+	"github.com/farbodahm/delephon/bq"
+	"github.com/farbodahm/delephon/export"
+	"github.com/farbodahm/delephon/ui"
+)
+
+// exportProgressEvery is how many rows are written between status updates
+// while streaming a large export.
+const exportProgressEvery = 10000
+
+// exportResults asks for a destination file and writes the current result
+// set to it in the given format.
+func (a *App) exportResults(format export.Format) {
+	a.exportResultSet(a.lastResult, "results", a.results, format)
+}
+
+// exportPivot writes the rows shown in the Pivot tab.
+func (a *App) exportPivot(format export.Format) {
+	columns, types, rows, nulls := a.pivot.Results.Data()
+	result := &bq.QueryResult{Columns: columns, ColumnTypes: types, Rows: rows, Nulls: nulls, RowCount: int64(len(rows))}
+	a.exportResultSet(result, "pivot", a.pivot.Results, format)
+}
+
+// exportResultSet asks for a destination file and writes result to it,
+// reporting progress in the status bar of pane.
+func (a *App) exportResultSet(result *bq.QueryResult, name string, pane *ui.Results, format export.Format) {
+	if result == nil || len(result.Columns) == 0 {
+		return
+	}
+	d := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+		if err != nil {
+			a.showError("Export Error", err)
+			return
+		}
+		if w == nil {
+			return
+		}
+		path := w.URI().Path()
+		_ = a.store.SetSetting("last_export_dir", filepath.Dir(path))
+		go a.writeExport(w, format, result, pane)
+	}, a.window)
+	d.SetFilter(storage.NewExtensionFileFilter([]string{format.Extension()}))
+	d.SetFileName(name + format.Extension())
+	if dir, _ := a.store.GetSetting("last_export_dir"); dir != "" {
+		if lister, err := storage.ListerForURI(storage.NewFileURI(dir)); err == nil {
+			d.SetLocation(lister)
+		}
+	}
+	d.Show()
+}
+
+// writeExport writes result to w and closes it. When only part of the result
+// was loaded into the grid, the rows are streamed again from the query job
+// so the file holds the full result set.
+func (a *App) writeExport(w fyne.URIWriteCloser, format export.Format, result *bq.QueryResult, pane *ui.Results) {
+	defer w.Close()
+	name := w.URI().Name()
+	start := time.Now()
+
+	ew, err := export.NewWriter(w, format, result.Columns, result.ColumnTypes)
+	if err != nil {
+		a.showError("Export Error", err)
+		return
+	}
+
+	var written int64
+	writeRow := func(row []string, nulls []bool) error {
+		if err := ew.WriteRow(row, nulls); err != nil {
+			return err
+		}
+		written++
+		if written%exportProgressEvery == 0 {
+			pane.SetStatus(fmt.Sprintf("Exporting to %s... %d rows", name, written))
+		}
+		return nil
+	}
+
+	if result.Truncated() && result.JobID != "" {
+		pane.SetStatus(fmt.Sprintf("Exporting to %s...", name))
+		err = a.bqMgr.ReadJobRows(a.ctx, result.ProjectID, result.JobID, result.Location, writeRow)
+	} else {
+		for i, row := range result.Rows {
+			if err = writeRow(row, result.RowNulls(i)); err != nil {
+				break
+			}
+		}
+	}
+	if err == nil {
+		err = ew.Close()
+	}
+	if err != nil {
+		pane.SetStatus(fmt.Sprintf("Export failed after %d rows", written))
+		a.showError("Export Error", err)
+		return
+	}
+	pane.SetStatus(fmt.Sprintf("Exported %d rows to %s in %s",
+		written, name, time.Since(start).Round(time.Millisecond)))
+}
+
+// exportChart asks for a destination file and saves the chart shown in the
+// Chart tab as a PNG or SVG image.
+func (a *App) exportChart(format string) {
+	ext := "." + format
+	d := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+		if err != nil {
+			a.showError("Export Error", err)
+			return
+		}
+		if w == nil {
+			return
+		}
+		defer w.Close()
+		_ = a.store.SetSetting("last_export_dir", filepath.Dir(w.URI().Path()))
+
+		var data []byte
+		if format == "svg" {
+			data = a.chart.SVG()
+		} else if data, err = a.chart.PNG(); err != nil {
+			a.showError("Export Error", err)
+			return
+		}
+		if _, err := w.Write(data); err != nil {
+			a.showError("Export Error", err)
+		}
+	}, a.window)
+	d.SetFilter(storage.NewExtensionFileFilter([]string{ext}))
+	d.SetFileName("chart" + ext)
+	if dir, _ := a.store.GetSetting("last_export_dir"); dir != "" {
+		if lister, err := storage.ListerForURI(storage.NewFileURI(dir)); err == nil {
+			d.SetLocation(lister)
+		}
+	}
+	d.Show()
+}
@@ -0,0 +1,202 @@
+// Package export writes query result sets to files and the clipboard.
+//
+// Rows arrive as the display strings produced by the bq package, with a
+// mask of the cells that are SQL NULL, since a NULL and a string reading
+// "NULL" display alike. Column types are BigQuery type names (e.g.
+// "INTEGER", "ARRAY<STRING>") and decide how each cell is encoded in typed
+// formats such as JSON Lines and Parquet.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format identifies an export format.
+type Format string
+
+const (
+	CSV       Format = "csv"
+	TSV       Format = "tsv"
+	JSONLines Format = "jsonl"
+	Parquet   Format = "parquet"
+	Markdown  Format = "md"
+	HTML      Format = "html"
+)
+
+// Formats lists the supported file formats in menu order.
+var Formats = []Format{CSV, TSV, JSONLines, Parquet, Markdown, HTML}
+
+// Label returns a human-readable name for menus.
+func (f Format) Label() string {
+	switch f {
+	case CSV:
+		return "CSV"
+	case TSV:
+		return "TSV"
+	case JSONLines:
+		return "JSON Lines"
+	case Parquet:
+		return "Parquet"
+	case Markdown:
+		return "Markdown table"
+	case HTML:
+		return "HTML table"
+	}
+	return string(f)
+}
+
+// Extension returns the file extension, including the dot.
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// Writer streams the rows of one result set in a given format.
+type Writer interface {
+	// WriteRow writes one row of display values, in column order. nulls
+	// marks the cells that are SQL NULL; it is nil when none are.
+	WriteRow(row []string, nulls []bool) error
+	// Close flushes buffered rows and writes any trailer. It does not close
+	// the underlying io.Writer.
+	Close() error
+}
+
+// NewWriter returns a Writer for format f that writes to w. The header (if
+// the format has one) is written immediately.
+func NewWriter(w io.Writer, f Format, columns, types []string) (Writer, error) {
+	if len(types) < len(columns) {
+		padded := make([]string, len(columns))
+		copy(padded, types)
+		types = padded
+	}
+	switch f {
+	case CSV:
+		return newCSVWriter(w, columns)
+	case TSV:
+		return newTSVWriter(w, columns)
+	case JSONLines:
+		return newJSONLinesWriter(w, columns, types), nil
+	case Parquet:
+		return newParquetWriter(w, columns, types)
+	case Markdown:
+		return newMarkdownWriter(w, columns)
+	case HTML:
+		return newHTMLWriter(w, columns)
+	}
+	return nil, fmt.Errorf("unsupported export format %q", f)
+}
+
+// WriteAll writes an in-memory result set with format f. nulls holds the
+// NULL mask of each row; it may be shorter than rows, or nil.
+func WriteAll(w io.Writer, f Format, columns, types []string, rows [][]string, nulls [][]bool) error {
+	ew, err := NewWriter(w, f, columns, types)
+	if err != nil {
+		return err
+	}
+	for i, row := range rows {
+		var rowNulls []bool
+		if i < len(nulls) {
+			rowNulls = nulls[i]
+		}
+		if err := ew.WriteRow(row, rowNulls); err != nil {
+			return err
+		}
+	}
+	return ew.Close()
+}
+
+// TSVString renders a result set as tab-separated text for pasting into a spreadsheet.
+func TSVString(columns []string, rows [][]string, nulls [][]bool) string {
+	var b strings.Builder
+	_ = WriteAll(&b, TSV, columns, nil, rows, nulls)
+	return b.String()
+}
+
+// DisplayNulls returns the NULL mask of rows that only hold display values,
+// treating every "NULL" as SQL NULL. It is for rows with no other record of
+// which cells are NULL, such as computed pivots.
+func DisplayNulls(rows [][]string) [][]bool {
+	nulls := make([][]bool, len(rows))
+	for i, row := range rows {
+		for j, v := range row {
+			if v == "NULL" {
+				if nulls[i] == nil {
+					nulls[i] = make([]bool, len(row))
+				}
+				nulls[i][j] = true
+			}
+		}
+	}
+	return nulls
+}
+
+func isNull(nulls []bool, i int) bool {
+	return i < len(nulls) && nulls[i]
+}
+
+// csvWriter writes RFC 4180 CSV. NULL becomes an empty field.
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w)}
+	if err := cw.w.Write(columns); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (c *csvWriter) WriteRow(row []string, nulls []bool) error {
+	out := make([]string, len(row))
+	for i, v := range row {
+		if !isNull(nulls, i) {
+			out[i] = v
+		}
+	}
+	return c.w.Write(out)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// tsvWriter writes unquoted tab-separated values. Tabs and newlines inside
+// values are replaced with spaces so every row stays on one line.
+type tsvWriter struct {
+	w io.Writer
+}
+
+var tsvEscaper = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+func newTSVWriter(w io.Writer, columns []string) (*tsvWriter, error) {
+	t := &tsvWriter{w: w}
+	if err := t.writeLine(columns); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *tsvWriter) writeLine(values []string) error {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = tsvEscaper.Replace(v)
+	}
+	_, err := io.WriteString(t.w, strings.Join(out, "\t")+"\n")
+	return err
+}
+
+func (t *tsvWriter) WriteRow(row []string, nulls []bool) error {
+	out := make([]string, len(row))
+	for i, v := range row {
+		if !isNull(nulls, i) {
+			out[i] = v
+		}
+	}
+	return t.writeLine(out)
+}
+
+func (t *tsvWriter) Close() error { return nil }
@@ -0,0 +1,161 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v15/parquet/file"
+)
+
+var (
+	testColumns = []string{"id", "name", "score", "ok", "tags"}
+	testTypes   = []string{"INTEGER", "STRING", "FLOAT", "BOOLEAN", "ARRAY<STRING>"}
+	testRows    = [][]string{
+		{"1", "alice", "1.5", "true", `["a","b"]`},
+		{"2", "bob\tsmith", "NULL", "false", "[]"},
+		{"3", "NULL", "2", "NULL", "NULL"},
+	}
+	testNulls = [][]bool{
+		nil,
+		{false, false, true, false, false},
+		{false, true, false, true, true},
+	}
+)
+
+func writeString(t *testing.T, f Format) string {
+	t.Helper()
+	var b bytes.Buffer
+	if err := WriteAll(&b, f, testColumns, testTypes, testRows, testNulls); err != nil {
+		t.Fatalf("WriteAll(%s): %v", f, err)
+	}
+	return b.String()
+}
+
+func TestCSV(t *testing.T) {
+	got := writeString(t, CSV)
+	want := "id,name,score,ok,tags\n" +
+		"1,alice,1.5,true,\"[\"\"a\"\",\"\"b\"\"]\"\n" +
+		"2,bob\tsmith,,false,[]\n" +
+		"3,,2,,\n"
+	if got != want {
+		t.Errorf("CSV:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestTSVString(t *testing.T) {
+	got := TSVString(testColumns, testRows, testNulls)
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d: %q", len(lines), got)
+	}
+	if lines[2] != "2\tbob smith\t\tfalse\t[]" {
+		t.Errorf("row 2 = %q, tab inside value should become a space", lines[2])
+	}
+}
+
+func TestJSONLines(t *testing.T) {
+	got := writeString(t, JSONLines)
+	want := `{"id":1,"name":"alice","score":1.5,"ok":true,"tags":["a","b"]}` + "\n" +
+		`{"id":2,"name":"bob\tsmith","score":null,"ok":false,"tags":[]}` + "\n" +
+		`{"id":3,"name":null,"score":2,"ok":null,"tags":null}` + "\n"
+	if got != want {
+		t.Errorf("JSON Lines:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestNullStringIsNotNull(t *testing.T) {
+	rows := [][]string{{"NULL", "NULL"}}
+	nulls := [][]bool{{false, true}}
+	for f, want := range map[Format]string{
+		CSV:       "a,b\nNULL,\n",
+		TSV:       "a\tb\nNULL\t\n",
+		JSONLines: `{"a":"NULL","b":null}` + "\n",
+	} {
+		var b bytes.Buffer
+		if err := WriteAll(&b, f, []string{"a", "b"}, []string{"STRING", "STRING"}, rows, nulls); err != nil {
+			t.Fatalf("WriteAll(%s): %v", f, err)
+		}
+		if b.String() != want {
+			t.Errorf("%s: got %q, want %q", f, b.String(), want)
+		}
+	}
+}
+
+func TestDisplayNulls(t *testing.T) {
+	got := DisplayNulls([][]string{{"1", "x"}, {"NULL", "y"}})
+	if got[0] != nil || len(got[1]) != 2 || !got[1][0] || got[1][1] {
+		t.Errorf("DisplayNulls = %v", got)
+	}
+}
+
+func TestJSONValueFallback(t *testing.T) {
+	if got := string(jsonValue("abc", "INTEGER")); got != `"abc"` {
+		t.Errorf("unparseable INTEGER = %s, want string", got)
+	}
+	if got := string(jsonValue("{not json", "JSON")); got != `"{not json"` {
+		t.Errorf("invalid JSON = %s, want string", got)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var b bytes.Buffer
+	err := WriteAll(&b, Markdown, []string{"a", "b"}, nil, [][]string{{"x|y", "line1\nline2"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "| a | b |\n| --- | --- |\n| x\\|y | line1<br>line2 |\n"
+	if b.String() != want {
+		t.Errorf("Markdown:\n got %q\nwant %q", b.String(), want)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	var b bytes.Buffer
+	err := WriteAll(&b, HTML, []string{"a"}, nil, [][]string{{"<b>&"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "<td>&lt;b&gt;&amp;</td>") {
+		t.Errorf("HTML cell not escaped: %s", b.String())
+	}
+}
+
+func TestParquet(t *testing.T) {
+	columns := []string{"id", "score", "ok", "day", "ts", "name"}
+	types := []string{"INTEGER", "FLOAT", "BOOLEAN", "DATE", "TIMESTAMP", "STRING"}
+	rows := [][]string{
+		{"1", "1.5", "true", "2024-01-02", "2024-01-02 03:04:05.123456 +0000 UTC", "alice"},
+		{"NULL", "NULL", "NULL", "NULL", "NULL", "NULL"},
+	}
+	var b bytes.Buffer
+	if err := WriteAll(&b, Parquet, columns, types, rows, [][]bool{nil, {true, true, true, true, true, true}}); err != nil {
+		t.Fatalf("WriteAll: %v", err)
+	}
+
+	r, err := file.NewParquetReader(bytes.NewReader(b.Bytes()))
+	if err != nil {
+		t.Fatalf("read back: %v", err)
+	}
+	defer r.Close()
+	if r.NumRows() != 2 {
+		t.Errorf("NumRows = %d, want 2", r.NumRows())
+	}
+	if n := r.MetaData().Schema.NumColumns(); n != len(columns) {
+		t.Errorf("NumColumns = %d, want %d", n, len(columns))
+	}
+}
+
+func TestParquetBadValue(t *testing.T) {
+	var b bytes.Buffer
+	err := WriteAll(&b, Parquet, []string{"n"}, []string{"INTEGER"}, [][]string{{"x"}}, nil)
+	if err == nil {
+		t.Fatal("expected error for non-integer value in INTEGER column")
+	}
+}
+
+func TestUnsupportedFormat(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, Format("xlsx"), nil, nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,79 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// jsonLinesWriter writes one JSON object per row. Values are encoded
+// according to their column type: numbers and booleans unquoted, nested
+// values (STRUCT, ARRAY, JSON) as embedded JSON and NULL as null.
+type jsonLinesWriter struct {
+	w       *bufio.Writer
+	columns []string
+	types   []string
+}
+
+func newJSONLinesWriter(w io.Writer, columns, types []string) *jsonLinesWriter {
+	return &jsonLinesWriter{w: bufio.NewWriter(w), columns: columns, types: types}
+}
+
+func (j *jsonLinesWriter) WriteRow(row []string, nulls []bool) error {
+	j.w.WriteByte('{')
+	for i, col := range j.columns {
+		if i > 0 {
+			j.w.WriteByte(',')
+		}
+		key, _ := json.Marshal(col)
+		j.w.Write(key)
+		j.w.WriteByte(':')
+		if i >= len(row) || isNull(nulls, i) {
+			j.w.WriteString("null")
+			continue
+		}
+		j.w.Write(jsonValue(row[i], j.types[i]))
+	}
+	j.w.WriteString("}\n")
+	return nil
+}
+
+func (j *jsonLinesWriter) Close() error {
+	return j.w.Flush()
+}
+
+// jsonValue encodes a non-NULL display value as JSON according to its
+// BigQuery type. Values that do not parse as their declared type fall back
+// to strings.
+func jsonValue(v, typ string) []byte {
+	switch {
+	case IsNested(typ):
+		if json.Valid([]byte(v)) {
+			return []byte(v)
+		}
+	case typ == "INTEGER" || typ == "INT64":
+		if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return []byte(v)
+		}
+	case typ == "FLOAT" || typ == "FLOAT64":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			if b, err := json.Marshal(f); err == nil {
+				return b
+			}
+		}
+	case typ == "BOOLEAN" || typ == "BOOL":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return []byte(strconv.FormatBool(b))
+		}
+	}
+	b, _ := json.Marshal(v)
+	return b
+}
+
+// IsNested reports whether values of a column type (STRUCT, ARRAY or JSON)
+// are rendered as JSON by the bq package.
+func IsNested(typ string) bool {
+	return typ == "RECORD" || typ == "STRUCT" || typ == "JSON" || strings.HasPrefix(typ, "ARRAY<")
+}
@@ -0,0 +1,145 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/apache/arrow/go/v15/arrow"
+	"github.com/apache/arrow/go/v15/arrow/array"
+	"github.com/apache/arrow/go/v15/arrow/memory"
+	"github.com/apache/arrow/go/v15/parquet"
+	"github.com/apache/arrow/go/v15/parquet/compress"
+	"github.com/apache/arrow/go/v15/parquet/pqarrow"
+)
+
+// parquetBatchRows is the number of rows buffered per Arrow record (and
+// Parquet row group) before it is written out.
+const parquetBatchRows = 10000
+
+// timestampLayout matches how the bq package renders TIMESTAMP values (time.Time.String).
+const timestampLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// parquetWriter buffers rows into Arrow records and writes them as Parquet
+// row groups. Scalar columns keep their type; nested values are stored as
+// JSON strings.
+type parquetWriter struct {
+	fw      *pqarrow.FileWriter
+	builder *array.RecordBuilder
+	types   []string
+	pending int
+}
+
+func newParquetWriter(w io.Writer, columns, types []string) (*parquetWriter, error) {
+	fields := make([]arrow.Field, len(columns))
+	for i, col := range columns {
+		fields[i] = arrow.Field{Name: col, Type: arrowType(types[i]), Nullable: true}
+	}
+	schema := arrow.NewSchema(fields, nil)
+
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+	// Hide any Close method so the caller keeps ownership of w.
+	fw, err := pqarrow.NewFileWriter(schema, struct{ io.Writer }{w}, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, fmt.Errorf("parquet writer: %w", err)
+	}
+	return &parquetWriter{
+		fw:      fw,
+		builder: array.NewRecordBuilder(memory.DefaultAllocator, schema),
+		types:   types,
+	}, nil
+}
+
+// arrowType maps a BigQuery column type to the Arrow type used in Parquet output.
+func arrowType(typ string) arrow.DataType {
+	switch typ {
+	case "INTEGER", "INT64":
+		return arrow.PrimitiveTypes.Int64
+	case "FLOAT", "FLOAT64":
+		return arrow.PrimitiveTypes.Float64
+	case "BOOLEAN", "BOOL":
+		return arrow.FixedWidthTypes.Boolean
+	case "DATE":
+		return arrow.FixedWidthTypes.Date32
+	case "TIMESTAMP":
+		return arrow.FixedWidthTypes.Timestamp_us
+	}
+	return arrow.BinaryTypes.String
+}
+
+func (p *parquetWriter) WriteRow(row []string, nulls []bool) error {
+	for i := range p.types {
+		b := p.builder.Field(i)
+		if i >= len(row) || isNull(nulls, i) {
+			b.AppendNull()
+			continue
+		}
+		if err := p.appendValue(b, row[i]); err != nil {
+			return fmt.Errorf("column %d: %w", i+1, err)
+		}
+	}
+	p.pending++
+	if p.pending >= parquetBatchRows {
+		return p.flush()
+	}
+	return nil
+}
+
+func (p *parquetWriter) appendValue(b array.Builder, v string) error {
+	switch fb := b.(type) {
+	case *array.Int64Builder:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("parse integer %q: %w", v, err)
+		}
+		fb.Append(n)
+	case *array.Float64Builder:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("parse float %q: %w", v, err)
+		}
+		fb.Append(f)
+	case *array.BooleanBuilder:
+		bv, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("parse boolean %q: %w", v, err)
+		}
+		fb.Append(bv)
+	case *array.Date32Builder:
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return fmt.Errorf("parse date %q: %w", v, err)
+		}
+		fb.Append(arrow.Date32FromTime(t))
+	case *array.TimestampBuilder:
+		t, err := time.Parse(timestampLayout, v)
+		if err != nil {
+			return fmt.Errorf("parse timestamp %q: %w", v, err)
+		}
+		fb.Append(arrow.Timestamp(t.UnixMicro()))
+	case *array.StringBuilder:
+		fb.Append(v)
+	default:
+		return fmt.Errorf("unsupported builder %T", b)
+	}
+	return nil
+}
+
+func (p *parquetWriter) flush() error {
+	if p.pending == 0 {
+		return nil
+	}
+	rec := p.builder.NewRecord()
+	defer rec.Release()
+	p.pending = 0
+	return p.fw.Write(rec)
+}
+
+func (p *parquetWriter) Close() error {
+	defer p.builder.Release()
+	if err := p.flush(); err != nil {
+		return err
+	}
+	return p.fw.Close()
+}
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"bufio"
+	"html"
+	"io"
+	"strings"
+)
+
+// markdownWriter writes a GitHub-flavored Markdown table.
+type markdownWriter struct {
+	w *bufio.Writer
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+func newMarkdownWriter(w io.Writer, columns []string) (*markdownWriter, error) {
+	m := &markdownWriter{w: bufio.NewWriter(w)}
+	m.writeLine(columns)
+	sep := make([]string, len(columns))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	m.writeLine(sep)
+	return m, nil
+}
+
+func (m *markdownWriter) writeLine(values []string) {
+	m.w.WriteString("|")
+	for _, v := range values {
+		m.w.WriteString(" " + markdownEscaper.Replace(v) + " |")
+	}
+	m.w.WriteString("\n")
+}
+
+func (m *markdownWriter) WriteRow(row []string, nulls []bool) error {
+	m.writeLine(row)
+	return nil
+}
+
+func (m *markdownWriter) Close() error {
+	return m.w.Flush()
+}
+
+// htmlWriter writes a standalone HTML table.
+type htmlWriter struct {
+	w *bufio.Writer
+}
+
+func newHTMLWriter(w io.Writer, columns []string) (*htmlWriter, error) {
+	h := &htmlWriter{w: bufio.NewWriter(w)}
+	h.w.WriteString("<table>\n<thead>\n<tr>")
+	for _, c := range columns {
+		h.w.WriteString("<th>" + html.EscapeString(c) + "</th>")
+	}
+	h.w.WriteString("</tr>\n</thead>\n<tbody>\n")
+	return h, nil
+}
+
+func (h *htmlWriter) WriteRow(row []string, nulls []bool) error {
+	h.w.WriteString("<tr>")
+	for _, v := range row {
+		h.w.WriteString("<td>" + html.EscapeString(v) + "</td>")
+	}
+	h.w.WriteString("</tr>\n")
+	return nil
+}
+
+func (h *htmlWriter) Close() error {
+	h.w.WriteString("</tbody>\n</table>\n")
+	return h.w.Flush()
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/farbodahm/delephon/ui"
+)
+
+// sqlFileFilter limits the file dialogs to SQL files.
+var sqlFileFilter = storage.NewExtensionFileFilter([]string{".sql"})
+
+// wireFileCallbacks connects the editor's file actions to the app's dialogs.
+func (a *App) wireFileCallbacks() {
+	a.editor.OnOpenFile = a.openSQLFile
+	a.editor.OnSaveFile = a.saveSQLFile
+	a.editor.ConfirmClose = a.confirmCloseTab
+	a.editor.OnExternalChange = func(name string, reload func()) {
+		fyne.Do(func() {
+			dialog.ShowConfirm("File Changed",
+				fmt.Sprintf("%s was modified outside Delephon.\nReload it and discard your unsaved edits?", name),
+				func(ok bool) {
+					if ok {
+						reload()
+					}
+				},
+				a.window,
+			)
+		})
+	}
+
+	// Window-level shortcuts for when the SQL editor does not have focus.
+	a.window.Canvas().AddShortcut(
+		&desktop.CustomShortcut{KeyName: fyne.KeyO, Modifier: fyne.KeyModifierShortcutDefault},
+		func(fyne.Shortcut) { a.openSQLFile() },
+	)
+	a.window.Canvas().AddShortcut(
+		&desktop.CustomShortcut{KeyName: fyne.KeyS, Modifier: fyne.KeyModifierShortcutDefault},
+		func(fyne.Shortcut) { a.saveSQLFile(a.editor.CurrentTab(), false, nil) },
+	)
+
+	if app := fyne.CurrentApp(); app != nil {
+		app.Lifecycle().SetOnEnteredForeground(a.editor.CheckExternalChanges)
+	}
+}
+
+// openSQLFile shows a file picker and loads the chosen file into a tab.
+func (a *App) openSQLFile() {
+	d := dialog.NewFileOpen(func(r fyne.URIReadCloser, err error) {
+		if err != nil {
+			a.showError("Open Error", err)
+			return
+		}
+		if r == nil {
+			return
+		}
+		path := r.URI().Path()
+		r.Close()
+		a.rememberSQLDir(path)
+		if err := a.editor.OpenFile(path); err != nil {
+			a.showError("Open Error", err)
+		}
+	}, a.window)
+	d.SetFilter(sqlFileFilter)
+	a.setSQLDialogLocation(d)
+	d.Show()
+}
+
+// saveSQLFile writes tab to its file, asking for a path when the tab is not
+// file-backed yet or saveAs is set.
+func (a *App) saveSQLFile(tab *container.TabItem, saveAs bool, done func(saved bool)) {
+	finish := func(saved bool) {
+		if done != nil {
+			done(saved)
+		}
+	}
+
+	path := a.editor.FilePath(tab)
+	if path == "" || saveAs {
+		a.saveSQLFileAs(tab, finish)
+		return
+	}
+
+	write := func() {
+		if err := a.editor.SaveFile(tab, path); err != nil {
+			a.showError("Save Error", err)
+			finish(false)
+			return
+		}
+		finish(true)
+	}
+	if !a.editor.FileChangedOnDisk(tab) {
+		write()
+		return
+	}
+	dialog.ShowConfirm("File Changed",
+		fmt.Sprintf("%s was modified outside Delephon since it was opened.\nOverwrite it?", filepath.Base(path)),
+		func(ok bool) {
+			if ok {
+				write()
+			} else {
+				finish(false)
+			}
+		},
+		a.window,
+	)
+}
+
+func (a *App) saveSQLFileAs(tab *container.TabItem, finish func(saved bool)) {
+	d := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+		if err != nil {
+			a.showError("Save Error", err)
+			finish(false)
+			return
+		}
+		if w == nil {
+			finish(false)
+			return
+		}
+		path := w.URI().Path()
+		w.Close()
+		if filepath.Ext(path) == "" {
+			path += ".sql"
+		}
+		a.rememberSQLDir(path)
+		if err := a.editor.SaveFile(tab, path); err != nil {
+			a.showError("Save Error", err)
+			finish(false)
+			return
+		}
+		finish(true)
+	}, a.window)
+	d.SetFilter(sqlFileFilter)
+	name := a.editor.TabName(tab)
+	if !strings.HasSuffix(name, ".sql") {
+		name += ".sql"
+	}
+	d.SetFileName(name)
+	a.setSQLDialogLocation(d)
+	d.Show()
+}
+
+// confirmCloseTab asks whether to save a tab with unsaved changes before closing it.
+func (a *App) confirmCloseTab(name string, answer func(ui.CloseChoice)) {
+	var d *dialog.CustomDialog
+	choose := func(choice ui.CloseChoice) func() {
+		return func() {
+			d.Hide()
+			answer(choice)
+		}
+	}
+	saveBtn := widget.NewButton("Save", choose(ui.CloseSave))
+	saveBtn.Importance = widget.HighImportance
+	buttons := container.NewHBox(
+		layout.NewSpacer(),
+		widget.NewButton("Cancel", choose(ui.CloseCancel)),
+		widget.NewButton("Don't Save", choose(ui.CloseDiscard)),
+		saveBtn,
+	)
+	msg := widget.NewLabel(fmt.Sprintf("%s has unsaved changes. Save them before closing?", name))
+	d = dialog.NewCustomWithoutButtons("Unsaved Changes", container.NewVBox(msg, buttons), a.window)
+	d.Show()
+}
+
+func (a *App) rememberSQLDir(path string) {
+	_ = a.store.SetSetting("last_sql_dir", filepath.Dir(path))
+}
+
+// setSQLDialogLocation starts file dialogs in the directory last used for SQL files.
+func (a *App) setSQLDialogLocation(d *dialog.FileDialog) {
+	dir, _ := a.store.GetSetting("last_sql_dir")
+	if dir == "" {
+		return
+	}
+	if lister, err := storage.ListerForURI(storage.NewFileURI(dir)); err == nil {
+		d.SetLocation(lister)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/farbodahm/delephon/bq"
+	"github.com/farbodahm/delephon/ui"
+)
+
+// maxListedJobs caps how many jobs the job browser lists.
+const maxListedJobs = 500
+
+// jobsRegionKey prefixes the per-project INFORMATION_SCHEMA region last
+// used in the job browser.
+const jobsRegionKey = "jobs_region/"
+
+// jobsRegionPattern is what the job browser accepts as a region. The region
+// is spliced into the INFORMATION_SCHEMA query, so nothing else may pass.
+var jobsRegionPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// listProjectJobs lists a project's recent jobs in the job browser.
+func (a *App) listProjectJobs(project, region string, allUsers bool, days int) {
+	if project == "" {
+		project = a.editor.GetCurrentProject()
+		a.jobs.Browser.SetProject(project)
+	}
+	if project == "" {
+		a.jobs.Browser.SetStatus("Choose a project to list its jobs.")
+		return
+	}
+	if region == "" {
+		region, _ = a.store.GetSetting(jobsRegionKey + project)
+		if region == "" {
+			region = "us"
+		}
+		a.jobs.Browser.SetRegion(region)
+	}
+	region = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(region)), "region-")
+	if !jobsRegionPattern.MatchString(region) {
+		a.jobs.Browser.SetStatus(fmt.Sprintf("Error: invalid region %q.", region))
+		return
+	}
+	_ = a.store.SetSetting(jobsRegionKey+project, region)
+
+	scope := "your"
+	if allUsers {
+		scope = "all users'"
+	}
+	a.jobs.Browser.SetStatus(fmt.Sprintf("Listing %s jobs in %s (region-%s)...", scope, project, region))
+	infos, cost, err := a.bqMgr.ListJobs(a.ctx, project, region, allUsers, days, maxListedJobs, a.queryOptions(project, sourceJobs))
+	a.recordJobCost(sourceJobs, project, cost)
+	if err != nil {
+		hint := ""
+		if allUsers {
+			hint = " Listing all users' jobs needs the bigquery.jobs.listAll permission."
+		}
+		a.jobs.Browser.SetStatus(fmt.Sprintf("Error: %v.%s", err, hint))
+		return
+	}
+	jobs := make([]ui.ProjectJob, len(infos))
+	for i, j := range infos {
+		jobs[i] = projectJob(j)
+	}
+	status := fmt.Sprintf("%d jobs in %s from the last %d days", len(jobs), project, days)
+	if len(jobs) == maxListedJobs {
+		status += fmt.Sprintf(" (newest %d shown)", maxListedJobs)
+	}
+	a.jobs.Browser.SetJobs(jobs, status)
+}
+
+func projectJob(j bq.JobInfo) ui.ProjectJob {
+	pj := ui.ProjectJob{
+		ProjectID:     j.ProjectID,
+		JobID:         j.JobID,
+		Location:      j.Location,
+		User:          j.User,
+		JobType:       j.JobType,
+		StatementType: j.StatementType,
+		State:         j.State,
+		Query:         j.Query,
+		Created:       j.Created,
+		BytesBilled:   j.BytesBilled,
+		SlotMillis:    j.SlotMillis,
+		CacheHit:      j.CacheHit,
+		DestProject:   j.DestProject,
+		DestDataset:   j.DestDataset,
+		DestTable:     j.DestTable,
+	}
+	if !j.Started.IsZero() && j.Ended.After(j.Started) {
+		pj.Duration = j.Ended.Sub(j.Started)
+	}
+	if j.Failed() {
+		pj.Error = strings.TrimSpace(j.ErrorReason + ": " + j.ErrorMessage)
+	}
+	return pj
+}
+
+// openJobSQL opens a listed job's query in a new editor tab.
+func (a *App) openJobSQL(job ui.ProjectJob) {
+	a.editor.NewTabWithSQL("Job "+shortJobID(job.JobID), job.ProjectID, job.Query)
+}
+
+// loadJobResults opens a listed job's results.
+func (a *App) loadJobResults(job ui.ProjectJob) {
+	a.jobs.Browser.SetStatus(fmt.Sprintf("Reading results of %s...", job.JobID))
+	ref := bq.JobRef{ProjectID: job.ProjectID, Location: job.Location, JobID: job.JobID}
+	if err := a.openJob(ref); err != nil {
+		a.jobs.Browser.SetStatus(fmt.Sprintf("Error: %v", err))
+		return
+	}
+	a.jobs.Browser.SetStatus(fmt.Sprintf("Opened results of %s", job.JobID))
+}
+
+// showOpenJobDialog asks for a job ID, e.g. from a teammate or a log line,
+// and opens the job's results.
+func (a *App) showOpenJobDialog() {
+	project := a.editor.GetCurrentProject()
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("project:US.bquxjob_1a2b3c4d_18f0e1a2b3c")
+	entry.Validator = func(s string) error {
+		_, err := bq.ParseJobRef(s, project)
+		return err
+	}
+	hint := widget.NewLabel("Opens a finished query job's SQL, statistics and cached results without re-running it. " +
+		"A bare job ID belongs to the current project.")
+	hint.Wrapping = fyne.TextWrapWord
+	d := dialog.NewForm("Open Job", "Open", "Cancel",
+		[]*widget.FormItem{
+			widget.NewFormItem("Job", entry),
+			widget.NewFormItem("", hint),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			ref, err := bq.ParseJobRef(entry.Text, project)
+			if err != nil {
+				return
+			}
+			go func() {
+				a.results.SetStatus(fmt.Sprintf("Opening job %s...", ref))
+				if err := a.openJob(ref); err != nil {
+					a.results.SetStatus(fmt.Sprintf("Error: %v", err))
+					a.showError("Open Job", err)
+				}
+			}()
+		}, a.window)
+	d.Resize(fyne.NewSize(560, 0))
+	d.Show()
+	a.window.Canvas().Focus(entry)
+}
+
+// openJob shows a finished query job's SQL in a new editor tab and its
+// statistics and results below, read from the job's destination table.
+func (a *App) openJob(ref bq.JobRef) error {
+	result, err := a.bqMgr.JobResult(a.ctx, ref)
+	if err != nil {
+		return err
+	}
+	name := "Job " + shortJobID(result.JobID)
+	fyne.Do(func() { a.editor.NewTabWithSQL(name, result.ProjectID, result.SQL) })
+	a.showResult(result, name)
+
+	rows := fmt.Sprintf("%d rows", result.RowCount)
+	if result.Truncated() {
+		rows = fmt.Sprintf("%d of %d rows", result.RowCount, result.TotalRows)
+	}
+	ran := ""
+	if result.Stats != nil {
+		ran = " | ran " + result.Stats.Started.Local().Format("2006-01-02 15:04")
+	}
+	a.results.SetStatus(fmt.Sprintf("%s | %s | %.2f MB processed | job %s%s (not re-run)",
+		rows,
+		result.Duration.Round(time.Millisecond),
+		float64(result.BytesProcessed)/(1024*1024),
+		ref, ran,
+	))
+	fyne.Do(func() { a.bottomTabs.SelectIndex(0) })
+	return nil
+}
+
+// shortJobID shortens long generated job IDs for tab and run names.
+func shortJobID(id string) string {
+	if len(id) > 16 {
+		return id[:8] + "…" + id[len(id)-6:]
+	}
+	return id
+}
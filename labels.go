@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os/user"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/farbodahm/delephon/bq"
+	"github.com/farbodahm/delephon/ui"
+)
+
+// Settings keys for job labels added to every query, and whether to also
+// name them in a SQL comment header.
+const (
+	jobLabelsKey        = "job_labels"
+	jobCommentHeaderKey = "job_comment_header"
+)
+
+// Sources of queries, recorded in the "source" job label.
+const (
+	sourceEditor   = "editor"
+	sourceAI       = "ai"
+	sourceSave     = "save"
+	sourceProfile  = "profile"
+	sourceJobs     = "jobs"
+	sourceUpload   = "upload"
+	sourceExplorer = "explorer"
+)
+
+// jobLabels returns the labels for a query from source: tool, source, the
+// OS user and, for editor queries, the name of the tab they ran from, then
+// the configured labels, then that tab's own labels, each overriding the
+// ones before.
+func (a *App) jobLabels(source string, tab ui.QueryTab) map[string]string {
+	if source != sourceEditor {
+		tab = ui.QueryTab{}
+	}
+	labels := map[string]string{"tool": "delephon", "source": source}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		labels["user"] = u.Username
+	}
+	if tab.Name != "" {
+		labels["tab"] = tab.Name
+	}
+	if v, _ := a.store.GetSetting(jobLabelsKey); v != "" {
+		if configured, err := bq.ParseLabels(v); err == nil {
+			for k, v := range configured {
+				labels[k] = v
+			}
+		}
+	}
+	for k, v := range tab.Labels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// promptTabLabels edits the job labels of the selected editor tab.
+func (a *App) promptTabLabels(current map[string]string, apply func(labels map[string]string)) {
+	entry := widget.NewEntry()
+	entry.SetText(bq.FormatLabels(current))
+	entry.SetPlaceHolder("e.g. team=finance, report=weekly")
+	entry.Validator = func(s string) error {
+		_, err := bq.ParseLabels(s)
+		return err
+	}
+	hint := widget.NewLabel("Added to every query run from this tab, after tool, source, user and tab. " +
+		"Keys and values are lowercased; characters other than letters, digits, _ and - become _.")
+	hint.Wrapping = fyne.TextWrapWord
+	d := dialog.NewForm("Job Labels", "Save", "Cancel",
+		[]*widget.FormItem{
+			widget.NewFormItem("Labels", entry),
+			widget.NewFormItem("", hint),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			labels, err := bq.ParseLabels(entry.Text)
+			if err != nil {
+				a.showError("Job Labels", err)
+				return
+			}
+			apply(labels)
+		}, a.window)
+	d.Resize(fyne.NewSize(480, 0))
+	d.Show()
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/farbodahm/delephon/store"
+	"github.com/farbodahm/delephon/ui"
+)
+
+// newSettingsApp returns an App whose store lives in a temporary config
+// directory.
+func newSettingsApp(t *testing.T) *App {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	s, err := store.New()
+	if err != nil {
+		t.Fatalf("store.New: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return &App{store: s}
+}
+
+func TestJobLabels_TabOnlyForEditor(t *testing.T) {
+	a := newSettingsApp(t)
+	if err := a.store.SetSetting(jobLabelsKey, "team=data, env=dev"); err != nil {
+		t.Fatal(err)
+	}
+	tab := ui.QueryTab{Name: "revenue", Labels: map[string]string{"env": "prod"}}
+
+	labels := a.jobLabels(sourceEditor, tab)
+	if labels["tab"] != "revenue" || labels["env"] != "prod" || labels["team"] != "data" {
+		t.Errorf("editor labels = %v", labels)
+	}
+
+	labels = a.jobLabels(sourceProfile, tab)
+	if _, ok := labels["tab"]; ok || labels["env"] != "dev" || labels["source"] != sourceProfile {
+		t.Errorf("profile labels = %v, want no tab labels", labels)
+	}
+}
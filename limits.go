@@ -0,0 +1,282 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/farbodahm/delephon/bq"
+	"github.com/farbodahm/delephon/ui"
+)
+
+// Settings keys for the maximum bytes billed per query. A project's own
+// setting, even "0" for no limit, takes precedence over the global one.
+const (
+	maxBytesBilledKey        = "max_bytes_billed"
+	maxBytesBilledProjectKey = "max_bytes_billed/"
+)
+
+// byteUnits maps size suffixes to their multipliers. Decimal units follow
+// SI; binary units (KiB, MiB, ...) are powers of 1024, as BigQuery bills.
+var byteUnits = map[string]float64{
+	"":    1,
+	"B":   1,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"PB":  1e15,
+	"KIB": 1 << 10,
+	"MIB": 1 << 20,
+	"GIB": 1 << 30,
+	"TIB": 1 << 40,
+	"PIB": 1 << 50,
+}
+
+// parseByteSize parses sizes such as "500 MB", "1.5TiB" or "1048576". An
+// empty string is 0.
+func parseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	i := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+	num, unit := s, ""
+	if i >= 0 {
+		num, unit = s[:i], strings.ToUpper(strings.TrimSpace(s[i:]))
+	}
+	mult, ok := byteUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit %q; use B, KB, MB, GB, TB or KiB, MiB, GiB, TiB", strings.TrimSpace(s[i:]))
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	v := f * mult
+	if v >= math.MaxInt64 {
+		return 0, fmt.Errorf("size %q is too large", s)
+	}
+	return int64(v), nil
+}
+
+// formatByteSize formats n with binary units, e.g. "10 GiB".
+func formatByteSize(n int64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	f, u := float64(n), 0
+	for f >= 1024 && u < len(units)-1 {
+		f /= 1024
+		u++
+	}
+	if f == math.Trunc(f) {
+		return fmt.Sprintf("%.0f %s", f, units[u])
+	}
+	return fmt.Sprintf("%.2f %s", f, units[u])
+}
+
+// bytesBilledLimit returns the maximum bytes billed for queries billed to
+// project, 0 for no limit, and where the limit comes from.
+func (a *App) bytesBilledLimit(project string) (int64, string) {
+	if v, err := a.store.GetSetting(maxBytesBilledProjectKey + project); err == nil && v != "" {
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n, "the limit for project " + project
+	}
+	v, _ := a.store.GetSetting(maxBytesBilledKey)
+	n, _ := strconv.ParseInt(v, 10, 64)
+	return n, "the global limit"
+}
+
+// queryOptions returns the options applied to every query billed to
+// project, labelled with the source that issued it.
+func (a *App) queryOptions(project, source string) bq.QueryOptions {
+	return a.tabQueryOptions(project, source, ui.QueryTab{})
+}
+
+// editorQueryOptions returns the options of a query run from the editor
+// tab tab.
+func (a *App) editorQueryOptions(project string, tab ui.QueryTab) bq.QueryOptions {
+	return a.tabQueryOptions(project, sourceEditor, tab)
+}
+
+func (a *App) tabQueryOptions(project, source string, tab ui.QueryTab) bq.QueryOptions {
+	limit, _ := a.bytesBilledLimit(project)
+	header, _ := a.store.GetSetting(jobCommentHeaderKey)
+	return bq.QueryOptions{
+		MaxBytesBilled: limit,
+		Labels:         a.jobLabels(source, tab),
+		CommentHeader:  header == "true",
+	}
+}
+
+// showQuerySettingsDialog edits the global and the current project's
+// maximum bytes billed and on-demand rate, the project's safety mode and
+// the job labels added to every query.
+func (a *App) showQuerySettingsDialog() {
+	project := a.editor.GetCurrentProject()
+
+	sizeText := func(key string) string {
+		v, _ := a.store.GetSetting(key)
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			return formatByteSize(n)
+		} else if err == nil {
+			return "0"
+		}
+		return ""
+	}
+	setting := func(key string) string {
+		v, _ := a.store.GetSetting(key)
+		return v
+	}
+
+	globalLimit := widget.NewEntry()
+	globalLimit.SetPlaceHolder("no limit, e.g. 100 GB")
+	globalLimit.SetText(sizeText(maxBytesBilledKey))
+	globalRate := widget.NewEntry()
+	globalRate.SetPlaceHolder(fmt.Sprintf("%.2f", defaultTiBRate))
+	globalRate.SetText(setting(tibRateKey))
+	items := []*widget.FormItem{
+		widget.NewFormItem("Max billed, all projects", globalLimit),
+		widget.NewFormItem("$ per TiB, all projects", globalRate),
+	}
+	projectLimit, projectRate := widget.NewEntry(), widget.NewEntry()
+	var safety *widget.Select
+	safetyModes := []safetyMode{"", safetyReadOnly, safetyConfirm, safetyOff}
+	if project != "" {
+		options := make([]string, len(safetyModes))
+		for i, m := range safetyModes {
+			options[i] = safetyModeLabels[m]
+		}
+		options[0] = "Default (" + safetyModeLabels[defaultSafetyMode(project)] + ")"
+		safety = widget.NewSelect(options, nil)
+		current, _ := a.store.GetSetting(safetyModeProjectKey + project)
+		safety.SetSelectedIndex(0)
+		for i, m := range safetyModes {
+			if string(m) == current {
+				safety.SetSelectedIndex(i)
+			}
+		}
+		projectLimit.SetPlaceHolder("use the global limit; 0 for none")
+		projectLimit.SetText(sizeText(maxBytesBilledProjectKey + project))
+		projectRate.SetPlaceHolder("use the global rate")
+		projectRate.SetText(setting(tibRateProjectKey + project))
+		items = append(items,
+			widget.NewFormItem("Max billed, "+project, projectLimit),
+			widget.NewFormItem("$ per TiB, "+project, projectRate),
+			widget.NewFormItem("Safety, "+project, safety),
+		)
+	}
+	labelsEntry := widget.NewEntry()
+	labelsEntry.SetPlaceHolder("e.g. team=analytics, cost_center=42")
+	labelsEntry.SetText(setting(jobLabelsKey))
+	commentCheck := widget.NewCheck("Also name labels in a SQL comment", nil)
+	commentCheck.SetChecked(setting(jobCommentHeaderKey) == "true")
+	items = append(items,
+		widget.NewFormItem("Job labels", labelsEntry),
+		widget.NewFormItem("", commentCheck),
+	)
+	hint := widget.NewLabel("BigQuery refuses queries that would bill more than the limit before reading any data. " +
+		"Limits apply to queries from the editor, the AI assistant, profiles and saved results. " +
+		"The rate estimates on-demand cost in the Spend tab. " +
+		"Safety mode blocks DML, DDL and scripts (read-only) or asks you to type the target first; production projects default to read-only. " +
+		"Job labels (plus tool, source, user and tab) tag every query for INFORMATION_SCHEMA.JOBS and billing exports.")
+	hint.Wrapping = fyne.TextWrapWord
+	items = append(items, widget.NewFormItem("", hint))
+
+	d := dialog.NewForm("Query Settings", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		type field struct {
+			key   string
+			entry *widget.Entry
+			parse func(string) (string, error)
+		}
+		fields := []field{{maxBytesBilledKey, globalLimit, limitSetting}, {tibRateKey, globalRate, rateSetting}}
+		if project != "" {
+			fields = append(fields,
+				field{maxBytesBilledProjectKey + project, projectLimit, limitSetting},
+				field{tibRateProjectKey + project, projectRate, rateSetting})
+		}
+		// Validate every entry before saving any.
+		settings := map[string]string{}
+		for _, f := range fields {
+			v, err := f.parse(f.entry.Text)
+			if err != nil {
+				a.showError("Query Settings", err)
+				return
+			}
+			settings[f.key] = v
+		}
+		if _, err := bq.ParseLabels(labelsEntry.Text); err != nil {
+			a.showError("Query Settings", err)
+			return
+		}
+		settings[jobLabelsKey] = labelsEntry.Text
+		settings[jobCommentHeaderKey] = strconv.FormatBool(commentCheck.Checked)
+		if safety != nil {
+			settings[safetyModeProjectKey+project] = string(safetyModes[safety.SelectedIndex()])
+		}
+		for k, v := range settings {
+			_ = a.store.SetSetting(k, v)
+		}
+		go a.refreshSpend()
+	}, a.window)
+	d.Resize(fyne.NewSize(560, 0))
+	d.Show()
+}
+
+// limitSetting stores an empty entry as "" (unset) and anything else as a
+// byte count.
+func limitSetting(text string) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return "", nil
+	}
+	n, err := parseByteSize(text)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(n, 10), nil
+}
+
+// showBytesBilledLimitDialog explains why a query was refused and offers to
+// run it once without the limit.
+func (a *App) showBytesBilledLimitDialog(project, sqlText string, tab ui.QueryTab, err error) {
+	limit, source := a.bytesBilledLimit(project)
+	msg := fmt.Sprintf("BigQuery refused this query because it would bill more than %s, %s.\n\n"+
+		"No data was read and nothing was billed. Narrow the query (filter on partitions, select fewer columns) "+
+		"or run it once without the limit. Limits are set under Query Settings in the toolbar.\n\n%v",
+		formatByteSize(limit), source, err)
+	fyne.Do(func() {
+		label := widget.NewLabel(msg)
+		label.Wrapping = fyne.TextWrapWord
+		d := dialog.NewCustomConfirm("Bytes Billed Limit Exceeded", "Run Once Without Limit", "Cancel", label, func(ok bool) {
+			if ok {
+				go a.runQueryWithOptions(project, sqlText, tab, a.unlimitedQueryOptions(project, tab))
+			}
+		}, a.window)
+		d.Resize(fyne.NewSize(520, 0))
+		d.Show()
+	})
+}
+
+// unlimitedQueryOptions returns the options of an editor query run once
+// without the bytes billed limit; its labels and comment header are kept.
+func (a *App) unlimitedQueryOptions(project string, tab ui.QueryTab) bq.QueryOptions {
+	opts := a.editorQueryOptions(project, tab)
+	opts.MaxBytesBilled = 0
+	return opts
+}
+
+// errBytesBilledLimit is returned to the AI assistant for queries refused by
+// the limit; it cannot override it.
+func errBytesBilledLimit(limit int64, source string) error {
+	return errors.New("query refused: it would bill more than " + formatByteSize(limit) + ", " + source +
+		". Narrow the query (filter partitions, select fewer columns) or ask the user to run it themselves")
+}
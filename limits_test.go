@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/farbodahm/delephon/ui"
+)
+
+func TestParseByteSize(t *testing.T) {
+	for in, want := range map[string]int64{
+		"":          0,
+		"0":         0,
+		"1048576":   1 << 20,
+		"500 MB":    500e6,
+		"1.5TiB":    3 << 39,
+		"10 gib":    10 << 30,
+		" 2 KB ":    2000,
+		"100 bytes": -1,
+		"GB":        -1,
+		"-1 GB":     -1,
+	} {
+		got, err := parseByteSize(in)
+		if want < 0 {
+			if err == nil {
+				t.Errorf("parseByteSize(%q) = %d, want error", in, got)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("parseByteSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	for n, want := range map[int64]string{
+		0:        "0 B",
+		10 << 30: "10 GiB",
+		3 << 39:  "1.50 TiB",
+	} {
+		if got := formatByteSize(n); got != want {
+			t.Errorf("formatByteSize(%d) = %q, want %q", n, got, want)
+		}
+		if n > 0 {
+			if back, _ := parseByteSize(formatByteSize(n)); back != n {
+				t.Errorf("round trip of %d = %d", n, back)
+			}
+		}
+	}
+}
+
+func TestUnlimitedQueryOptions_KeepsLabels(t *testing.T) {
+	a := newSettingsApp(t)
+	for key, v := range map[string]string{
+		maxBytesBilledKey:   "1000",
+		jobLabelsKey:        "team=data",
+		jobCommentHeaderKey: "true",
+	} {
+		if err := a.store.SetSetting(key, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tab := ui.QueryTab{Name: "revenue", Labels: map[string]string{"report": "weekly"}}
+
+	opts := a.unlimitedQueryOptions("proj", tab)
+	if opts.MaxBytesBilled != 0 {
+		t.Errorf("MaxBytesBilled = %d, want no limit", opts.MaxBytesBilled)
+	}
+	if !opts.CommentHeader {
+		t.Error("comment header dropped")
+	}
+	for k, want := range map[string]string{"source": sourceEditor, "team": "data", "tab": "revenue", "report": "weekly"} {
+		if opts.Labels[k] != want {
+			t.Errorf("label %s = %q, want %q", k, opts.Labels[k], want)
+		}
+	}
+}
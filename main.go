@@ -43,6 +43,9 @@ func main() {
 
 	window.SetContent(application.BuildUI())
 
+	// Reopen the tabs and layout of the last workspace
+	application.RestoreWorkspace()
+
 	// Load favorites + recent projects from local DB (no GCP API call)
 	application.LoadInitialProjects()
 
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+
+	"github.com/farbodahm/delephon/ui"
+)
+
+// profileResults profiles the rows in the Results tab on the client.
+func (a *App) profileResults() {
+	result := a.lastResult
+	if result == nil || len(result.Columns) == 0 {
+		a.profile.SetStatus("Run a query first; its results are profiled without querying again.")
+		return
+	}
+	source := "Query results"
+	if result.Truncated() {
+		source = fmt.Sprintf("Query results (first %d of %d rows)", result.RowCount, result.TotalRows)
+	}
+	a.profile.ProfileResult(source, result.Columns, result.ColumnTypes, result.Rows)
+	a.bottomTabs.Select(a.profileTab)
+}
+
+// profileTable profiles a table on the server with approximate aggregates.
+func (a *App) profileTable(project, dataset, table string) {
+	name := fmt.Sprintf("%s.%s.%s", project, dataset, table)
+	fyne.Do(func() { a.bottomTabs.Select(a.profileTab) })
+	a.profile.SetStatus(fmt.Sprintf("Profiling %s (scans every column)...", name))
+	start := time.Now()
+
+	profiles, cost, err := a.bqMgr.ProfileTable(a.ctx, project, dataset, table, a.queryOptions(project, sourceProfile))
+	a.recordJobCost(sourceProfile, project, cost)
+	if err != nil {
+		a.profile.SetStatus(fmt.Sprintf("Error: %v", err))
+		return
+	}
+	out := make([]ui.ColumnProfile, len(profiles))
+	for i, p := range profiles {
+		cp := ui.ColumnProfile{
+			Name:     p.Name,
+			Type:     p.Type,
+			Rows:     p.Rows,
+			Nulls:    p.Nulls,
+			Distinct: p.Distinct,
+			Approx:   true,
+			Min:      p.Min,
+			Max:      p.Max,
+			Numeric:  p.Numeric,
+			Mean:     p.Mean,
+			StdDev:   p.StdDev,
+		}
+		for _, t := range p.Top {
+			cp.Top = append(cp.Top, ui.ValueCount{Value: t.Value, Count: t.Count})
+		}
+		for _, b := range p.Histogram {
+			cp.Histogram = append(cp.Histogram, ui.HistogramBin{Lo: b.Lo, Hi: b.Hi, Count: b.Count})
+		}
+		out[i] = cp
+	}
+	a.profile.SetProfiles(name, out, fmt.Sprintf("Approximate statistics over the full table, computed in %s",
+		time.Since(start).Round(time.Millisecond)))
+}
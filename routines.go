@@ -0,0 +1,178 @@
+package main
+
+import (
+	"log"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/farbodahm/delephon/bq"
+	"github.com/farbodahm/delephon/ui"
+)
+
+// routineKinds names the routine types in the routine dialog.
+var routineKinds = map[string]string{
+	bq.RoutineScalarFunction:    "Function",
+	bq.RoutineAggregateFunction: "Aggregate function",
+	bq.RoutineTableFunction:     "Table function",
+	bq.RoutineProcedure:         "Stored procedure",
+}
+
+// listRoutines lists the routine names of a dataset and keeps them for
+// autocomplete, along with signatures already loaded. Routines are optional
+// in the explorer, so a failure is logged and yields none.
+func (a *App) listRoutines(project, dataset string) []bq.RoutineInfo {
+	routines, err := a.bqMgr.ListRoutines(a.ctx, project, dataset)
+	if err != nil {
+		log.Printf("explorer: error listing routines of %s.%s: %v", project, dataset, err)
+	}
+	a.routinesMu.Lock()
+	if a.routines == nil {
+		a.routines = make(map[string][]bq.RoutineInfo)
+	}
+	key := ui.DatasetNodeID(project, dataset)
+	loaded := make(map[string]bq.RoutineInfo)
+	for _, r := range a.routines[key] {
+		if r.Type != "" {
+			loaded[r.RoutineID] = r
+		}
+	}
+	for i, r := range routines {
+		if l, ok := loaded[r.RoutineID]; ok {
+			routines[i] = l
+		}
+	}
+	a.routines[key] = routines
+	a.routinesMu.Unlock()
+	return routines
+}
+
+// cacheRoutine keeps a routine's metadata for autocomplete, in place of its
+// listed name.
+func (a *App) cacheRoutine(r bq.RoutineInfo) {
+	a.routinesMu.Lock()
+	defer a.routinesMu.Unlock()
+	if a.routines == nil {
+		a.routines = make(map[string][]bq.RoutineInfo)
+	}
+	key := ui.DatasetNodeID(r.ProjectID, r.DatasetID)
+	for i, known := range a.routines[key] {
+		if known.RoutineID == r.RoutineID {
+			a.routines[key][i] = r
+			return
+		}
+	}
+	a.routines[key] = append(a.routines[key], r)
+}
+
+// loadRoutineSignature reads a routine's metadata when autocomplete first
+// needs its signature.
+func (a *App) loadRoutineSignature(project, dataset, routine string) {
+	r, err := a.bqMgr.GetRoutine(a.ctx, project, dataset, routine)
+	if err != nil {
+		log.Printf("autocomplete: error reading routine %s.%s.%s: %v", project, dataset, routine, err)
+		return
+	}
+	a.cacheRoutine(*r)
+	a.updateCompletions()
+}
+
+// knownRoutines returns the routines listed so far, for autocomplete.
+// Routines listed by name only have no signature until their metadata is
+// read; only metadata sets Type.
+func (a *App) knownRoutines() []ui.Routine {
+	a.routinesMu.Lock()
+	defer a.routinesMu.Unlock()
+	var out []ui.Routine
+	for _, routines := range a.routines {
+		for _, r := range routines {
+			rt := ui.Routine{Project: r.ProjectID, Dataset: r.DatasetID, Name: r.RoutineID}
+			if r.Type != "" {
+				rt.Signature = r.Signature()
+			}
+			out = append(out, rt)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Project != out[j].Project {
+			return out[i].Project < out[j].Project
+		}
+		if out[i].Dataset != out[j].Dataset {
+			return out[i].Dataset < out[j].Dataset
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// routineCallSQL returns a statement that calls a routine, with its
+// argument names as placeholders.
+func routineCallSQL(r bq.RoutineInfo) string {
+	args := make([]string, len(r.Arguments))
+	for i, arg := range r.Arguments {
+		args[i] = arg.Name
+	}
+	call := "`" + r.FullName() + "`(" + strings.Join(args, ", ") + ")"
+	switch r.Type {
+	case bq.RoutineProcedure:
+		return "CALL " + call + ";"
+	case bq.RoutineTableFunction:
+		return "SELECT *\nFROM " + call + "\nLIMIT 1000"
+	}
+	return "SELECT " + call
+}
+
+// showRoutine shows a routine's signature, language and body, and offers
+// to open a call of it in a new editor tab.
+func (a *App) showRoutine(project, dataset, routine string) {
+	r, err := a.bqMgr.GetRoutine(a.ctx, project, dataset, routine)
+	if err != nil {
+		a.showError("Routine", err)
+		return
+	}
+	a.cacheRoutine(*r)
+	fyne.Do(func() {
+		value := func(s string) *widget.Label {
+			l := widget.NewLabel(s)
+			l.Wrapping = fyne.TextWrapWord
+			l.Selectable = true
+			return l
+		}
+		kind := routineKinds[r.Type]
+		if kind == "" {
+			kind = r.Type
+		}
+		items := []*widget.FormItem{
+			widget.NewFormItem("Routine", value(r.FullName())),
+			widget.NewFormItem("Type", value(kind)),
+			widget.NewFormItem("Language", value(r.Language)),
+			widget.NewFormItem("Signature", value(r.Signature())),
+		}
+		if r.Description != "" {
+			items = append(items, widget.NewFormItem("Description", value(r.Description)))
+		}
+		if len(r.Libraries) > 0 {
+			items = append(items, widget.NewFormItem("Libraries", value(strings.Join(r.Libraries, "\n"))))
+		}
+		items = append(items, widget.NewFormItem("Last modified", value(r.Modified.Local().Format("2006-01-02 15:04"))))
+
+		body := widget.NewLabel(r.Body)
+		body.TextStyle = fyne.TextStyle{Monospace: true}
+		body.Selectable = true
+		bodyTitle := widget.NewLabelWithStyle("Body", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		content := container.NewBorder(container.NewVBox(widget.NewForm(items...), bodyTitle), nil, nil, nil,
+			container.NewScroll(body))
+
+		d := dialog.NewCustomConfirm("Routine", "Open Call in Editor", "Close", content, func(ok bool) {
+			if ok {
+				a.editor.NewTabWithSQL(r.RoutineID, project, routineCallSQL(*r))
+			}
+		}, a.window)
+		d.Resize(fyne.NewSize(720, 560))
+		d.Show()
+	})
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/farbodahm/delephon/bq"
+)
+
+func TestRoutineCallSQL(t *testing.T) {
+	args := []bq.RoutineArgument{{Name: "day", Type: "DATE"}, {Name: "n", Type: "INT64"}}
+	for typ, want := range map[string]string{
+		bq.RoutineScalarFunction: "SELECT `p.ds.r`(day, n)",
+		bq.RoutineTableFunction:  "SELECT *\nFROM `p.ds.r`(day, n)\nLIMIT 1000",
+		bq.RoutineProcedure:      "CALL `p.ds.r`(day, n);",
+	} {
+		r := bq.RoutineInfo{ProjectID: "p", DatasetID: "ds", RoutineID: "r", Type: typ, Arguments: args}
+		if got := routineCallSQL(r); got != want {
+			t.Errorf("routineCallSQL(%s) = %q, want %q", typ, got, want)
+		}
+	}
+}
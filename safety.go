@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/farbodahm/delephon/bq"
+)
+
+// safetyMode controls which statements may run against a project.
+type safetyMode string
+
+const (
+	safetyReadOnly safetyMode = "read-only" // refuse DML, DDL and scripts
+	safetyConfirm  safetyMode = "confirm"   // require typing the target to run them
+	safetyOff      safetyMode = "off"       // run anything
+)
+
+var safetyModeLabels = map[safetyMode]string{
+	safetyReadOnly: "Read-only",
+	safetyConfirm:  "Confirm writes",
+	safetyOff:      "Off",
+}
+
+// safetyModeProjectKey prefixes the per-project safety mode setting. When a
+// project has none, production projects are read-only and others are off.
+const safetyModeProjectKey = "safety_mode/"
+
+// productionProject matches project IDs that look like production, such
+// as "acme-prod" or "production-data", but not "preprod" or "product-x".
+var productionProject = regexp.MustCompile(`(?i)(^|[-_.])(prod|prd|production)($|[-_.\d])`)
+
+// defaultSafetyMode returns the mode of a project without its own setting.
+func defaultSafetyMode(project string) safetyMode {
+	if productionProject.MatchString(project) {
+		return safetyReadOnly
+	}
+	return safetyOff
+}
+
+// safetyMode returns the effective safety mode of project.
+func (a *App) safetyMode(project string) safetyMode {
+	v, _ := a.store.GetSetting(safetyModeProjectKey + project)
+	if _, ok := safetyModeLabels[safetyMode(v)]; ok {
+		return safetyMode(v)
+	}
+	return defaultSafetyMode(project)
+}
+
+// writingStatements returns the statements of sqlText that may write. When
+// the parser cannot tell, e.g. for EXECUTE IMMEDIATE or CALL, BigQuery is
+// asked with a dry run; scripts are treated as writing.
+func (a *App) writingStatements(ctx context.Context, project, sqlText string) []bq.Statement {
+	var writes []bq.Statement
+	onlyUnknown := true
+	for _, s := range bq.ClassifyStatements(sqlText) {
+		if s.Writes() {
+			writes = append(writes, s)
+			onlyUnknown = onlyUnknown && s.Kind == bq.StatementScript
+		}
+	}
+	if len(writes) > 0 && onlyUnknown {
+		if typ, err := a.bqMgr.DryRunStatementType(ctx, project, sqlText); err == nil && typ == "SELECT" {
+			return nil
+		}
+	}
+	return writes
+}
+
+// describeStatements summarizes writing statements, e.g.
+// "DELETE on ds.t, DROP TABLE on ds.u".
+func describeStatements(stmts []bq.Statement) string {
+	parts := make([]string, len(stmts))
+	for i, s := range stmts {
+		parts[i] = s.Type
+		if s.Target != "" {
+			parts[i] += " on " + s.Target
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// confirmationText is what the user must type to run writing statements:
+// their single target, or the project ID when there are several or none.
+func confirmationText(stmts []bq.Statement, project string) string {
+	target := ""
+	for _, s := range stmts {
+		if s.Target == "" || (target != "" && s.Target != target) {
+			return project
+		}
+		target = s.Target
+	}
+	return target
+}
+
+// statementProject returns the project a writing statement changes: the
+// one its target names, or project when the target names none.
+func statementProject(s bq.Statement, project string) string {
+	parts := strings.Split(s.Target, ".")
+	switch {
+	case strings.HasSuffix(s.Type, " PROJECT") && parts[0] != "":
+		return parts[0]
+	case len(parts) == 3, len(parts) == 2 && strings.HasSuffix(s.Type, " SCHEMA"):
+		return parts[0]
+	}
+	return project
+}
+
+// strictestMode returns the strictest safety mode among project, which
+// runs sqlText, and the projects its writing statements change, along with
+// the project it belongs to.
+func (a *App) strictestMode(project, sqlText string) (safetyMode, string) {
+	mode, modeProject := a.safetyMode(project), project
+	for _, s := range bq.ClassifyStatements(sqlText) {
+		if mode == safetyReadOnly {
+			break
+		}
+		if !s.Writes() {
+			continue
+		}
+		p := statementProject(s, project)
+		if m := a.safetyMode(p); m == safetyReadOnly || (m == safetyConfirm && mode == safetyOff) {
+			mode, modeProject = m, p
+		}
+	}
+	return mode, modeProject
+}
+
+// guardStatements calls run if the safety modes of project and the
+// projects sqlText writes to allow it, asks for typed confirmation first in
+// confirm mode, and refuses writing statements in read-only mode. The
+// strictest mode among those projects applies.
+func (a *App) guardStatements(project, sqlText string, run func()) {
+	mode, modeProject := a.strictestMode(project, sqlText)
+	if mode == safetyOff {
+		run()
+		return
+	}
+	writes := a.writingStatements(a.ctx, project, sqlText)
+	if len(writes) == 0 {
+		run()
+		return
+	}
+	desc := describeStatements(writes)
+	if mode == safetyReadOnly {
+		a.results.SetStatus(fmt.Sprintf("Blocked: %s is read-only (%s)", modeProject, desc))
+		a.showReadOnlyDialog(modeProject, fmt.Sprintf("this query was not run:\n\n%s\n\nOnly SELECT queries run in read-only projects.", desc))
+		return
+	}
+
+	want := confirmationText(writes, modeProject)
+	a.confirmTyped("Confirm Changes", "Run", fmt.Sprintf("This query changes data or objects in %s:\n\n%s\n\nType %s to run it.",
+		modeProject, desc, want), want, run)
+}
+
+// showReadOnlyDialog explains that an action was refused because project
+// is read-only; refused describes what was not done.
+func (a *App) showReadOnlyDialog(project, refused string) {
+	fyne.Do(func() {
+		label := widget.NewLabel(fmt.Sprintf("Project %s is read-only, so %s\n\n"+
+			"To allow changes, set the project's safety mode to \"Confirm writes\" or \"Off\" under Query Settings.", project, refused))
+		label.Wrapping = fyne.TextWrapWord
+		d := dialog.NewCustom("Read-only Project", "OK", label, a.window)
+		d.Resize(fyne.NewSize(520, 0))
+		d.Show()
+	})
+}
+
+// confirmTyped asks the user to type want before calling run in the
+// background, e.g. the name of a table about to be changed.
+func (a *App) confirmTyped(title, confirm, message, want string, run func()) {
+	fyne.Do(func() {
+		entry := widget.NewEntry()
+		entry.SetPlaceHolder(want)
+		entry.Validator = func(s string) error {
+			if strings.Trim(strings.TrimSpace(s), "`") != want {
+				return errors.New("does not match")
+			}
+			return nil
+		}
+		label := widget.NewLabel(message)
+		label.Wrapping = fyne.TextWrapWord
+		d := dialog.NewForm(title, confirm, "Cancel",
+			[]*widget.FormItem{
+				widget.NewFormItem("", label),
+				widget.NewFormItem("Confirm", entry),
+			},
+			func(ok bool) {
+				if ok {
+					go run()
+				}
+			}, a.window)
+		d.Resize(fyne.NewSize(520, 0))
+		d.Show()
+		a.window.Canvas().Focus(entry)
+	})
+}
+
+// errWritesNotAllowed is returned to the AI assistant for writing
+// statements in a project with a safety mode; it cannot confirm them.
+func errWritesNotAllowed(project string, mode safetyMode, stmts []bq.Statement) error {
+	return fmt.Errorf("query refused: project %s is in %q safety mode and the assistant may only run SELECT queries (found %s)",
+		project, safetyModeLabels[mode], describeStatements(stmts))
+}
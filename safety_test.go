@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/farbodahm/delephon/bq"
+)
+
+func TestDefaultSafetyMode(t *testing.T) {
+	for project, want := range map[string]safetyMode{
+		"acme-prod":       safetyReadOnly,
+		"prod-analytics":  safetyReadOnly,
+		"data_production": safetyReadOnly,
+		"acme-prd-2":      safetyReadOnly,
+		"acme-prod1":      safetyReadOnly,
+		"acme-preprod":    safetyOff,
+		"product-catalog": safetyOff,
+		"acme-dev":        safetyOff,
+	} {
+		if got := defaultSafetyMode(project); got != want {
+			t.Errorf("defaultSafetyMode(%q) = %q, want %q", project, got, want)
+		}
+	}
+}
+
+func TestConfirmationText(t *testing.T) {
+	del := bq.Statement{Kind: bq.StatementDML, Type: "DELETE", Target: "ds.t"}
+	drop := bq.Statement{Kind: bq.StatementDDL, Type: "DROP TABLE", Target: "ds.u"}
+	script := bq.Statement{Kind: bq.StatementScript, Type: "CALL"}
+	for _, tc := range []struct {
+		stmts []bq.Statement
+		want  string
+	}{
+		{[]bq.Statement{del}, "ds.t"},
+		{[]bq.Statement{del, del}, "ds.t"},
+		{[]bq.Statement{del, drop}, "proj"},
+		{[]bq.Statement{script}, "proj"},
+	} {
+		if got := confirmationText(tc.stmts, "proj"); got != tc.want {
+			t.Errorf("confirmationText(%+v) = %q, want %q", tc.stmts, got, tc.want)
+		}
+	}
+	if got := describeStatements([]bq.Statement{del, script}); got != "DELETE on ds.t, CALL" {
+		t.Errorf("describeStatements = %q", got)
+	}
+}
+
+func TestStatementProject(t *testing.T) {
+	for _, tc := range []struct {
+		stmt bq.Statement
+		want string
+	}{
+		{bq.Statement{Kind: bq.StatementDML, Type: "DELETE", Target: "acme-prod.ds.t"}, "acme-prod"},
+		{bq.Statement{Kind: bq.StatementDDL, Type: "DROP TABLE", Target: "ds.t"}, "dev"},
+		{bq.Statement{Kind: bq.StatementDDL, Type: "DROP SCHEMA", Target: "acme-prod.ds"}, "acme-prod"},
+		{bq.Statement{Kind: bq.StatementDDL, Type: "DROP SCHEMA", Target: "ds"}, "dev"},
+		{bq.Statement{Kind: bq.StatementDDL, Type: "ALTER PROJECT", Target: "acme-prod"}, "acme-prod"},
+		{bq.Statement{Kind: bq.StatementScript, Type: "CALL"}, "dev"},
+	} {
+		if got := statementProject(tc.stmt, "dev"); got != tc.want {
+			t.Errorf("statementProject(%+v) = %q, want %q", tc.stmt, got, tc.want)
+		}
+	}
+}
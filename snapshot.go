@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/farbodahm/delephon/bq"
+	"github.com/farbodahm/delephon/store"
+	"github.com/farbodahm/delephon/ui"
+)
+
+// saveSnapshot asks for a name and stores the current result set locally,
+// linked to the history entry of the run that produced it.
+func (a *App) saveSnapshot() {
+	result := a.lastResult
+	if result == nil || len(result.Columns) == 0 {
+		return
+	}
+	historyID := a.lastHistoryID
+
+	name := widget.NewEntry()
+	name.SetText(fmt.Sprintf("%s %s", a.lastRunName, time.Now().Format("2006-01-02 15:04")))
+	items := []*widget.FormItem{widget.NewFormItem("Name", name)}
+	if result.Truncated() {
+		note := widget.NewLabel(fmt.Sprintf("Only the %d fetched rows of %d are saved.", result.RowCount, result.TotalRows))
+		items = append(items, widget.NewFormItem("", note))
+	}
+	dialog.ShowForm("Save Snapshot", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		sn := store.Snapshot{
+			HistoryID: historyID,
+			Name:      strings.TrimSpace(name.Text),
+			SQL:       result.SQL,
+			Project:   result.ProjectID,
+			TotalRows: result.TotalRows,
+			Rows:      result.Rows,
+			Nulls:     result.Nulls,
+		}
+		if sn.Name == "" {
+			sn.Name = "Snapshot"
+		}
+		for i, c := range result.Columns {
+			sn.Columns = append(sn.Columns, store.SnapshotColumn{Name: c, Type: result.ColumnTypes[i]})
+		}
+		go func() {
+			if _, err := a.store.SaveSnapshot(sn); err != nil {
+				a.showError("Snapshot Error", err)
+				return
+			}
+			a.results.SetStatus(fmt.Sprintf("Saved snapshot %q (%d rows)", sn.Name, len(sn.Rows)))
+			a.refreshHistory()
+		}()
+	}, a.window)
+}
+
+// openSnapshot shows a saved snapshot in the results grid without querying
+// BigQuery.
+func (a *App) openSnapshot(id int64) {
+	sn, err := a.store.GetSnapshot(id)
+	if err != nil {
+		a.showError("Snapshot Error", err)
+		return
+	}
+	result := &bq.QueryResult{
+		Rows:      sn.Rows,
+		Nulls:     sn.Nulls,
+		RowCount:  int64(len(sn.Rows)),
+		TotalRows: sn.TotalRows,
+		SQL:       sn.SQL,
+		ProjectID: sn.Project,
+	}
+	for _, c := range sn.Columns {
+		result.Columns = append(result.Columns, c.Name)
+		result.ColumnTypes = append(result.ColumnTypes, c.Type)
+	}
+	a.showResult(result, "Snapshot "+sn.Name)
+	a.results.SetStatus(fmt.Sprintf("Snapshot %q | %d rows | saved %s (offline)",
+		sn.Name, result.RowCount, sn.CreatedAt.Format("2006-01-02 15:04")))
+	fyne.Do(func() { a.bottomTabs.SelectIndex(0) })
+}
+
+func (a *App) deleteSnapshot(id int64) {
+	dialog.ShowConfirm("Delete Snapshot", "Delete this snapshot? Its rows cannot be recovered.", func(ok bool) {
+		if !ok {
+			return
+		}
+		go func() {
+			if err := a.store.DeleteSnapshot(id); err != nil {
+				a.showError("Snapshot Error", err)
+				return
+			}
+			a.refreshHistory()
+		}()
+	}, a.window)
+}
+
+func (a *App) refreshSnapshots() {
+	snapshots, err := a.store.ListSnapshots()
+	if err != nil {
+		return
+	}
+	infos := make([]ui.SnapshotInfo, len(snapshots))
+	for i, sn := range snapshots {
+		infos[i] = ui.SnapshotInfo{
+			ID:        sn.ID,
+			Name:      sn.Name,
+			SQL:       sn.SQL,
+			Project:   sn.Project,
+			CreatedAt: sn.CreatedAt,
+			RowCount:  sn.RowCount,
+		}
+	}
+	a.history.SetSnapshots(infos)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"github.com/farbodahm/delephon/bq"
+	"github.com/farbodahm/delephon/ui"
+)
+
+// execStats converts a query's execution statistics for the Execution
+// Details tab. It returns nil when there are none, e.g. for snapshots.
+func execStats(s *bq.QueryStats) *ui.ExecStats {
+	if s == nil {
+		return nil
+	}
+	out := &ui.ExecStats{
+		StatementType:    s.StatementType,
+		CacheHit:         s.CacheHit,
+		BytesProcessed:   s.BytesProcessed,
+		BytesBilled:      s.BytesBilled,
+		SlotMillis:       s.SlotMillis,
+		ReferencedTables: s.ReferencedTables,
+	}
+	if !s.Started.IsZero() && s.Ended.After(s.Started) {
+		out.Elapsed = s.Ended.Sub(s.Started)
+	}
+	for _, st := range s.Stages {
+		stage := ui.ExecStage{
+			ID:              st.ID,
+			Name:            st.Name,
+			Status:          st.Status,
+			Inputs:          st.InputStages,
+			RecordsRead:     st.RecordsRead,
+			RecordsWritten:  st.RecordsWritten,
+			ShuffleBytes:    st.ShuffleBytes,
+			ShuffleSpilled:  st.ShuffleSpilled,
+			ParallelInputs:  st.ParallelInputs,
+			CompletedInputs: st.CompletedInputs,
+			WaitRatioAvg:    st.WaitRatioAvg,
+			WaitRatioMax:    st.WaitRatioMax,
+			ReadRatioAvg:    st.ReadRatioAvg,
+			ReadRatioMax:    st.ReadRatioMax,
+			ComputeRatioAvg: st.ComputeRatioAvg,
+			ComputeRatioMax: st.ComputeRatioMax,
+			WriteRatioAvg:   st.WriteRatioAvg,
+			WriteRatioMax:   st.WriteRatioMax,
+			ComputeAvg:      st.ComputeAvg,
+			ComputeMax:      st.ComputeMax,
+			Steps:           st.Steps,
+		}
+		if !st.Start.IsZero() && st.End.After(st.Start) {
+			stage.Duration = st.End.Sub(st.Start)
+		}
+		out.Stages = append(out.Stages, stage)
+	}
+	for _, t := range s.Timeline {
+		out.Timeline = append(out.Timeline, ui.ExecSample{
+			Elapsed:      t.Elapsed,
+			ActiveUnits:  t.ActiveUnits,
+			PendingUnits: t.PendingUnits,
+			SlotMillis:   t.SlotMillis,
+		})
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package store
+
+import "time"
+
+// AuditEntry records a change made to BigQuery objects from the app, such
+// as copying or deleting a table, and whether it succeeded.
+type AuditEntry struct {
+	ID        int64
+	Timestamp time.Time
+	Action    string // e.g. "copy table" or "delete table"
+	Project   string // project of the changed object
+	Target    string // object acted on, e.g. project.dataset.table
+	Detail    string // e.g. the destination of a copy
+	Error     string // empty when the change succeeded
+}
+
+// AddAudit appends an entry to the audit log. Timestamp defaults to now.
+func (s *Store) AddAudit(e AuditEntry) error {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	_, err := s.db.Exec(
+		`INSERT INTO audit_log (timestamp, action, project, target, detail, error) VALUES (?, ?, ?, ?, ?, ?)`,
+		e.Timestamp, e.Action, e.Project, e.Target, e.Detail, e.Error,
+	)
+	return err
+}
+
+// ListAudit returns the most recent audit log entries, newest first.
+func (s *Store) ListAudit(limit int) ([]AuditEntry, error) {
+	rows, err := s.db.Query(
+		`SELECT id, timestamp, action, project, target, detail, error FROM audit_log ORDER BY id DESC LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		if err := rows.Scan(&e.ID, &e.Timestamp, &e.Action, &e.Project, &e.Target, &e.Detail, &e.Error); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,160 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// CostEntry is one query in the cost ledger.
+type CostEntry struct {
+	ID             int64
+	HistoryID      int64  // history entry of the run; 0 if none (e.g. AI tool queries)
+	Source         string // what issued the query, e.g. "editor" or "ai"
+	Project        string // billing project
+	SQL            string
+	Fingerprint    string // set by AddCost from SQL
+	BytesProcessed int64
+	BytesBilled    int64
+	CacheHit       bool
+	RatePerTiB     float64 // on-demand price used for Cost, in dollars
+	Cost           float64 // estimated on-demand cost in dollars
+	Timestamp      time.Time
+}
+
+// SpendGrouping selects how SpendBy aggregates the ledger.
+type SpendGrouping int
+
+const (
+	SpendByDay SpendGrouping = iota
+	SpendByProject
+	SpendByQuery // by query fingerprint
+)
+
+// SpendGroup is the spend of one day, project or query fingerprint.
+type SpendGroup struct {
+	Key         string // day as YYYY-MM-DD, project, or fingerprint
+	SQL         string // latest query text, for fingerprint groups
+	Projects    string // comma-separated projects, for fingerprint groups
+	Runs        int64
+	BytesBilled int64
+	Cost        float64
+	Last        time.Time
+}
+
+// AddCost records a query's estimated cost. The fingerprint is computed
+// from the SQL, and Timestamp defaults to now.
+func (s *Store) AddCost(e CostEntry) error {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	_, err := s.db.Exec(
+		`INSERT INTO cost_ledger (history_id, source, project, sql_text, fingerprint, day, timestamp,
+		                          bytes_processed, bytes_billed, cache_hit, rate_per_tib, cost)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.HistoryID, e.Source, e.Project, e.SQL, QueryFingerprint(e.SQL), e.Timestamp.Local().Format(time.DateOnly), e.Timestamp,
+		e.BytesProcessed, e.BytesBilled, e.CacheHit, e.RatePerTiB, e.Cost,
+	)
+	return err
+}
+
+// SpendBy aggregates the ledger from the local day of since onwards; a
+// zero since covers everything. Days are newest first, other groups most
+// expensive first.
+func (s *Store) SpendBy(group SpendGrouping, since time.Time) ([]SpendGroup, error) {
+	from := ""
+	if !since.IsZero() {
+		from = since.Local().Format(time.DateOnly)
+	}
+	var key, order string
+	switch group {
+	case SpendByDay:
+		key, order = "day", "day DESC"
+	case SpendByProject:
+		key, order = "project", "SUM(cost) DESC, project"
+	case SpendByQuery:
+		key, order = "fingerprint", "SUM(cost) DESC, COUNT(*) DESC"
+	default:
+		return nil, fmt.Errorf("unknown spend grouping %d", group)
+	}
+	rows, err := s.db.Query(
+		`SELECT `+key+`, COUNT(*), SUM(bytes_billed), SUM(cost), MAX(id), GROUP_CONCAT(DISTINCT project)
+		 FROM cost_ledger WHERE day >= ? GROUP BY `+key+` ORDER BY `+order,
+		from,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []SpendGroup
+	var lastIDs []int64
+	for rows.Next() {
+		var g SpendGroup
+		var lastID int64
+		if err := rows.Scan(&g.Key, &g.Runs, &g.BytesBilled, &g.Cost, &lastID, &g.Projects); err != nil {
+			return nil, err
+		}
+		out = append(out, g)
+		lastIDs = append(lastIDs, lastID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i, id := range lastIDs {
+		if err := s.db.QueryRow(`SELECT sql_text, timestamp FROM cost_ledger WHERE id = ?`, id).Scan(&out[i].SQL, &out[i].Last); err != nil {
+			return nil, err
+		}
+		if group != SpendByQuery {
+			out[i].SQL = ""
+		}
+	}
+	return out, nil
+}
+
+// fingerprintToken matches, leftmost first, the parts of a query that
+// normalizing treats specially: quoted identifiers, comments, string and
+// bytes literals, and numbers.
+var fingerprintToken = regexp.MustCompile("(?s)`[^`]*`" +
+	`|--[^\n]*|#[^\n]*|/\*.*?\*/` +
+	`|(?:\b[rRbB]{1,2})?(?:'''.*?'''|""".*?"""|'(?:\\.|[^'\\\n])*'|"(?:\\.|[^"\\\n])*")` +
+	`|\b\d+(?:\.\d+)?(?:[eE][+-]?\d+)?\b`)
+
+var (
+	fingerprintList  = regexp.MustCompile(`\?(?:\s*,\s*\?)+`)
+	fingerprintSpace = regexp.MustCompile(`\s+`)
+)
+
+// NormalizeSQL reduces a query to its shape: comments removed, literals
+// replaced with ?, lists of literals collapsed, whitespace collapsed and
+// everything but quoted identifiers lowercased.
+func NormalizeSQL(sqlText string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range fingerprintToken.FindAllStringIndex(sqlText, -1) {
+		b.WriteString(strings.ToLower(sqlText[last:m[0]]))
+		tok := sqlText[m[0]:m[1]]
+		switch {
+		case strings.HasPrefix(tok, "`"):
+			b.WriteString(tok)
+		case strings.HasPrefix(tok, "--"), strings.HasPrefix(tok, "#"), strings.HasPrefix(tok, "/*"):
+			b.WriteString(" ")
+		default:
+			b.WriteString("?")
+		}
+		last = m[1]
+	}
+	b.WriteString(strings.ToLower(sqlText[last:]))
+	out := fingerprintList.ReplaceAllString(b.String(), "?")
+	out = fingerprintSpace.ReplaceAllString(strings.TrimSpace(out), " ")
+	return strings.TrimRight(out, "; ")
+}
+
+// QueryFingerprint identifies queries with the same shape, so repeated runs
+// with different literals group together.
+func QueryFingerprint(sqlText string) string {
+	sum := sha256.Sum256([]byte(NormalizeSQL(sqlText)))
+	return hex.EncodeToString(sum[:8])
+}
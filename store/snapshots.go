@@ -0,0 +1,185 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Snapshot is a saved result set that can be reopened without querying
+// BigQuery again.
+type Snapshot struct {
+	ID        int64
+	HistoryID int64 // history entry of the run that produced it; 0 if unknown
+	Name      string
+	SQL       string
+	Project   string
+	CreatedAt time.Time
+	RowCount  int64  // rows saved
+	TotalRows uint64 // rows in the full result; more than RowCount when truncated
+	Columns   []SnapshotColumn
+	Rows      [][]string // display values, with SQL NULL as "NULL"; nil in listings
+	Nulls     [][]bool   // per row, the cells that are SQL NULL; nil for rows without any
+}
+
+type SnapshotColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// SaveSnapshot stores a result set and returns the new snapshot's ID.
+func (s *Store) SaveSnapshot(sn Snapshot) (int64, error) {
+	schema, err := json.Marshal(sn.Columns)
+	if err != nil {
+		return 0, err
+	}
+	rows := encodeSnapshotRows(sn.Columns, sn.Rows, sn.Nulls)
+	res, err := s.db.Exec(
+		`INSERT INTO snapshots (history_id, name, sql_text, project, created_at, row_count, total_rows, schema, rows)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		sn.HistoryID, sn.Name, sn.SQL, sn.Project, time.Now(), len(sn.Rows), int64(sn.TotalRows), string(schema), rows,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// ListSnapshots returns all snapshots, newest first, without their rows.
+func (s *Store) ListSnapshots() ([]Snapshot, error) {
+	rows, err := s.db.Query(
+		`SELECT id, history_id, name, sql_text, project, created_at, row_count, total_rows, schema
+		 FROM snapshots ORDER BY created_at DESC, id DESC`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Snapshot
+	for rows.Next() {
+		var sn Snapshot
+		var total int64
+		var schema string
+		if err := rows.Scan(&sn.ID, &sn.HistoryID, &sn.Name, &sn.SQL, &sn.Project, &sn.CreatedAt, &sn.RowCount, &total, &schema); err != nil {
+			return nil, err
+		}
+		sn.TotalRows = uint64(total)
+		if err := json.Unmarshal([]byte(schema), &sn.Columns); err != nil {
+			return nil, fmt.Errorf("snapshot %d schema: %w", sn.ID, err)
+		}
+		out = append(out, sn)
+	}
+	return out, rows.Err()
+}
+
+// GetSnapshot loads a snapshot with its rows.
+func (s *Store) GetSnapshot(id int64) (*Snapshot, error) {
+	sn := &Snapshot{ID: id}
+	var total int64
+	var schema, data string
+	err := s.db.QueryRow(
+		`SELECT history_id, name, sql_text, project, created_at, row_count, total_rows, schema, rows
+		 FROM snapshots WHERE id = ?`, id,
+	).Scan(&sn.HistoryID, &sn.Name, &sn.SQL, &sn.Project, &sn.CreatedAt, &sn.RowCount, &total, &schema, &data)
+	if err != nil {
+		return nil, err
+	}
+	sn.TotalRows = uint64(total)
+	if err := json.Unmarshal([]byte(schema), &sn.Columns); err != nil {
+		return nil, fmt.Errorf("snapshot schema: %w", err)
+	}
+	if sn.Rows, sn.Nulls, err = decodeSnapshotRows(data); err != nil {
+		return nil, fmt.Errorf("snapshot rows: %w", err)
+	}
+	return sn, nil
+}
+
+func (s *Store) DeleteSnapshot(id int64) error {
+	_, err := s.db.Exec(`DELETE FROM snapshots WHERE id = ?`, id)
+	return err
+}
+
+// encodeSnapshotRows stores rows as JSON arrays, one per line. Numbers and
+// booleans are unquoted, other values (nested ones included) are JSON
+// strings, and only the cells marked in nulls are null, so a JSON null value
+// stays apart from SQL NULL. The display text is preserved exactly.
+func encodeSnapshotRows(columns []SnapshotColumn, rows [][]string, nulls [][]bool) string {
+	var b bytes.Buffer
+	for ri, row := range rows {
+		var rowNulls []bool
+		if ri < len(nulls) {
+			rowNulls = nulls[ri]
+		}
+		b.WriteByte('[')
+		for i, v := range row {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if i < len(rowNulls) && rowNulls[i] {
+				b.WriteString("null")
+				continue
+			}
+			typ := ""
+			if i < len(columns) {
+				typ = columns[i].Type
+			}
+			b.Write(snapshotValue(v, typ))
+		}
+		b.WriteString("]\n")
+	}
+	return b.String()
+}
+
+// snapshotValue encodes a non-NULL display value.
+func snapshotValue(v, typ string) []byte {
+	raw := []byte(v)
+	switch {
+	case typ == "INTEGER" || typ == "INT64" || typ == "FLOAT" || typ == "FLOAT64":
+		var n json.Number
+		if json.Unmarshal(raw, &n) == nil && n.String() == v {
+			return raw
+		}
+	case typ == "BOOLEAN" || typ == "BOOL":
+		if v == "true" || v == "false" {
+			return raw
+		}
+	}
+	q, _ := json.Marshal(v)
+	return q
+}
+
+// decodeSnapshotRows is the inverse of encodeSnapshotRows.
+func decodeSnapshotRows(data string) (rows [][]string, nulls [][]bool, err error) {
+	for _, line := range strings.Split(data, "\n") {
+		if line == "" {
+			continue
+		}
+		var raw []json.RawMessage
+		if err := json.Unmarshal([]byte(line), &raw); err != nil {
+			return nil, nil, err
+		}
+		row := make([]string, len(raw))
+		var rowNulls []bool
+		for i, r := range raw {
+			switch {
+			case string(r) == "null":
+				row[i] = "NULL"
+				if rowNulls == nil {
+					rowNulls = make([]bool, len(raw))
+				}
+				rowNulls[i] = true
+			case len(r) > 0 && r[0] == '"':
+				if err := json.Unmarshal(r, &row[i]); err != nil {
+					return nil, nil, err
+				}
+			default:
+				row[i] = string(r)
+			}
+		}
+		rows = append(rows, row)
+		nulls = append(nulls, rowNulls)
+	}
+	return rows, nulls, nil
+}
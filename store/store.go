@@ -18,6 +18,11 @@ type HistoryEntry struct {
 	Duration  time.Duration
 	RowCount  int64
 	Error     string
+
+	// SnapshotID and SnapshotName identify the latest snapshot saved from
+	// this run; SnapshotID is 0 when there is none.
+	SnapshotID   int64
+	SnapshotName string
 }
 
 type Favorite struct {
@@ -88,6 +93,49 @@ func (s *Store) migrate() error {
 		CREATE TABLE IF NOT EXISTS favorite_projects (
 			project_id TEXT PRIMARY KEY
 		);
+		CREATE TABLE IF NOT EXISTS workspaces (
+			name TEXT PRIMARY KEY,
+			state TEXT NOT NULL DEFAULT '',
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE IF NOT EXISTS snapshots (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			history_id INTEGER NOT NULL DEFAULT 0,
+			name TEXT NOT NULL,
+			sql_text TEXT NOT NULL DEFAULT '',
+			project TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			row_count INTEGER NOT NULL DEFAULT 0,
+			total_rows INTEGER NOT NULL DEFAULT 0,
+			schema TEXT NOT NULL DEFAULT '[]',
+			rows TEXT NOT NULL DEFAULT ''
+		);
+		CREATE INDEX IF NOT EXISTS snapshots_history ON snapshots (history_id);
+		CREATE TABLE IF NOT EXISTS cost_ledger (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			history_id INTEGER NOT NULL DEFAULT 0,
+			source TEXT NOT NULL DEFAULT '',
+			project TEXT NOT NULL DEFAULT '',
+			sql_text TEXT NOT NULL DEFAULT '',
+			fingerprint TEXT NOT NULL DEFAULT '',
+			day TEXT NOT NULL,
+			timestamp DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			bytes_processed INTEGER NOT NULL DEFAULT 0,
+			bytes_billed INTEGER NOT NULL DEFAULT 0,
+			cache_hit INTEGER NOT NULL DEFAULT 0,
+			rate_per_tib REAL NOT NULL DEFAULT 0,
+			cost REAL NOT NULL DEFAULT 0
+		);
+		CREATE INDEX IF NOT EXISTS cost_ledger_day ON cost_ledger (day);
+		CREATE TABLE IF NOT EXISTS audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			timestamp DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			action TEXT NOT NULL,
+			project TEXT NOT NULL DEFAULT '',
+			target TEXT NOT NULL DEFAULT '',
+			detail TEXT NOT NULL DEFAULT '',
+			error TEXT NOT NULL DEFAULT ''
+		);
 	`)
 	return err
 }
@@ -98,12 +146,16 @@ func (s *Store) Close() error {
 
 // History
 
-func (s *Store) AddHistory(sqlText, project string, dur time.Duration, rowCount int64, queryErr string) error {
-	_, err := s.db.Exec(
+// AddHistory records a query run and returns the new entry's ID.
+func (s *Store) AddHistory(sqlText, project string, dur time.Duration, rowCount int64, queryErr string) (int64, error) {
+	res, err := s.db.Exec(
 		`INSERT INTO history (sql_text, project, timestamp, duration_ms, row_count, error) VALUES (?, ?, ?, ?, ?, ?)`,
 		sqlText, project, time.Now(), dur.Milliseconds(), rowCount, queryErr,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
 }
 
 func (s *Store) ListHistory(limit int) ([]HistoryEntry, error) {
@@ -111,7 +163,11 @@ func (s *Store) ListHistory(limit int) ([]HistoryEntry, error) {
 		limit = 200
 	}
 	rows, err := s.db.Query(
-		`SELECT id, sql_text, project, timestamp, duration_ms, row_count, error FROM history ORDER BY timestamp DESC LIMIT ?`,
+		`SELECT h.id, h.sql_text, h.project, h.timestamp, h.duration_ms, h.row_count, h.error,
+		        COALESCE(sn.id, 0), COALESCE(sn.name, '')
+		 FROM history h
+		 LEFT JOIN snapshots sn ON sn.id = (SELECT MAX(id) FROM snapshots WHERE history_id = h.id)
+		 ORDER BY h.timestamp DESC LIMIT ?`,
 		limit,
 	)
 	if err != nil {
@@ -122,7 +178,7 @@ func (s *Store) ListHistory(limit int) ([]HistoryEntry, error) {
 	for rows.Next() {
 		var e HistoryEntry
 		var ms int64
-		if err := rows.Scan(&e.ID, &e.SQL, &e.Project, &e.Timestamp, &ms, &e.RowCount, &e.Error); err != nil {
+		if err := rows.Scan(&e.ID, &e.SQL, &e.Project, &e.Timestamp, &ms, &e.RowCount, &e.Error, &e.SnapshotID, &e.SnapshotName); err != nil {
 			return nil, err
 		}
 		e.Duration = time.Duration(ms) * time.Millisecond
@@ -187,6 +243,50 @@ func (s *Store) SetSetting(key, value string) error {
 	return err
 }
 
+// Workspaces
+
+// SaveWorkspace stores the serialized state of a named workspace, replacing any previous state.
+func (s *Store) SaveWorkspace(name, state string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO workspaces (name, state, updated_at) VALUES (?, ?, ?)
+		 ON CONFLICT(name) DO UPDATE SET state = excluded.state, updated_at = excluded.updated_at`,
+		name, state, time.Now(),
+	)
+	return err
+}
+
+// GetWorkspace returns the serialized state of a workspace, or "" if it has never been saved.
+func (s *Store) GetWorkspace(name string) (string, error) {
+	var state string
+	err := s.db.QueryRow(`SELECT state FROM workspaces WHERE name = ?`, name).Scan(&state)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return state, err
+}
+
+func (s *Store) ListWorkspaces() ([]string, error) {
+	rows, err := s.db.Query(`SELECT name FROM workspaces ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var n string
+		if err := rows.Scan(&n); err != nil {
+			return nil, err
+		}
+		names = append(names, n)
+	}
+	return names, rows.Err()
+}
+
+func (s *Store) DeleteWorkspace(name string) error {
+	_, err := s.db.Exec(`DELETE FROM workspaces WHERE name = ?`, name)
+	return err
+}
+
 // Favorite Projects
 
 func (s *Store) AddFavoriteProject(projectID string) error {
@@ -2,6 +2,7 @@ package store
 
 import (
 	"database/sql"
+	"reflect"
 	"testing"
 	"time"
 
@@ -248,3 +249,245 @@ func TestListRecentProjects(t *testing.T) {
 		t.Fatalf("expected 2 projects with limit, got %d", len(limited))
 	}
 }
+
+func TestWorkspaces(t *testing.T) {
+	s := newTestStore(t)
+
+	// Missing workspace returns empty state
+	state, err := s.GetWorkspace("default")
+	if err != nil {
+		t.Fatalf("GetWorkspace: %v", err)
+	}
+	if state != "" {
+		t.Errorf("expected empty state for unknown workspace, got %q", state)
+	}
+
+	if err := s.SaveWorkspace("default", `{"tabs":1}`); err != nil {
+		t.Fatalf("SaveWorkspace: %v", err)
+	}
+	if err := s.SaveWorkspace("billing investigation", `{"tabs":2}`); err != nil {
+		t.Fatalf("SaveWorkspace: %v", err)
+	}
+
+	// Overwrite keeps a single row per name
+	if err := s.SaveWorkspace("default", `{"tabs":3}`); err != nil {
+		t.Fatalf("SaveWorkspace overwrite: %v", err)
+	}
+	state, _ = s.GetWorkspace("default")
+	if state != `{"tabs":3}` {
+		t.Errorf("expected overwritten state, got %q", state)
+	}
+
+	names, err := s.ListWorkspaces()
+	if err != nil {
+		t.Fatalf("ListWorkspaces: %v", err)
+	}
+	if len(names) != 2 || names[0] != "billing investigation" || names[1] != "default" {
+		t.Errorf("expected sorted workspace names, got %v", names)
+	}
+
+	if err := s.DeleteWorkspace("billing investigation"); err != nil {
+		t.Fatalf("DeleteWorkspace: %v", err)
+	}
+	names, _ = s.ListWorkspaces()
+	if len(names) != 1 {
+		t.Errorf("expected 1 workspace after delete, got %v", names)
+	}
+}
+
+func TestSnapshots(t *testing.T) {
+	s := newTestStore(t)
+
+	histID, err := s.AddHistory("SELECT * FROM t", "proj-a", time.Second, 3, "")
+	if err != nil || histID == 0 {
+		t.Fatalf("AddHistory: id %d, err %v", histID, err)
+	}
+
+	cols := []SnapshotColumn{
+		{Name: "n", Type: "INTEGER"},
+		{Name: "x", Type: "FLOAT"},
+		{Name: "s", Type: "STRING"},
+		{Name: "ok", Type: "BOOLEAN"},
+		{Name: "j", Type: "JSON"},
+		{Name: "amount", Type: "NUMERIC"},
+	}
+	rows := [][]string{
+		{"1", "1.5e-07", "NULL", "true", `{"b":1,"a":[2]}`, "12.50"},
+		{"NULL", "+Inf", "line\nbreak", "false", `"text"`, "NULL"},
+		{"3", "2", `"quoted"`, "NULL", "NULL", "0"},
+	}
+	// The first row's "NULL" is a string, not SQL NULL.
+	nulls := [][]bool{
+		nil,
+		{true, false, false, false, false, true},
+		{false, false, false, true, true, false},
+	}
+	id, err := s.SaveSnapshot(Snapshot{
+		HistoryID: histID,
+		Name:      "before refactor",
+		SQL:       "SELECT * FROM t",
+		Project:   "proj-a",
+		TotalRows: 10,
+		Columns:   cols,
+		Rows:      rows,
+		Nulls:     nulls,
+	})
+	if err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+
+	got, err := s.GetSnapshot(id)
+	if err != nil {
+		t.Fatalf("GetSnapshot: %v", err)
+	}
+	if !reflect.DeepEqual(got.Rows, rows) {
+		t.Errorf("rows did not round-trip:\ngot  %q\nwant %q", got.Rows, rows)
+	}
+	if !reflect.DeepEqual(got.Nulls, nulls) {
+		t.Errorf("nulls did not round-trip:\ngot  %v\nwant %v", got.Nulls, nulls)
+	}
+	if !reflect.DeepEqual(got.Columns, cols) || got.RowCount != 3 || got.TotalRows != 10 || got.HistoryID != histID {
+		t.Errorf("snapshot metadata = %+v", got)
+	}
+
+	list, err := s.ListSnapshots()
+	if err != nil || len(list) != 1 || list[0].Name != "before refactor" || list[0].Rows != nil {
+		t.Errorf("ListSnapshots = %+v, %v", list, err)
+	}
+
+	entries, _ := s.ListHistory(10)
+	if entries[0].SnapshotID != id || entries[0].SnapshotName != "before refactor" {
+		t.Errorf("history entry not linked to snapshot: %+v", entries[0])
+	}
+
+	if err := s.DeleteSnapshot(id); err != nil {
+		t.Fatalf("DeleteSnapshot: %v", err)
+	}
+	entries, _ = s.ListHistory(10)
+	if entries[0].SnapshotID != 0 {
+		t.Errorf("history still links to deleted snapshot: %+v", entries[0])
+	}
+}
+
+func TestSnapshotRows_JSONNull(t *testing.T) {
+	cols := []SnapshotColumn{{Name: "j", Type: "JSON"}, {Name: "a", Type: "ARRAY<STRING>"}}
+	rows := [][]string{{"null", `["null"]`}, {"NULL", "[]"}}
+	nulls := [][]bool{nil, {true, false}}
+
+	gotRows, gotNulls, err := decodeSnapshotRows(encodeSnapshotRows(cols, rows, nulls))
+	if err != nil {
+		t.Fatalf("decodeSnapshotRows: %v", err)
+	}
+	if !reflect.DeepEqual(gotRows, rows) || !reflect.DeepEqual(gotNulls, nulls) {
+		t.Errorf("got rows %q nulls %v, want %q %v", gotRows, gotNulls, rows, nulls)
+	}
+}
+
+func TestSnapshotValueTyped(t *testing.T) {
+	tests := []struct{ v, typ, want string }{
+		{"42", "INTEGER", "42"},
+		{"1.5", "FLOAT", "1.5"},
+		{"NaN", "FLOAT", `"NaN"`},
+		{"true", "BOOLEAN", "true"},
+		{"12.50", "NUMERIC", `"12.50"`}, // kept as text to preserve precision
+		{"[1,2]", "ARRAY<INTEGER>", `"[1,2]"`},
+		{"null", "JSON", `"null"`},   // a JSON null; SQL NULL is marked separately
+		{"NULL", "STRING", `"NULL"`}, // a string; SQL NULL is marked separately
+	}
+	for _, tt := range tests {
+		if got := string(snapshotValue(tt.v, tt.typ)); got != tt.want {
+			t.Errorf("snapshotValue(%q, %s) = %s, want %s", tt.v, tt.typ, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeSQL(t *testing.T) {
+	for in, want := range map[string]string{
+		"SELECT * FROM t WHERE id = 42;":                          "select * from t where id = ?",
+		"select *\n  from t -- don't\nwhere id=7":                 "select * from t where id=?",
+		"SELECT a FROM `Proj.ds.T1` WHERE s IN ('a', 'b', \"c\")": "select a from `Proj.ds.T1` where s in (?)",
+		"SELECT x1, 1.5e3 /* it's */ FROM t WHERE d > r'x\\d'":    "select x1, ? from t where d > ?",
+	} {
+		if got := NormalizeSQL(in); got != want {
+			t.Errorf("NormalizeSQL(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if QueryFingerprint("SELECT 1 FROM t WHERE a = 'x'") != QueryFingerprint("select 2\nfrom t where a = 'y'") {
+		t.Error("queries differing only in literals should share a fingerprint")
+	}
+	if QueryFingerprint("SELECT a FROM t") == QueryFingerprint("SELECT b FROM t") {
+		t.Error("different queries should have different fingerprints")
+	}
+}
+
+func TestSpendBy(t *testing.T) {
+	s := newTestStore(t)
+	day1 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	for _, e := range []CostEntry{
+		{Project: "a", SQL: "SELECT * FROM t WHERE id = 1", BytesBilled: 100, Cost: 1.0, Timestamp: day1},
+		{Project: "b", SQL: "SELECT * FROM t WHERE id = 2", BytesBilled: 300, Cost: 3.0, Timestamp: day2},
+		{Project: "a", SQL: "SELECT count(*) FROM u", BytesBilled: 50, Cost: 0.5, Timestamp: day2},
+	} {
+		if err := s.AddCost(e); err != nil {
+			t.Fatalf("AddCost: %v", err)
+		}
+	}
+
+	days, err := s.SpendBy(SpendByDay, time.Time{})
+	if err != nil {
+		t.Fatalf("SpendBy day: %v", err)
+	}
+	if len(days) != 2 || days[0].Key != "2026-03-02" || days[0].Cost != 3.5 || days[0].Runs != 2 {
+		t.Errorf("by day = %+v", days)
+	}
+
+	projects, _ := s.SpendBy(SpendByProject, time.Time{})
+	if len(projects) != 2 || projects[0].Key != "b" || projects[1].BytesBilled != 150 {
+		t.Errorf("by project = %+v", projects)
+	}
+
+	queries, _ := s.SpendBy(SpendByQuery, time.Time{})
+	if len(queries) != 2 || queries[0].Runs != 2 || queries[0].Cost != 4 {
+		t.Fatalf("by query = %+v", queries)
+	}
+	if queries[0].SQL != "SELECT * FROM t WHERE id = 2" || queries[0].Projects == "" || !queries[0].Last.Equal(day2) {
+		t.Errorf("latest run of fingerprint = %+v", queries[0])
+	}
+
+	recent, _ := s.SpendBy(SpendByProject, day2)
+	if len(recent) != 2 || recent[1].Cost != 0.5 {
+		t.Errorf("since day 2 = %+v", recent)
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	s := newTestStore(t)
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, e := range []AuditEntry{
+		{Action: "copy table", Project: "p", Target: "p.ds.a", Detail: "to p.ds.b", Timestamp: at},
+		{Action: "delete table", Project: "p", Target: "p.ds.a", Error: "access denied"},
+	} {
+		if err := s.AddAudit(e); err != nil {
+			t.Fatalf("AddAudit: %v", err)
+		}
+	}
+
+	entries, err := s.ListAudit(10)
+	if err != nil {
+		t.Fatalf("ListAudit: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Action != "delete table" || entries[0].Error != "access denied" || entries[0].Timestamp.IsZero() {
+		t.Errorf("newest entry = %+v", entries[0])
+	}
+	if entries[1].Detail != "to p.ds.b" || !entries[1].Timestamp.Equal(at) {
+		t.Errorf("oldest entry = %+v", entries[1])
+	}
+
+	if entries, _ := s.ListAudit(1); len(entries) != 1 {
+		t.Errorf("limit 1 returned %d entries", len(entries))
+	}
+}
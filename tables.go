@@ -0,0 +1,405 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/farbodahm/delephon/bq"
+	"github.com/farbodahm/delephon/store"
+	"github.com/farbodahm/delephon/ui"
+)
+
+// Actions recorded in the audit log.
+const (
+	auditCopyTable       = "copy table"
+	auditSnapshotTable   = "snapshot table"
+	auditCloneTable      = "clone table"
+	auditRenameTable     = "rename table"
+	auditSetExpiration   = "set expiration"
+	auditEditTable       = "edit table"
+	auditDeleteTable     = "delete table"
+	auditUploadFile      = "upload file"
+	auditSaveResults     = "save results"
+	auditCreateDataset   = "create dataset"
+	auditEditDataset     = "edit dataset"
+	auditBlockedReadOnly = "blocked: project is read-only"
+)
+
+// maxAuditEntries caps how many audit log entries the Jobs tab shows.
+const maxAuditEntries = 500
+
+// tableAction starts a change to a table chosen from the explorer.
+func (a *App) tableAction(action ui.TableAction, project, dataset, table string) {
+	switch action {
+	case ui.TableDDL:
+		go a.openTableDDL(project, dataset, table)
+	case ui.TableCopy:
+		a.showCopyTableDialog(project, dataset, table, false)
+	case ui.TableSnapshot:
+		a.showCopyTableDialog(project, dataset, table, true)
+	case ui.TableRename:
+		a.showRenameTableDialog(project, dataset, table)
+	case ui.TableExpiration:
+		go a.showExpirationDialog(project, dataset, table)
+	case ui.TableEdit:
+		go a.showEditTableDialog(project, dataset, table)
+	case ui.TableDelete:
+		a.confirmDeleteTable(project, dataset, table)
+	}
+}
+
+// openTableDDL copies a table's CREATE statement to the clipboard and opens
+// it in a new editor tab, e.g. to recreate its structure elsewhere.
+func (a *App) openTableDDL(project, dataset, table string) {
+	ddl, cost, err := a.bqMgr.TableDDL(a.ctx, project, dataset, table, a.queryOptions(project, sourceExplorer))
+	a.recordJobCost(sourceExplorer, project, cost)
+	if err != nil {
+		a.showError("Copy CREATE Statement", err)
+		return
+	}
+	fyne.Do(func() {
+		fyne.CurrentApp().Clipboard().SetContent(ddl)
+		a.editor.NewTabWithSQL("DDL "+table, project, ddl)
+	})
+}
+
+// projectWritable reports whether datasets and tables of project may be
+// changed. For a read-only project it explains the refusal and records it
+// in the audit log.
+func (a *App) projectWritable(project, action, target string) bool {
+	if a.safetyMode(project) != safetyReadOnly {
+		return true
+	}
+	a.audit(store.AuditEntry{Action: action, Project: project, Target: target, Error: auditBlockedReadOnly})
+	a.showReadOnlyDialog(project, fmt.Sprintf("%s was not changed.", target))
+	return false
+}
+
+// changeTable runs a change as a background job shown in the Jobs tab,
+// records its outcome in the audit log and reloads the datasets it touched
+// in the explorer.
+func (a *App) changeTable(e store.AuditEntry, title string, reload []string, change func(ctx context.Context) (string, error)) {
+	ctx, cancel := context.WithCancel(a.ctx)
+	defer cancel()
+
+	id := a.jobs.Start(title, cancel)
+	fyne.Do(func() { a.bottomTabs.Select(a.jobsTab) })
+
+	detail, err := change(ctx)
+	if errors.Is(err, context.Canceled) {
+		err = errors.New("cancelled")
+	}
+	if detail != "" {
+		e.Detail = strings.TrimPrefix(e.Detail+" | "+detail, " | ")
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	a.jobs.Finish(id, err, detail)
+	a.audit(e)
+	for _, nodeID := range reload {
+		a.explorer.ReloadChildren(nodeID)
+	}
+}
+
+// audit records an entry in the audit log and refreshes its view.
+func (a *App) audit(e store.AuditEntry) {
+	if err := a.store.AddAudit(e); err != nil {
+		log.Printf("audit log: %v", err)
+	}
+	a.refreshAudit()
+}
+
+func (a *App) refreshAudit() {
+	entries, err := a.store.ListAudit(maxAuditEntries)
+	if err != nil {
+		return
+	}
+	uiEntries := make([]ui.AuditEntry, len(entries))
+	for i, e := range entries {
+		uiEntries[i] = ui.AuditEntry{
+			Timestamp: e.Timestamp,
+			Action:    e.Action,
+			Target:    e.Target,
+			Detail:    e.Detail,
+			Error:     e.Error,
+		}
+	}
+	a.jobs.Audit.SetEntries(uiEntries)
+}
+
+func tableName(project, dataset, table string) string {
+	return project + "." + dataset + "." + table
+}
+
+// showCopyTableDialog asks where to copy a table to, or where to create a
+// snapshot or clone of it when snapshot is set.
+func (a *App) showCopyTableDialog(project, dataset, table string, snapshot bool) {
+	source := tableName(project, dataset, table)
+	projectEntry, datasetEntry := a.newDatasetEntries(project, dataset)
+	tableEntry := widget.NewEntry()
+	tableEntry.SetPlaceHolder("table")
+
+	kindRadio := widget.NewRadioGroup([]string{"Snapshot", "Clone"}, func(kind string) {
+		if kind == "Clone" {
+			tableEntry.SetText(table + "_clone")
+		} else {
+			tableEntry.SetText(table + "_snapshot_" + time.Now().Format("20060102"))
+		}
+	})
+	kindRadio.Horizontal = true
+	kindRadio.Required = true
+	writeSelect := widget.NewSelect(writeDispositionOptions, nil)
+	writeSelect.SetSelectedIndex(0)
+	expirationSelect := widget.NewSelect(expirationOptions, nil)
+	expirationSelect.SetSelectedIndex(0)
+
+	title := "Copy Table"
+	items := []*widget.FormItem{widget.NewFormItem("Source", widget.NewLabel(source))}
+	if snapshot {
+		title = "Snapshot or Clone Table"
+		kindRadio.SetSelected("Snapshot")
+		hint := widget.NewLabel("A snapshot is a read-only copy of the table as it is now; a clone is a writable copy. " +
+			"Both are billed only for data that differs from the source.")
+		hint.Wrapping = fyne.TextWrapWord
+		items = append(items, widget.NewFormItem("Create", kindRadio), widget.NewFormItem("", hint))
+	} else {
+		tableEntry.SetText(table + "_copy")
+	}
+	items = append(items,
+		widget.NewFormItem("Project", projectEntry),
+		widget.NewFormItem("Dataset", datasetEntry),
+		widget.NewFormItem("Table", tableEntry),
+	)
+	if !snapshot {
+		items = append(items, widget.NewFormItem("If table exists", writeSelect))
+	}
+	items = append(items, widget.NewFormItem("Expires", expirationSelect))
+
+	d := dialog.NewForm(title, "Create", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		dst := bq.TableDestination{
+			ProjectID:        strings.TrimSpace(projectEntry.Text),
+			DatasetID:        strings.TrimSpace(datasetEntry.Text),
+			TableID:          strings.TrimSpace(tableEntry.Text),
+			WriteDisposition: writeDispositionLabels[writeSelect.Selected],
+			Expiration:       expirations[expirationSelect.Selected],
+		}
+		if err := validateDestination(dst); err != nil {
+			a.showError(title, err)
+			return
+		}
+		operation, action := bq.OperationCopy, auditCopyTable
+		switch {
+		case snapshot && kindRadio.Selected == "Clone":
+			operation, action = bq.OperationClone, auditCloneTable
+			dst.WriteDisposition = bq.WriteEmpty
+		case snapshot:
+			operation, action = bq.OperationSnapshot, auditSnapshotTable
+			dst.WriteDisposition = bq.WriteEmpty
+		}
+		if !a.projectWritable(dst.ProjectID, action, dst.FullName()) {
+			return
+		}
+		run := func() { a.copyTable(project, dataset, table, dst, operation, action) }
+		if dst.WriteDisposition == bq.WriteTruncate {
+			a.confirmTyped("Overwrite Table", "Overwrite",
+				fmt.Sprintf("All rows of %s will be replaced with those of %s.\n\nType %s.%s to overwrite it.",
+					dst.FullName(), source, dst.DatasetID, dst.TableID),
+				dst.DatasetID+"."+dst.TableID, run)
+			return
+		}
+		go run()
+	}, a.window)
+	d.Resize(fyne.NewSize(520, 0))
+	d.Show()
+}
+
+func (a *App) copyTable(project, dataset, table string, dst bq.TableDestination, operation, action string) {
+	source := tableName(project, dataset, table)
+	e := store.AuditEntry{Action: action, Project: dst.ProjectID, Target: source, Detail: "to " + dst.FullName()}
+	if dst.WriteDisposition == bq.WriteTruncate {
+		e.Detail += " (overwrite)"
+	}
+	reload := []string{ui.DatasetNodeID(dst.ProjectID, dst.DatasetID)}
+	labels := a.queryOptions(dst.ProjectID, sourceExplorer).Labels
+	a.changeTable(e, fmt.Sprintf("%s %s to %s", strings.ToUpper(action[:1])+action[1:], source, dst.FullName()), reload,
+		func(ctx context.Context) (string, error) {
+			jobID, err := a.bqMgr.CopyTable(ctx, project, dataset, table, dst, operation, labels)
+			if jobID == "" {
+				return "", err
+			}
+			return "job " + jobID, err
+		})
+}
+
+// showRenameTableDialog asks for a table's new name, then for typed
+// confirmation, since queries using the old name stop working.
+func (a *App) showRenameTableDialog(project, dataset, table string) {
+	source := tableName(project, dataset, table)
+	if !a.projectWritable(project, auditRenameTable, source) {
+		return
+	}
+	entry := widget.NewEntry()
+	entry.SetText(table)
+	entry.Validator = func(s string) error {
+		s = strings.TrimSpace(s)
+		switch {
+		case s == "":
+			return errors.New("name is required")
+		case strings.ContainsAny(s, ".`"):
+			return errors.New("must not contain dots or backticks")
+		}
+		return nil
+	}
+	d := dialog.NewForm("Rename Table", "Rename", "Cancel",
+		[]*widget.FormItem{
+			widget.NewFormItem("Table", widget.NewLabel(source)),
+			widget.NewFormItem("New name", entry),
+		},
+		func(ok bool) {
+			newName := strings.TrimSpace(entry.Text)
+			if !ok || newName == table {
+				return
+			}
+			a.confirmTyped("Confirm Rename", "Rename",
+				fmt.Sprintf("%s will be renamed to %s. Queries, views and scheduled jobs that use the old name will fail.\n\nType %s.%s to rename it.",
+					source, newName, dataset, table),
+				dataset+"."+table,
+				func() {
+					e := store.AuditEntry{Action: auditRenameTable, Project: project, Target: source, Detail: "to " + newName}
+					a.changeTable(e, fmt.Sprintf("Rename %s to %s", source, newName), []string{ui.DatasetNodeID(project, dataset)},
+						func(ctx context.Context) (string, error) {
+							cost, err := a.bqMgr.RenameTable(ctx, project, dataset, table, newName, a.queryOptions(project, sourceExplorer))
+							a.recordJobCost(sourceExplorer, project, cost)
+							return "", err
+						})
+				})
+		}, a.window)
+	d.Resize(fyne.NewSize(480, 0))
+	d.Show()
+}
+
+// showExpirationDialog shows a table's expiration and asks for a new one.
+func (a *App) showExpirationDialog(project, dataset, table string) {
+	source := tableName(project, dataset, table)
+	if !a.projectWritable(project, auditSetExpiration, source) {
+		return
+	}
+	info, err := a.bqMgr.GetTableInfo(a.ctx, project, dataset, table)
+	if err != nil {
+		a.showError("Set Expiration", err)
+		return
+	}
+	current := "Never"
+	if !info.Expiration.IsZero() {
+		current = info.Expiration.Local().Format("2006-01-02 15:04")
+	}
+	fyne.Do(func() {
+		expirationSelect := widget.NewSelect(expirationOptions, nil)
+		expirationSelect.SetSelectedIndex(0)
+		d := dialog.NewForm("Set Expiration", "Save", "Cancel",
+			[]*widget.FormItem{
+				widget.NewFormItem("Table", widget.NewLabel(source)),
+				widget.NewFormItem("Currently expires", widget.NewLabel(current)),
+				widget.NewFormItem("Expires", expirationSelect),
+			},
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				var at time.Time
+				detail := "never expires"
+				if dur := expirations[expirationSelect.Selected]; dur > 0 {
+					at = time.Now().Add(dur)
+					detail = "expires " + at.Format("2006-01-02 15:04")
+				}
+				e := store.AuditEntry{Action: auditSetExpiration, Project: project, Target: source, Detail: detail}
+				go a.changeTable(e, fmt.Sprintf("Set expiration of %s", source), nil,
+					func(ctx context.Context) (string, error) {
+						return "", a.bqMgr.SetTableExpiration(ctx, project, dataset, table, at)
+					})
+			}, a.window)
+		d.Resize(fyne.NewSize(480, 0))
+		d.Show()
+	})
+}
+
+// showEditTableDialog edits a table's description and labels.
+func (a *App) showEditTableDialog(project, dataset, table string) {
+	source := tableName(project, dataset, table)
+	if !a.projectWritable(project, auditEditTable, source) {
+		return
+	}
+	info, err := a.bqMgr.GetTableInfo(a.ctx, project, dataset, table)
+	if err != nil {
+		a.showError("Edit Table", err)
+		return
+	}
+	fyne.Do(func() {
+		descEntry := widget.NewMultiLineEntry()
+		descEntry.Wrapping = fyne.TextWrapWord
+		descEntry.SetMinRowsVisible(4)
+		descEntry.SetText(info.Description)
+		labelsEntry := widget.NewEntry()
+		labelsEntry.SetText(bq.FormatLabels(info.Labels))
+		labelsEntry.SetPlaceHolder("e.g. owner=finance, tier=gold")
+		labelsEntry.Validator = func(s string) error {
+			_, err := bq.ParseLabels(s)
+			return err
+		}
+		d := dialog.NewForm("Edit Table", "Save", "Cancel",
+			[]*widget.FormItem{
+				widget.NewFormItem("Table", widget.NewLabel(source)),
+				widget.NewFormItem("Description", descEntry),
+				widget.NewFormItem("Labels", labelsEntry),
+			},
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				labels, err := bq.ParseLabels(labelsEntry.Text)
+				if err != nil {
+					a.showError("Edit Table", err)
+					return
+				}
+				e := store.AuditEntry{Action: auditEditTable, Project: project, Target: source, Detail: "labels: " + bq.FormatLabels(labels)}
+				go a.changeTable(e, fmt.Sprintf("Edit %s", source), nil,
+					func(ctx context.Context) (string, error) {
+						return "", a.bqMgr.UpdateTableMetadata(ctx, project, dataset, table, info, descEntry.Text, labels)
+					})
+			}, a.window)
+		d.Resize(fyne.NewSize(560, 0))
+		d.Show()
+	})
+}
+
+// confirmDeleteTable deletes a table after typed confirmation.
+func (a *App) confirmDeleteTable(project, dataset, table string) {
+	source := tableName(project, dataset, table)
+	if !a.projectWritable(project, auditDeleteTable, source) {
+		return
+	}
+	a.confirmTyped("Delete Table", "Delete",
+		fmt.Sprintf("%s will be deleted. It can only be restored with time travel for a few days, "+
+			"and not at all if it is a view; take a snapshot first to keep a copy.\n\nType %s.%s to delete it.",
+			source, dataset, table),
+		dataset+"."+table,
+		func() {
+			e := store.AuditEntry{Action: auditDeleteTable, Project: project, Target: source}
+			a.changeTable(e, "Delete "+source, []string{ui.DatasetNodeID(project, dataset)},
+				func(ctx context.Context) (string, error) {
+					return "", a.bqMgr.DeleteTable(ctx, project, dataset, table)
+				})
+		})
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// AuditEntry is a change made to BigQuery objects from the app.
+type AuditEntry struct {
+	Timestamp time.Time
+	Action    string
+	Target    string
+	Detail    string
+	Error     string
+}
+
+var auditColumns = []string{"Time", "Action", "Target", "Outcome", "Detail"}
+
+var auditWidths = []float32{140, 120, 320, 200, 400}
+
+// AuditLog lists the changes made to tables and datasets from the app,
+// newest first, including those that failed.
+type AuditLog struct {
+	table   *widget.Table
+	entries []AuditEntry
+
+	// OnRefresh is called to reload the log; the app answers with
+	// SetEntries.
+	OnRefresh func()
+
+	Container fyne.CanvasObject
+}
+
+func NewAuditLog() *AuditLog {
+	l := &AuditLog{}
+	l.table = widget.NewTableWithHeaders(
+		func() (int, int) { return len(l.entries), len(auditColumns) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			if id.Row >= len(l.entries) {
+				return
+			}
+			e := l.entries[id.Row]
+			label.Importance = widget.MediumImportance
+			if e.Error != "" {
+				label.Importance = widget.DangerImportance
+			}
+			label.SetText(auditCell(e, id.Col))
+		},
+	)
+	l.table.UpdateHeader = func(id widget.TableCellID, template fyne.CanvasObject) {
+		label := template.(*widget.Label)
+		if id.Row < 0 && id.Col >= 0 && id.Col < len(auditColumns) {
+			label.SetText(auditColumns[id.Col])
+		} else if id.Col < 0 && id.Row >= 0 {
+			label.SetText(fmt.Sprintf("%d", id.Row+1))
+		}
+	}
+	for i, w := range auditWidths {
+		l.table.SetColumnWidth(i, w)
+	}
+
+	refreshBtn := widget.NewButton("Refresh", func() {
+		if l.OnRefresh != nil {
+			l.OnRefresh()
+		}
+	})
+	note := widget.NewLabel("Changes made to tables and datasets from this app, kept locally.")
+	l.Container = container.NewBorder(container.NewBorder(nil, nil, nil, refreshBtn, note), nil, nil, nil, l.table)
+	return l
+}
+
+// SetEntries shows the log, newest first.
+func (l *AuditLog) SetEntries(entries []AuditEntry) {
+	fyne.Do(func() {
+		l.entries = entries
+		l.table.Refresh()
+	})
+}
+
+func auditCell(e AuditEntry, col int) string {
+	switch col {
+	case 0:
+		return e.Timestamp.Local().Format("2006-01-02 15:04:05")
+	case 1:
+		return e.Action
+	case 2:
+		return e.Target
+	case 3:
+		if e.Error != "" {
+			return "Failed: " + e.Error
+		}
+		return "Done"
+	case 4:
+		return e.Detail
+	}
+	return ""
+}
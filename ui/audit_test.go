@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuditCell(t *testing.T) {
+	e := AuditEntry{
+		Timestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local),
+		Action:    "delete table",
+		Target:    "p.ds.t",
+		Detail:    "job x",
+	}
+	checks := map[int]string{0: "2026-03-01 12:00:00", 1: "delete table", 2: "p.ds.t", 3: "Done", 4: "job x"}
+	for col, want := range checks {
+		if got := auditCell(e, col); got != want {
+			t.Errorf("col %d = %q, want %q", col, got, want)
+		}
+	}
+	e.Error = "access denied"
+	if got := auditCell(e, 3); got != "Failed: access denied" {
+		t.Errorf("failed outcome = %q", got)
+	}
+}
+
+func TestAuditLogSetEntries(t *testing.T) {
+	l := NewAuditLog()
+	l.SetEntries([]AuditEntry{{Action: "copy table"}, {Action: "edit table"}})
+	if rows, cols := l.table.Length(); rows != 2 || cols != len(auditColumns) {
+		t.Errorf("table size = %d x %d", rows, cols)
+	}
+}
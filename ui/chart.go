@@ -0,0 +1,583 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/software"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// chartPalette colors the series of a chart, in order.
+var chartPalette = []color.NRGBA{
+	{R: 0x42, G: 0x85, B: 0xF4, A: 0xFF},
+	{R: 0xF4, G: 0xB4, B: 0x00, A: 0xFF},
+	{R: 0x0F, G: 0x9D, B: 0x58, A: 0xFF},
+	{R: 0xDB, G: 0x44, B: 0x37, A: 0xFF},
+	{R: 0xAB, G: 0x47, B: 0xBC, A: 0xFF},
+}
+
+type shapeKind int
+
+const (
+	shapeLine shapeKind = iota
+	shapeRect
+	shapeCircle
+	shapeText
+)
+
+// chartShape is one drawing primitive. The same shapes are turned into
+// Fyne canvas objects for display and PNG export, and into SVG elements.
+type chartShape struct {
+	kind shapeKind
+	// Lines run from (x1,y1) to (x2,y2); rects span those corners; circles
+	// are centered on (x1,y1) with radius x2; text is anchored at (x1,y1).
+	x1, y1, x2, y2 float32
+	color          color.Color
+	stroke         float32
+	text           string
+	align          fyne.TextAlign // text anchor: leading, center or trailing
+	size           float32        // text size
+}
+
+// chartColors are the theme colors a chart layout is drawn with.
+type chartColors struct {
+	fg, grid color.Color
+}
+
+const (
+	chartMarginLeft   float32 = 64
+	chartMarginRight  float32 = 16
+	chartMarginTop    float32 = 28
+	chartMarginBottom float32 = 40
+	chartTextSize     float32 = 11
+)
+
+// layoutChart computes the shapes for a chart of the given size.
+func layoutChart(d chartData, w, h float32, c chartColors) []chartShape {
+	var shapes []chartShape
+	text := func(x, y float32, s string, align fyne.TextAlign, col color.Color) {
+		shapes = append(shapes, chartShape{kind: shapeText, x1: x, y1: y, text: s, align: align, color: col, size: chartTextSize})
+	}
+
+	plotL, plotR := chartMarginLeft, w-chartMarginRight
+	plotT, plotB := chartMarginTop, h-chartMarginBottom
+	if plotR-plotL < 20 || plotB-plotT < 20 {
+		return nil
+	}
+
+	var points int
+	for _, s := range d.Series {
+		points += len(s.Points)
+	}
+	if points == 0 {
+		text(w/2, h/2-chartTextSize, "No data to plot. Pick an x axis and numeric series.", fyne.TextAlignCenter, c.fg)
+		return shapes
+	}
+
+	// Data ranges.
+	xMin, xMax := math.Inf(1), math.Inf(-1)
+	yMin, yMax := 0.0, math.Inf(-1)
+	for _, s := range d.Series {
+		for _, p := range s.Points {
+			xMin, xMax = math.Min(xMin, p.X), math.Max(xMax, p.X)
+			yMin, yMax = math.Min(yMin, p.Y), math.Max(yMax, p.Y)
+		}
+	}
+	barChart := d.Kind == ChartBar || d.Kind == ChartHistogram
+	switch {
+	case d.XKind == axisCategory:
+		xMin, xMax = -0.5, float64(len(d.Categories))-0.5
+	case d.Kind == ChartHistogram:
+		xMin -= d.BinWidth / 2
+		xMax += d.BinWidth / 2
+	case barChart:
+		gap := minGap(d.Series)
+		xMin -= gap / 2
+		xMax += gap / 2
+	}
+	if xMax == xMin {
+		xMin, xMax = xMin-1, xMax+1
+	}
+	yTicks := niceTicks(yMin, yMax, 5)
+	yMin, yMax = yTicks[0], yTicks[len(yTicks)-1]
+	if yMax == yMin {
+		yMax = yMin + 1
+	}
+
+	px := func(x float64) float32 {
+		return plotL + float32((x-xMin)/(xMax-xMin))*(plotR-plotL)
+	}
+	py := func(y float64) float32 {
+		return plotB - float32((y-yMin)/(yMax-yMin))*(plotB-plotT)
+	}
+
+	// Grid and y axis labels.
+	for _, t := range yTicks {
+		y := py(t)
+		shapes = append(shapes, chartShape{kind: shapeLine, x1: plotL, y1: y, x2: plotR, y2: y, color: c.grid, stroke: 1})
+		text(plotL-6, y-chartTextSize*0.7, formatTick(t), fyne.TextAlignTrailing, c.fg)
+	}
+	// Axes.
+	shapes = append(shapes,
+		chartShape{kind: shapeLine, x1: plotL, y1: plotT, x2: plotL, y2: plotB, color: c.fg, stroke: 1},
+		chartShape{kind: shapeLine, x1: plotL, y1: plotB, x2: plotR, y2: plotB, color: c.fg, stroke: 1},
+	)
+
+	// X axis labels.
+	switch d.XKind {
+	case axisCategory:
+		step := 1
+		if maxLabels := int((plotR - plotL) / 70); maxLabels > 0 && len(d.Categories) > maxLabels {
+			step = (len(d.Categories) + maxLabels - 1) / maxLabels
+		}
+		for i := 0; i < len(d.Categories); i += step {
+			text(px(float64(i)), plotB+4, truncate(d.Categories[i], 12), fyne.TextAlignCenter, c.fg)
+		}
+	default:
+		for _, t := range niceTicks(xMin, xMax, 6) {
+			if t < xMin || t > xMax {
+				continue
+			}
+			label := formatTick(t)
+			if d.XKind == axisTime {
+				label = formatTimeTick(t, xMax-xMin)
+			}
+			text(px(t), plotB+4, label, fyne.TextAlignCenter, c.fg)
+		}
+	}
+	text((plotL+plotR)/2, plotB+chartTextSize+10, d.XName, fyne.TextAlignCenter, c.fg)
+
+	// Series.
+	zero := py(math.Max(0, yMin))
+	for si, s := range d.Series {
+		col := chartPalette[si%len(chartPalette)]
+		switch d.Kind {
+		case ChartLine:
+			for i := 1; i < len(s.Points); i++ {
+				a, b := s.Points[i-1], s.Points[i]
+				shapes = append(shapes, chartShape{kind: shapeLine, x1: px(a.X), y1: py(a.Y), x2: px(b.X), y2: py(b.Y), color: col, stroke: 2})
+			}
+			if len(s.Points) == 1 {
+				p := s.Points[0]
+				shapes = append(shapes, chartShape{kind: shapeCircle, x1: px(p.X), y1: py(p.Y), x2: 3, color: col})
+			}
+		case ChartScatter:
+			for _, p := range s.Points {
+				shapes = append(shapes, chartShape{kind: shapeCircle, x1: px(p.X), y1: py(p.Y), x2: 3, color: col})
+			}
+		case ChartBar, ChartHistogram:
+			slot := px(xMin+1) - px(xMin) // one x unit, for categories
+			switch {
+			case d.Kind == ChartHistogram:
+				slot = px(xMin+d.BinWidth) - px(xMin)
+			case d.XKind != axisCategory:
+				slot = px(xMin+minGap(d.Series)) - px(xMin)
+			}
+			group := slot * 0.8
+			if d.Kind == ChartHistogram {
+				group = slot - 1
+			}
+			bw := group / float32(len(d.Series))
+			for _, p := range s.Points {
+				left := px(p.X) - group/2 + float32(si)*bw
+				top, bottom := py(p.Y), zero
+				if top > bottom {
+					top, bottom = bottom, top
+				}
+				shapes = append(shapes, chartShape{kind: shapeRect, x1: left, y1: top, x2: left + bw, y2: bottom, color: col})
+			}
+		}
+	}
+
+	// Legend.
+	lx := plotL
+	for si, s := range d.Series {
+		col := chartPalette[si%len(chartPalette)]
+		shapes = append(shapes, chartShape{kind: shapeRect, x1: lx, y1: 8, x2: lx + 10, y2: 18, color: col})
+		text(lx+14, 6, s.Name, fyne.TextAlignLeading, c.fg)
+		lx += 24 + fyne.MeasureText(s.Name, chartTextSize, fyne.TextStyle{}).Width
+	}
+	return shapes
+}
+
+// minGap returns the smallest distance between distinct x values, used to
+// size bars on numeric and time axes.
+func minGap(series []chartSeries) float64 {
+	gap := math.Inf(1)
+	for _, s := range series {
+		for i := 1; i < len(s.Points); i++ {
+			if d := s.Points[i].X - s.Points[i-1].X; d > 0 && d < gap {
+				gap = d
+			}
+		}
+	}
+	if math.IsInf(gap, 1) {
+		return 1
+	}
+	return gap
+}
+
+// niceTicks returns evenly spaced, round tick values covering [lo, hi].
+func niceTicks(lo, hi float64, n int) []float64 {
+	if hi < lo {
+		lo, hi = hi, lo
+	}
+	if hi == lo {
+		if lo == 0 {
+			return []float64{0, 1}
+		}
+		lo, hi = lo-math.Abs(lo)/2, hi+math.Abs(hi)/2
+	}
+	raw := (hi - lo) / float64(n)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := mag
+	for _, m := range []float64{1, 2, 2.5, 5, 10} {
+		if m*mag >= raw {
+			step = m * mag
+			break
+		}
+	}
+	start := math.Floor(lo/step) * step
+	var ticks []float64
+	for t := start; t <= hi+step*0.5; t += step {
+		ticks = append(ticks, math.Round(t/step)*step)
+		if t >= hi {
+			break
+		}
+	}
+	return ticks
+}
+
+func formatTick(v float64) string {
+	abs := math.Abs(v)
+	switch {
+	case abs >= 1e9:
+		return strconv.FormatFloat(v/1e9, 'f', -1, 64) + "B"
+	case abs >= 1e6:
+		return strconv.FormatFloat(v/1e6, 'f', -1, 64) + "M"
+	case abs >= 1e4:
+		return strconv.FormatFloat(v/1e3, 'f', -1, 64) + "k"
+	}
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
+
+// formatTimeTick formats a Unix-seconds tick with a precision that suits
+// the visible span.
+func formatTimeTick(v, span float64) string {
+	t := time.Unix(0, int64(v*1e9)).UTC()
+	switch {
+	case span > 2*365*24*3600:
+		return t.Format("2006-01")
+	case span > 2*24*3600:
+		return t.Format("2006-01-02")
+	case span > 2*3600:
+		return t.Format("01-02 15:04")
+	}
+	return t.Format("15:04:05")
+}
+
+// chartObjects converts shapes to canvas objects positioned in chart space.
+func chartObjects(shapes []chartShape) []fyne.CanvasObject {
+	objs := make([]fyne.CanvasObject, 0, len(shapes))
+	for _, s := range shapes {
+		switch s.kind {
+		case shapeLine:
+			l := canvas.NewLine(s.color)
+			l.StrokeWidth = s.stroke
+			l.Position1 = fyne.NewPos(s.x1, s.y1)
+			l.Position2 = fyne.NewPos(s.x2, s.y2)
+			objs = append(objs, l)
+		case shapeRect:
+			r := canvas.NewRectangle(s.color)
+			r.Move(fyne.NewPos(s.x1, s.y1))
+			r.Resize(fyne.NewSize(s.x2-s.x1, s.y2-s.y1))
+			objs = append(objs, r)
+		case shapeCircle:
+			c := canvas.NewCircle(s.color)
+			c.Position1 = fyne.NewPos(s.x1-s.x2, s.y1-s.x2)
+			c.Position2 = fyne.NewPos(s.x1+s.x2, s.y1+s.x2)
+			objs = append(objs, c)
+		case shapeText:
+			t := canvas.NewText(s.text, s.color)
+			t.TextSize = s.size
+			size := fyne.MeasureText(s.text, s.size, t.TextStyle)
+			x := s.x1
+			switch s.align {
+			case fyne.TextAlignCenter:
+				x -= size.Width / 2
+			case fyne.TextAlignTrailing:
+				x -= size.Width
+			}
+			t.Move(fyne.NewPos(x, s.y1))
+			t.Resize(size)
+			objs = append(objs, t)
+		}
+	}
+	return objs
+}
+
+// chartSVG renders shapes as a standalone SVG document.
+func chartSVG(shapes []chartShape, w, h float32, bg color.Color) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="sans-serif">`+"\n", w, h, w, h)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", svgColor(bg))
+	for _, s := range shapes {
+		switch s.kind {
+		case shapeLine:
+			fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="%.1f"/>`+"\n",
+				s.x1, s.y1, s.x2, s.y2, svgColor(s.color), s.stroke)
+		case shapeRect:
+			fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n",
+				s.x1, s.y1, s.x2-s.x1, s.y2-s.y1, svgColor(s.color))
+		case shapeCircle:
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s"/>`+"\n",
+				s.x1, s.y1, s.x2, svgColor(s.color))
+		case shapeText:
+			anchor := "start"
+			switch s.align {
+			case fyne.TextAlignCenter:
+				anchor = "middle"
+			case fyne.TextAlignTrailing:
+				anchor = "end"
+			}
+			// Shapes anchor text at its top; SVG anchors at the baseline.
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="%.0f" text-anchor="%s" fill="%s">%s</text>`+"\n",
+				s.x1, s.y1+s.size, s.size, anchor, svgColor(s.color), html.EscapeString(s.text))
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+func svgColor(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if n.A == 0xFF {
+		return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+	}
+	return fmt.Sprintf("rgba(%d,%d,%d,%.3f)", n.R, n.G, n.B, float64(n.A)/255)
+}
+
+// chartPlot draws chart data, re-laying out the shapes on resize.
+type chartPlot struct {
+	widget.BaseWidget
+	data chartData
+}
+
+func newChartPlot() *chartPlot {
+	p := &chartPlot{}
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+func (p *chartPlot) CreateRenderer() fyne.WidgetRenderer {
+	r := &chartPlotRenderer{plot: p}
+	r.Layout(p.Size())
+	return r
+}
+
+func currentChartColors() chartColors {
+	return chartColors{
+		fg:   theme.Color(theme.ColorNameForeground),
+		grid: theme.Color(theme.ColorNameSeparator),
+	}
+}
+
+type chartPlotRenderer struct {
+	plot    *chartPlot
+	objects []fyne.CanvasObject
+}
+
+func (r *chartPlotRenderer) Layout(size fyne.Size) {
+	r.objects = chartObjects(layoutChart(r.plot.data, size.Width, size.Height, currentChartColors()))
+}
+
+func (r *chartPlotRenderer) MinSize() fyne.Size { return fyne.NewSize(240, 160) }
+
+func (r *chartPlotRenderer) Refresh() {
+	r.Layout(r.plot.Size())
+	canvas.Refresh(r.plot)
+}
+
+func (r *chartPlotRenderer) Objects() []fyne.CanvasObject { return r.objects }
+func (r *chartPlotRenderer) Destroy()                     {}
+
+// Chart plots the current result set as a line, bar, scatter or histogram
+// chart. It reads the rows already fetched and never runs a query.
+type Chart struct {
+	plot       *chartPlot
+	kindSelect *widget.Select
+	xSelect    *widget.Select
+	aggSelect  *widget.Select
+	seriesBtn  *widget.Button
+	exportBtn  *widget.Button
+	updating   bool // set while controls are synced from the spec
+	columns    []string
+	types      []string
+	rows       [][]string
+	spec       chartSpec
+
+	// OnExport is called with "png" or "svg" when an export is requested.
+	OnExport func(format string)
+
+	Container fyne.CanvasObject
+}
+
+func NewChart() *Chart {
+	c := &Chart{plot: newChartPlot()}
+
+	kinds := make([]string, len(chartKinds))
+	for i, k := range chartKinds {
+		kinds[i] = string(k)
+	}
+	c.kindSelect = widget.NewSelect(kinds, func(s string) {
+		c.spec.Kind = ChartKind(s)
+		c.redraw()
+	})
+	c.xSelect = widget.NewSelect(nil, func(s string) {
+		for i, col := range c.columns {
+			if col == s {
+				c.spec.X = i
+			}
+		}
+		c.redraw()
+	})
+	aggs := make([]string, len(chartAggs))
+	for i, a := range chartAggs {
+		aggs[i] = string(a)
+	}
+	c.aggSelect = widget.NewSelect(aggs, func(s string) {
+		c.spec.Agg = ChartAgg(s)
+		c.redraw()
+	})
+	c.seriesBtn = widget.NewButton("Series", c.showSeriesMenu)
+	c.exportBtn = widget.NewButtonWithIcon("Export", theme.DocumentSaveIcon(), func() {
+		showMenuBelow(fyne.NewMenu("",
+			fyne.NewMenuItem("PNG Image…", func() { c.export("png") }),
+			fyne.NewMenuItem("SVG Image…", func() { c.export("svg") }),
+		), c.exportBtn)
+	})
+
+	toolbar := container.NewHBox(
+		widget.NewLabel("Chart:"), c.kindSelect,
+		widget.NewLabel("X:"), c.xSelect,
+		c.seriesBtn,
+		widget.NewLabel("Aggregate:"), c.aggSelect,
+		c.exportBtn,
+	)
+	c.Container = container.NewBorder(toolbar, nil, nil, nil, c.plot)
+	c.setEnabled(false)
+	return c
+}
+
+func (c *Chart) setEnabled(on bool) {
+	for _, w := range []fyne.Disableable{c.kindSelect, c.xSelect, c.aggSelect, c.seriesBtn, c.exportBtn} {
+		if on {
+			w.Enable()
+		} else {
+			w.Disable()
+		}
+	}
+}
+
+// SetData replaces the charted result set and picks a default chart for it.
+func (c *Chart) SetData(columns, types []string, rows [][]string) {
+	fyne.Do(func() {
+		c.columns, c.types, c.rows = columns, types, rows
+		c.spec = defaultChartSpec(columns, types)
+		c.updating = true
+		c.xSelect.Options = columns
+		if c.spec.X < len(columns) {
+			c.xSelect.SetSelected(columns[c.spec.X])
+		} else {
+			c.xSelect.ClearSelected()
+		}
+		c.kindSelect.SetSelected(string(c.spec.Kind))
+		c.aggSelect.SetSelected(string(c.spec.Agg))
+		c.updating = false
+		c.setEnabled(len(columns) > 0)
+		c.redraw()
+	})
+}
+
+func (c *Chart) showSeriesMenu() {
+	var items []*fyne.MenuItem
+	for i, col := range c.columns {
+		if i >= len(c.types) || !isNumericType(c.types[i]) {
+			continue
+		}
+		item := fyne.NewMenuItem(col, func() {
+			c.toggleSeries(i)
+		})
+		item.Checked = indexOf(c.spec.Y, i) >= 0
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		items = append(items, &fyne.MenuItem{Label: "No numeric columns", Disabled: true})
+	}
+	showMenuBelow(fyne.NewMenu("", items...), c.seriesBtn)
+}
+
+func (c *Chart) toggleSeries(col int) {
+	c.spec.Y = toggleIndex(c.spec.Y, col)
+	c.redraw()
+}
+
+func (c *Chart) redraw() {
+	if c.updating {
+		return
+	}
+	c.plot.data = buildChartData(c.columns, c.types, c.rows, c.spec)
+	c.plot.Refresh()
+}
+
+func (c *Chart) export(format string) {
+	if c.OnExport != nil {
+		c.OnExport(format)
+	}
+}
+
+// exportSize is the image size used for PNG and SVG exports: the on-screen
+// size, or a default when the chart has not been shown yet.
+func (c *Chart) exportSize() fyne.Size {
+	size := c.plot.Size()
+	if size.Width < 240 || size.Height < 160 {
+		return fyne.NewSize(960, 540)
+	}
+	return size
+}
+
+// SVG renders the current chart as an SVG document.
+func (c *Chart) SVG() []byte {
+	size := c.exportSize()
+	shapes := layoutChart(c.plot.data, size.Width, size.Height, currentChartColors())
+	return []byte(chartSVG(shapes, size.Width, size.Height, theme.Color(theme.ColorNameBackground)))
+}
+
+// PNG renders the current chart as a PNG image.
+func (c *Chart) PNG() ([]byte, error) {
+	size := c.exportSize()
+	shapes := layoutChart(c.plot.data, size.Width, size.Height, currentChartColors())
+	bg := canvas.NewRectangle(theme.Color(theme.ColorNameBackground))
+	bg.Resize(size)
+	content := container.NewWithoutLayout(append([]fyne.CanvasObject{bg}, chartObjects(shapes)...)...)
+	content.Resize(size)
+
+	img := software.Render(content, fyne.CurrentApp().Settings().Theme())
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
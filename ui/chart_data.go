@@ -0,0 +1,321 @@
+package ui
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type ChartKind string
+
+const (
+	ChartLine      ChartKind = "Line"
+	ChartBar       ChartKind = "Bar"
+	ChartScatter   ChartKind = "Scatter"
+	ChartHistogram ChartKind = "Histogram"
+)
+
+var chartKinds = []ChartKind{ChartLine, ChartBar, ChartScatter, ChartHistogram}
+
+type ChartAgg string
+
+const (
+	AggNone  ChartAgg = "None"
+	AggSum   ChartAgg = "Sum"
+	AggAvg   ChartAgg = "Avg"
+	AggCount ChartAgg = "Count"
+	AggMin   ChartAgg = "Min"
+	AggMax   ChartAgg = "Max"
+)
+
+var chartAggs = []ChartAgg{AggNone, AggSum, AggAvg, AggCount, AggMin, AggMax}
+
+// maxChartSeries caps how many numeric columns are plotted by default.
+const maxChartSeries = 5
+
+// chartSpec selects what to plot from a result set.
+type chartSpec struct {
+	Kind ChartKind
+	X    int   // x axis column; ignored for histograms
+	Y    []int // series columns; a histogram uses the first
+	Agg  ChartAgg
+	Bins int // histogram bin count; 0 picks one from the row count
+}
+
+// chartAxisKind describes how x values are placed on the axis.
+type chartAxisKind int
+
+const (
+	axisCategory chartAxisKind = iota // evenly spaced labels, in first-seen order
+	axisNumber
+	axisTime // x values are Unix seconds
+)
+
+type chartSeries struct {
+	Name   string
+	Points []chartPoint
+}
+
+type chartPoint struct {
+	X, Y  float64
+	Label string // category label, for category axes
+}
+
+// chartData is the plottable form of a result set.
+type chartData struct {
+	Kind       ChartKind
+	XKind      chartAxisKind
+	XName      string
+	Categories []string // for category axes, indexed by point X
+	Series     []chartSeries
+	BinWidth   float64 // histogram bin width in x units
+}
+
+func isNumericType(typ string) bool {
+	switch typ {
+	case "INTEGER", "INT64", "FLOAT", "FLOAT64", "NUMERIC", "BIGNUMERIC":
+		return true
+	}
+	return false
+}
+
+func isTimeType(typ string) bool {
+	return typ == "DATE" || typ == "TIMESTAMP" || typ == "DATETIME"
+}
+
+// defaultChartSpec picks a chart for a result shape: a time column with
+// numeric columns becomes a line chart, a label column with numbers a bar
+// chart, two numeric columns a scatter plot and a single one a histogram.
+func defaultChartSpec(columns, types []string) chartSpec {
+	var timeCols, numCols, labelCols []int
+	for i := range columns {
+		typ := ""
+		if i < len(types) {
+			typ = types[i]
+		}
+		switch {
+		case isTimeType(typ):
+			timeCols = append(timeCols, i)
+		case isNumericType(typ):
+			numCols = append(numCols, i)
+		case typ == "STRING" || typ == "BOOLEAN" || typ == "BOOL":
+			labelCols = append(labelCols, i)
+		}
+	}
+	if len(numCols) > maxChartSeries {
+		numCols = numCols[:maxChartSeries]
+	}
+	switch {
+	case len(timeCols) > 0 && len(numCols) > 0:
+		return chartSpec{Kind: ChartLine, X: timeCols[0], Y: numCols, Agg: AggSum}
+	case len(labelCols) > 0 && len(numCols) > 0:
+		return chartSpec{Kind: ChartBar, X: labelCols[0], Y: numCols[:1], Agg: AggSum}
+	case len(numCols) >= 2:
+		return chartSpec{Kind: ChartScatter, X: numCols[0], Y: numCols[1:2], Agg: AggNone}
+	case len(numCols) == 1:
+		return chartSpec{Kind: ChartHistogram, X: numCols[0], Y: numCols, Agg: AggCount}
+	case len(columns) > 0:
+		return chartSpec{Kind: ChartBar, X: 0, Y: nil, Agg: AggCount}
+	}
+	return chartSpec{Kind: ChartBar, Agg: AggCount}
+}
+
+// parseTimeValue parses a DATE, DATETIME or TIMESTAMP display value.
+func parseTimeValue(s string) (time.Time, bool) {
+	for _, layout := range []string{
+		"2006-01-02 15:04:05.999999999 -0700 MST", // TIMESTAMP
+		"2006-01-02T15:04:05.999999999",           // DATETIME
+		"2006-01-02",                              // DATE
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// buildChartData turns rows into plottable series according to spec.
+// Rows whose x or y values do not parse are skipped.
+func buildChartData(columns, types []string, rows [][]string, spec chartSpec) chartData {
+	typeOf := func(col int) string {
+		if col < len(types) {
+			return types[col]
+		}
+		return ""
+	}
+	if spec.Kind == ChartHistogram {
+		return buildHistogram(columns, rows, spec)
+	}
+
+	data := chartData{Kind: spec.Kind}
+	if spec.X < 0 || spec.X >= len(columns) {
+		return data
+	}
+	data.XName = columns[spec.X]
+	xType := typeOf(spec.X)
+	switch {
+	case isTimeType(xType):
+		data.XKind = axisTime
+	case isNumericType(xType) && spec.Kind != ChartBar:
+		data.XKind = axisNumber
+	default:
+		data.XKind = axisCategory
+	}
+
+	// Map each row to an x position.
+	catIndex := make(map[string]int)
+	xOf := func(v string) (float64, bool) {
+		switch data.XKind {
+		case axisTime:
+			t, ok := parseTimeValue(v)
+			return float64(t.UnixNano()) / 1e9, ok
+		case axisNumber:
+			f, err := strconv.ParseFloat(v, 64)
+			return f, err == nil
+		}
+		i, ok := catIndex[v]
+		if !ok {
+			i = len(data.Categories)
+			catIndex[v] = i
+			data.Categories = append(data.Categories, v)
+		}
+		return float64(i), true
+	}
+
+	ys := spec.Y
+	agg := spec.Agg
+	if len(ys) == 0 {
+		// Nothing to measure: count rows per x.
+		ys = []int{-1}
+		agg = AggCount
+	}
+	for _, yc := range ys {
+		s := chartSeries{Name: "count"}
+		if yc >= 0 && yc < len(columns) {
+			s.Name = columns[yc]
+		}
+		groups := make(map[float64][]float64)
+		var order []float64
+		for _, row := range rows {
+			x, ok := xOf(cellAt(row, spec.X))
+			if !ok {
+				continue
+			}
+			y := 1.0
+			if yc >= 0 {
+				f, err := strconv.ParseFloat(cellAt(row, yc), 64)
+				if err != nil {
+					if agg != AggCount {
+						continue
+					}
+					f = 1
+				}
+				y = f
+			}
+			if agg == AggNone {
+				s.Points = append(s.Points, chartPoint{X: x, Y: y})
+				continue
+			}
+			if _, seen := groups[x]; !seen {
+				order = append(order, x)
+			}
+			groups[x] = append(groups[x], y)
+		}
+		for _, x := range order {
+			s.Points = append(s.Points, chartPoint{X: x, Y: aggregate(groups[x], agg)})
+		}
+		if data.XKind == axisCategory {
+			for i := range s.Points {
+				s.Points[i].Label = data.Categories[int(s.Points[i].X)]
+			}
+		} else {
+			sort.SliceStable(s.Points, func(i, j int) bool { return s.Points[i].X < s.Points[j].X })
+		}
+		data.Series = append(data.Series, s)
+	}
+	return data
+}
+
+func aggregate(vals []float64, agg ChartAgg) float64 {
+	switch agg {
+	case AggCount:
+		return float64(len(vals))
+	case AggAvg:
+		return sumFloats(vals) / float64(len(vals))
+	case AggMin:
+		m := vals[0]
+		for _, v := range vals[1:] {
+			m = math.Min(m, v)
+		}
+		return m
+	case AggMax:
+		m := vals[0]
+		for _, v := range vals[1:] {
+			m = math.Max(m, v)
+		}
+		return m
+	}
+	return sumFloats(vals)
+}
+
+func sumFloats(vals []float64) float64 {
+	var s float64
+	for _, v := range vals {
+		s += v
+	}
+	return s
+}
+
+// buildHistogram counts the values of the first Y column (or X when no Y
+// is set) into equal-width bins.
+func buildHistogram(columns []string, rows [][]string, spec chartSpec) chartData {
+	col := spec.X
+	if len(spec.Y) > 0 {
+		col = spec.Y[0]
+	}
+	data := chartData{Kind: ChartHistogram, XKind: axisNumber}
+	if col < 0 || col >= len(columns) {
+		return data
+	}
+	data.XName = columns[col]
+
+	var vals []float64
+	for _, row := range rows {
+		if f, err := strconv.ParseFloat(cellAt(row, col), 64); err == nil {
+			vals = append(vals, f)
+		}
+	}
+	if len(vals) == 0 {
+		return data
+	}
+	lo, hi := vals[0], vals[0]
+	for _, v := range vals {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	bins := spec.Bins
+	if bins <= 0 {
+		// Sturges' rule, within reasonable bounds.
+		bins = int(math.Ceil(math.Log2(float64(len(vals))))) + 1
+		bins = max(5, min(bins, 50))
+	}
+	if hi == lo {
+		hi = lo + 1
+	}
+	width := (hi - lo) / float64(bins)
+	counts := make([]float64, bins)
+	for _, v := range vals {
+		i := int((v - lo) / width)
+		if i >= bins {
+			i = bins - 1
+		}
+		counts[i]++
+	}
+	s := chartSeries{Name: "count"}
+	for i, c := range counts {
+		s.Points = append(s.Points, chartPoint{X: lo + (float64(i)+0.5)*width, Y: c})
+	}
+	data.Series = []chartSeries{s}
+	data.BinWidth = width
+	return data
+}
@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"image/color"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDefaultChartSpec(t *testing.T) {
+	tests := []struct {
+		name  string
+		types []string
+		want  chartSpec
+	}{
+		{"time series", []string{"STRING", "DATE", "INTEGER", "FLOAT"},
+			chartSpec{Kind: ChartLine, X: 1, Y: []int{2, 3}, Agg: AggSum}},
+		{"labels", []string{"STRING", "INTEGER"},
+			chartSpec{Kind: ChartBar, X: 0, Y: []int{1}, Agg: AggSum}},
+		{"two numbers", []string{"FLOAT", "INTEGER"},
+			chartSpec{Kind: ChartScatter, X: 0, Y: []int{1}, Agg: AggNone}},
+		{"one number", []string{"FLOAT"},
+			chartSpec{Kind: ChartHistogram, X: 0, Y: []int{0}, Agg: AggCount}},
+		{"no numbers", []string{"STRING", "STRING"},
+			chartSpec{Kind: ChartBar, X: 0, Agg: AggCount}},
+	}
+	for _, tt := range tests {
+		cols := make([]string, len(tt.types))
+		if got := defaultChartSpec(cols, tt.types); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuildChartData_Aggregates(t *testing.T) {
+	cols := []string{"day", "region", "sales"}
+	types := []string{"DATE", "STRING", "INTEGER"}
+	rows := [][]string{
+		{"2024-01-02", "eu", "5"},
+		{"2024-01-01", "us", "3"},
+		{"2024-01-02", "us", "NULL"},
+		{"2024-01-01", "eu", "4"},
+	}
+
+	d := buildChartData(cols, types, rows, chartSpec{Kind: ChartLine, X: 0, Y: []int{2}, Agg: AggSum})
+	if d.XKind != axisTime || len(d.Series) != 1 {
+		t.Fatalf("got %+v", d)
+	}
+	var ys []float64
+	for _, p := range d.Series[0].Points {
+		ys = append(ys, p.Y)
+	}
+	if !reflect.DeepEqual(ys, []float64{7, 5}) {
+		t.Errorf("sums by day = %v, want [7 5] in date order", ys)
+	}
+
+	d = buildChartData(cols, types, rows, chartSpec{Kind: ChartBar, X: 1, Y: []int{2}, Agg: AggCount})
+	if !reflect.DeepEqual(d.Categories, []string{"eu", "us"}) {
+		t.Errorf("categories = %v, want first-seen order", d.Categories)
+	}
+	if p := d.Series[0].Points; len(p) != 2 || p[0].Y != 2 || p[1].Y != 2 || p[1].Label != "us" {
+		t.Errorf("counts = %+v, want NULLs counted", p)
+	}
+}
+
+func TestBuildHistogram(t *testing.T) {
+	var rows [][]string
+	for _, v := range []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "10", "NULL"} {
+		rows = append(rows, []string{v})
+	}
+	d := buildChartData([]string{"v"}, []string{"INTEGER"}, rows, chartSpec{Kind: ChartHistogram, Y: []int{0}, Bins: 5})
+	if d.BinWidth != 2 {
+		t.Errorf("BinWidth = %v, want 2", d.BinWidth)
+	}
+	var counts []float64
+	for _, p := range d.Series[0].Points {
+		counts = append(counts, p.Y)
+	}
+	if !reflect.DeepEqual(counts, []float64{2, 2, 2, 2, 2}) {
+		t.Errorf("counts = %v, want max value in the last bin", counts)
+	}
+}
+
+func TestNiceTicks(t *testing.T) {
+	if got := niceTicks(0, 97, 5); !reflect.DeepEqual(got, []float64{0, 20, 40, 60, 80, 100}) {
+		t.Errorf("niceTicks(0, 97) = %v", got)
+	}
+}
+
+func TestChartSVG(t *testing.T) {
+	d := buildChartData([]string{"name", "n"}, []string{"STRING", "INTEGER"},
+		[][]string{{"a<b", "1"}, {"c", "3"}}, chartSpec{Kind: ChartBar, X: 0, Y: []int{1}, Agg: AggSum})
+	shapes := layoutChart(d, 400, 300, chartColors{fg: color.Black, grid: color.Gray{Y: 200}})
+	svg := chartSVG(shapes, 400, 300, color.White)
+
+	if !strings.HasPrefix(svg, "<svg ") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Fatalf("not an SVG document:\n%s", svg)
+	}
+	if strings.Count(svg, `fill="#4285f4"`) != 3 { // two bars and the legend swatch
+		t.Errorf("expected two bars and a legend swatch:\n%s", svg)
+	}
+	if !strings.Contains(svg, "a&lt;b") {
+		t.Error("category labels should be escaped")
+	}
+}
+
+func TestChart_SetDataAndPNG(t *testing.T) {
+	c := NewChart()
+	c.SetData([]string{"day", "n"}, []string{"DATE", "INTEGER"},
+		[][]string{{"2024-01-01", "1"}, {"2024-01-02", "4"}})
+	if c.kindSelect.Selected != string(ChartLine) || c.xSelect.Selected != "day" {
+		t.Errorf("controls = %q / %q, want the default time series chart", c.kindSelect.Selected, c.xSelect.Selected)
+	}
+	png, err := c.PNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(png), "\x89PNG") {
+		t.Error("PNG export did not produce a PNG image")
+	}
+}
@@ -0,0 +1,301 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Diff compares a pinned result set with a later run of the same tab or
+// with another tab's result. Rows are aligned on key columns, and added,
+// removed and changed rows and cells are highlighted.
+type Diff struct {
+	baseLabel     *widget.Label
+	compareSelect *widget.Select
+	keysBtn       *widget.Button
+	changesOnly   *widget.Check
+	unpinBtn      *widget.Button
+	summary       *widget.Label
+	table         *widget.Table
+
+	baseline *DiffSource
+	sources  []DiffSource
+	current  *DiffSource
+	keys     []string
+	diff     resultDiff
+	rows     []int // indices into diff.Rows that are shown
+
+	Container fyne.CanvasObject
+}
+
+func NewDiff() *Diff {
+	d := &Diff{}
+
+	d.baseLabel = widget.NewLabel("")
+	d.compareSelect = widget.NewSelect(nil, func(label string) {
+		d.current = nil
+		for i := range d.sources {
+			if d.sources[i].Label == label {
+				d.current = &d.sources[i]
+			}
+		}
+		d.update()
+	})
+	d.compareSelect.PlaceHolder = "Pick a result to compare"
+	d.keysBtn = widget.NewButton("Key Columns", d.showKeysMenu)
+	d.changesOnly = widget.NewCheck("Changes only", func(bool) { d.update() })
+	d.unpinBtn = widget.NewButtonWithIcon("Unpin", theme.ContentClearIcon(), func() {
+		d.baseline = nil
+		d.keys = nil
+		d.update()
+	})
+	d.summary = widget.NewLabel("")
+
+	d.table = widget.NewTableWithHeaders(
+		func() (int, int) {
+			if len(d.diff.Columns) == 0 {
+				return 0, 0
+			}
+			return len(d.rows), len(d.diff.Columns) + 1
+		},
+		func() fyne.CanvasObject {
+			bg := canvas.NewRectangle(color.Transparent)
+			txt := canvas.NewText("", color.White)
+			return container.NewStack(bg, container.NewPadded(txt))
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			stack := obj.(*fyne.Container)
+			bg := stack.Objects[0].(*canvas.Rectangle)
+			txt := stack.Objects[1].(*fyne.Container).Objects[0].(*canvas.Text)
+			txt.TextSize = theme.Size(theme.SizeNameText)
+			txt.Color = theme.Color(theme.ColorNameForeground)
+			text, fill := d.cell(id)
+			txt.Text = text
+			bg.FillColor = fill
+			bg.Refresh()
+			txt.Refresh()
+		},
+	)
+	d.table.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewLabel("")
+	}
+	d.table.UpdateHeader = func(id widget.TableCellID, template fyne.CanvasObject) {
+		l := template.(*widget.Label)
+		l.TextStyle = fyne.TextStyle{Bold: true}
+		switch {
+		case id.Row < 0 && id.Col == 0:
+			l.SetText("")
+		case id.Row < 0 && id.Col > 0 && id.Col <= len(d.diff.Columns):
+			name := d.diff.Columns[id.Col-1]
+			switch d.diff.ColStatus[id.Col-1] {
+			case diffAdded:
+				name += " (added)"
+			case diffRemoved:
+				name += " (removed)"
+			}
+			l.SetText(name)
+		case id.Col < 0 && id.Row >= 0:
+			l.SetText(fmt.Sprintf("%d", id.Row+1))
+		default:
+			l.SetText("")
+		}
+	}
+	d.table.SetColumnWidth(0, 32)
+
+	top := container.NewBorder(nil, nil,
+		container.NewHBox(widget.NewLabel("Pinned:"), d.baseLabel, d.unpinBtn, widget.NewLabel("vs")),
+		container.NewHBox(d.keysBtn, d.changesOnly),
+		d.compareSelect)
+	d.Container = container.NewBorder(top, d.summary, nil, nil, d.table)
+	d.update()
+	return d
+}
+
+// SetBaseline pins src as the left side of the comparison.
+func (d *Diff) SetBaseline(src DiffSource) {
+	d.baseline = &src
+	d.keys = nil
+	d.current = nil
+	d.compareSelect.ClearSelected()
+	d.update()
+}
+
+// SetSources lists the result sets that can be compared with the pinned
+// one, most recent first. The selection is kept when still listed.
+// Otherwise the most recent source other than the baseline is picked.
+func (d *Diff) SetSources(sources []DiffSource) {
+	d.sources = sources
+	labels := make([]string, len(sources))
+	for i, s := range sources {
+		labels[i] = s.Label
+	}
+	d.compareSelect.Options = labels
+
+	selected := d.compareSelect.Selected
+	if indexOfString(labels, selected) < 0 {
+		selected = ""
+		for _, s := range sources {
+			if d.baseline == nil || s.Label != d.baseline.Label {
+				selected = s.Label
+				break
+			}
+		}
+	}
+	if selected == "" {
+		d.compareSelect.ClearSelected()
+		d.current = nil
+		d.update()
+		return
+	}
+	// Set the field directly: the label may be unchanged while the source
+	// behind it is new, and SetSelected would not fire OnChanged then.
+	d.compareSelect.Selected = selected
+	d.compareSelect.Refresh()
+	d.compareSelect.OnChanged(selected)
+}
+
+func (d *Diff) showKeysMenu() {
+	if d.baseline == nil || d.current == nil {
+		return
+	}
+	var items []*fyne.MenuItem
+	for _, col := range commonColumns(*d.baseline, *d.current) {
+		item := fyne.NewMenuItem(col, func() {
+			if i := indexOfString(d.keys, col); i >= 0 {
+				d.keys = append(d.keys[:i:i], d.keys[i+1:]...)
+			} else {
+				d.keys = append(d.keys, col)
+			}
+			d.update()
+		})
+		item.Checked = indexOfString(d.keys, col) >= 0
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		items = append(items, &fyne.MenuItem{Label: "No columns in common", Disabled: true})
+	}
+	showMenuBelow(fyne.NewMenu("", items...), d.keysBtn)
+}
+
+// update recomputes the diff and refreshes the view.
+func (d *Diff) update() {
+	d.diff = resultDiff{}
+	d.rows = nil
+	switch {
+	case d.baseline == nil:
+		d.baseLabel.SetText("nothing")
+		d.unpinBtn.Disable()
+		d.keysBtn.Disable()
+		d.summary.SetText("Pin a result from the Results tab, then run the query again or switch tabs to compare.")
+	case d.current == nil:
+		d.baseLabel.SetText(d.baseline.Label)
+		d.unpinBtn.Enable()
+		d.keysBtn.Disable()
+		d.summary.SetText("Pick a result to compare with the pinned one.")
+	default:
+		d.baseLabel.SetText(d.baseline.Label)
+		d.unpinBtn.Enable()
+		d.keysBtn.Enable()
+		d.diff = diffResults(*d.baseline, *d.current, d.keys)
+		for i, r := range d.diff.Rows {
+			if !d.changesOnly.Checked || r.Status != diffSame {
+				d.rows = append(d.rows, i)
+			}
+		}
+		d.summary.SetText(d.describe())
+	}
+	for i := range d.diff.Columns {
+		d.table.SetColumnWidth(i+1, 140)
+	}
+	d.table.Refresh()
+}
+
+// describe summarizes the diff, e.g. "2 added · 1 removed · 3 changed ·
+// 40 unchanged · matched on id".
+func (d *Diff) describe() string {
+	parts := []string{
+		fmt.Sprintf("%d added", d.diff.Added),
+		fmt.Sprintf("%d removed", d.diff.Removed),
+		fmt.Sprintf("%d changed", d.diff.Changed),
+		fmt.Sprintf("%d unchanged", d.diff.Same),
+	}
+	var added, removed []string
+	for i, st := range d.diff.ColStatus {
+		switch st {
+		case diffAdded:
+			added = append(added, d.diff.Columns[i])
+		case diffRemoved:
+			removed = append(removed, d.diff.Columns[i])
+		}
+	}
+	if len(added) > 0 {
+		parts = append(parts, "new columns: "+strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		parts = append(parts, "dropped columns: "+strings.Join(removed, ", "))
+	}
+	if len(d.keys) > 0 {
+		parts = append(parts, "matched on "+strings.Join(d.keys, ", "))
+	} else {
+		parts = append(parts, "matched on whole rows")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// cell returns the text and background of a diff grid cell. Column 0 marks
+// the row status.
+func (d *Diff) cell(id widget.TableCellID) (string, color.Color) {
+	if id.Row >= len(d.rows) {
+		return "", color.Transparent
+	}
+	r := d.diff.Rows[d.rows[id.Row]]
+	rowFill := color.Color(color.Transparent)
+	marker := ""
+	switch r.Status {
+	case diffAdded:
+		rowFill, marker = diffColor(theme.ColorNameSuccess), "+"
+	case diffRemoved:
+		rowFill, marker = diffColor(theme.ColorNameError), "−"
+	case diffChanged:
+		marker = "~"
+	}
+	if id.Col == 0 {
+		return marker, rowFill
+	}
+	col := id.Col - 1
+	switch r.Status {
+	case diffAdded:
+		return r.Cur[col], rowFill
+	case diffRemoved:
+		return r.Base[col], rowFill
+	}
+	if r.Changed[col] {
+		return r.Base[col] + " → " + r.Cur[col], diffColor(theme.ColorNameWarning)
+	}
+	if d.diff.ColStatus[col] == diffAdded {
+		return r.Cur[col], color.Transparent
+	}
+	return r.Base[col], color.Transparent
+}
+
+// diffColor returns a translucent version of a theme color for highlights.
+func diffColor(name fyne.ThemeColorName) color.Color {
+	c := color.NRGBAModel.Convert(theme.Color(name)).(color.NRGBA)
+	c.A = 0x50
+	return c
+}
+
+func indexOfString(s []string, v string) int {
+	for i, x := range s {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,177 @@
+package ui
+
+import "strings"
+
+// DiffSource is a result set that can be compared with another.
+type DiffSource struct {
+	Label   string // e.g. "Query 1 · 14:03:22"
+	Columns []string
+	Types   []string
+	Rows    [][]string
+}
+
+type diffStatus int
+
+const (
+	diffSame diffStatus = iota
+	diffChanged
+	diffAdded
+	diffRemoved
+)
+
+// diffRow is one aligned row. Base and Cur hold values by diff column and
+// are nil when the row is missing from that side.
+type diffRow struct {
+	Status  diffStatus
+	Base    []string
+	Cur     []string
+	Changed []bool // per diff column, for changed rows
+}
+
+// resultDiff is the comparison of a baseline result set with a current one.
+type resultDiff struct {
+	Columns   []string
+	ColStatus []diffStatus // diffAdded or diffRemoved for columns on one side only
+	Rows      []diffRow
+
+	Same, Changed, Added, Removed int
+}
+
+// diffResults aligns the rows of base and cur and compares them. Rows are
+// matched on the key columns, which must exist on both sides; without keys
+// a row matches an identical row. Rows with the same key are paired in
+// order of appearance. The output follows the current rows, with removed
+// rows placed near their baseline position.
+func diffResults(base, cur DiffSource, keys []string) resultDiff {
+	var d resultDiff
+	baseIdx := make(map[string]int)
+	curIdx := make(map[string]int)
+	for i, c := range base.Columns {
+		baseIdx[c] = i
+	}
+	for i, c := range cur.Columns {
+		curIdx[c] = i
+	}
+
+	// Columns: baseline order, then columns only in the current result.
+	var common []string
+	for _, c := range base.Columns {
+		d.Columns = append(d.Columns, c)
+		if _, ok := curIdx[c]; ok {
+			d.ColStatus = append(d.ColStatus, diffSame)
+			common = append(common, c)
+		} else {
+			d.ColStatus = append(d.ColStatus, diffRemoved)
+		}
+	}
+	for _, c := range cur.Columns {
+		if _, ok := baseIdx[c]; !ok {
+			d.Columns = append(d.Columns, c)
+			d.ColStatus = append(d.ColStatus, diffAdded)
+		}
+	}
+
+	var keyCols []string
+	for _, k := range keys {
+		_, inBase := baseIdx[k]
+		_, inCur := curIdx[k]
+		if inBase && inCur {
+			keyCols = append(keyCols, k)
+		}
+	}
+	if len(keyCols) == 0 {
+		keyCols = common
+	}
+
+	align := func(row []string, idx map[string]int) []string {
+		out := make([]string, len(d.Columns))
+		for i, c := range d.Columns {
+			if j, ok := idx[c]; ok {
+				out[i] = cellAt(row, j)
+			}
+		}
+		return out
+	}
+	keyOf := func(row []string, idx map[string]int) string {
+		parts := make([]string, len(keyCols))
+		for i, k := range keyCols {
+			parts[i] = cellAt(row, idx[k])
+		}
+		return strings.Join(parts, pivotKeySep)
+	}
+
+	// Queue baseline rows by key so duplicates pair up in order, and match
+	// every current row before placing any removed row.
+	pending := make(map[string][]int)
+	for i, row := range base.Rows {
+		k := keyOf(row, baseIdx)
+		pending[k] = append(pending[k], i)
+	}
+	matched := make([]bool, len(base.Rows))
+	baseOf := make([]int, len(cur.Rows))
+	for ci, row := range cur.Rows {
+		k := keyOf(row, curIdx)
+		q := pending[k]
+		if len(q) == 0 {
+			baseOf[ci] = -1
+			continue
+		}
+		baseOf[ci] = q[0]
+		pending[k] = q[1:]
+		matched[q[0]] = true
+	}
+
+	nextBase := 0
+	emitRemovedBefore := func(limit int) {
+		for ; nextBase < limit; nextBase++ {
+			if !matched[nextBase] {
+				d.Rows = append(d.Rows, diffRow{Status: diffRemoved, Base: align(base.Rows[nextBase], baseIdx)})
+				d.Removed++
+			}
+		}
+	}
+
+	for ci, row := range cur.Rows {
+		cr := align(row, curIdx)
+		bi := baseOf[ci]
+		if bi < 0 {
+			d.Rows = append(d.Rows, diffRow{Status: diffAdded, Cur: cr})
+			d.Added++
+			continue
+		}
+		emitRemovedBefore(bi)
+
+		br := align(base.Rows[bi], baseIdx)
+		dr := diffRow{Status: diffSame, Base: br, Cur: cr, Changed: make([]bool, len(d.Columns))}
+		for i, st := range d.ColStatus {
+			if st == diffSame && br[i] != cr[i] {
+				dr.Changed[i] = true
+				dr.Status = diffChanged
+			}
+		}
+		if dr.Status == diffChanged {
+			d.Changed++
+		} else {
+			d.Same++
+		}
+		d.Rows = append(d.Rows, dr)
+	}
+	emitRemovedBefore(len(base.Rows))
+	return d
+}
+
+// commonColumns returns the columns present in both result sets, in the
+// order of a.
+func commonColumns(a, b DiffSource) []string {
+	in := make(map[string]bool, len(b.Columns))
+	for _, c := range b.Columns {
+		in[c] = true
+	}
+	var out []string
+	for _, c := range a.Columns {
+		if in[c] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+var diffBase = DiffSource{
+	Label:   "before",
+	Columns: []string{"id", "name", "score"},
+	Rows: [][]string{
+		{"1", "ann", "10"},
+		{"2", "bob", "20"},
+		{"3", "cid", "30"},
+	},
+}
+
+func diffStatuses(d resultDiff) []diffStatus {
+	var out []diffStatus
+	for _, r := range d.Rows {
+		out = append(out, r.Status)
+	}
+	return out
+}
+
+func TestDiffResults_Keyed(t *testing.T) {
+	cur := DiffSource{
+		Label:   "after",
+		Columns: []string{"id", "name", "score", "rank"},
+		Rows: [][]string{
+			{"1", "ann", "10", "2"},
+			{"3", "cid", "35", "1"},
+			{"4", "dee", "5", "3"},
+		},
+	}
+	d := diffResults(diffBase, cur, []string{"id"})
+
+	if !reflect.DeepEqual(d.Columns, []string{"id", "name", "score", "rank"}) {
+		t.Errorf("columns = %v", d.Columns)
+	}
+	if d.ColStatus[3] != diffAdded {
+		t.Errorf("rank column status = %v, want added", d.ColStatus[3])
+	}
+	want := []diffStatus{diffSame, diffRemoved, diffChanged, diffAdded}
+	if got := diffStatuses(d); !reflect.DeepEqual(got, want) {
+		t.Errorf("statuses = %v, want %v (removed row kept near its old position)", got, want)
+	}
+	if !reflect.DeepEqual(d.Rows[2].Changed, []bool{false, false, true, false}) {
+		t.Errorf("changed cells = %v, want only score; new columns do not count", d.Rows[2].Changed)
+	}
+	if d.Same != 1 || d.Changed != 1 || d.Added != 1 || d.Removed != 1 {
+		t.Errorf("counts = %d/%d/%d/%d", d.Same, d.Changed, d.Added, d.Removed)
+	}
+}
+
+func TestDiffResults_WholeRows(t *testing.T) {
+	cur := DiffSource{
+		Columns: []string{"id", "name", "score"},
+		Rows: [][]string{
+			{"2", "bob", "20"},
+			{"1", "ann", "11"},
+			{"2", "bob", "20"},
+		},
+	}
+	d := diffResults(diffBase, cur, nil)
+	// Without keys a changed value is a removal plus an addition, and the
+	// duplicate row only matches once.
+	if d.Same != 1 || d.Changed != 0 || d.Added != 2 || d.Removed != 2 {
+		t.Errorf("counts = same %d changed %d added %d removed %d", d.Same, d.Changed, d.Added, d.Removed)
+	}
+}
+
+func TestDiffResults_Reordered(t *testing.T) {
+	base := DiffSource{Columns: []string{"id", "v"}, Rows: [][]string{{"1", "a"}, {"2", "b"}}}
+	cur := DiffSource{Columns: []string{"id", "v"}, Rows: [][]string{{"2", "b"}, {"1", "a"}}}
+	d := diffResults(base, cur, []string{"id"})
+	// A baseline row matched by a later current row is not removed.
+	if d.Same != 2 || d.Changed+d.Added+d.Removed != 0 || len(d.Rows) != 2 {
+		t.Errorf("counts = same %d changed %d added %d removed %d, rows %d", d.Same, d.Changed, d.Added, d.Removed, len(d.Rows))
+	}
+}
+
+func TestDiffResults_UnknownKeyFallsBack(t *testing.T) {
+	d := diffResults(diffBase, diffBase, []string{"missing"})
+	if d.Same != 3 || d.Changed+d.Added+d.Removed != 0 {
+		t.Errorf("identical results should match fully: %+v", d)
+	}
+}
+
+func TestDiff_Sources(t *testing.T) {
+	d := NewDiff()
+	after := DiffSource{Label: "after", Columns: diffBase.Columns, Rows: diffBase.Rows[:2]}
+	d.SetBaseline(diffBase)
+	d.SetSources([]DiffSource{diffBase, after})
+
+	if d.compareSelect.Selected != "after" {
+		t.Errorf("selected = %q, want the newest source that is not the baseline", d.compareSelect.Selected)
+	}
+	if got := d.summary.Text; got != "0 added · 1 removed · 0 changed · 2 unchanged · matched on whole rows" {
+		t.Errorf("summary = %q", got)
+	}
+	d.changesOnly.SetChecked(true)
+	if len(d.rows) != 1 {
+		t.Errorf("changes only shows %d rows, want 1", len(d.rows))
+	}
+}
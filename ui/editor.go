@@ -2,20 +2,76 @@ package ui
 
 import (
 	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
-type RunQueryFunc func(project, sql string)
+type RunQueryFunc func(project, sql string, tab QueryTab)
+
+// QueryTab identifies the tab a query was run from, for its job labels.
+type QueryTab struct {
+	Name   string
+	Labels map[string]string
+}
+
+// SaveFileFunc asks the app layer to save tab, prompting for a path when the
+// tab has no backing file or saveAs is set. done (may be nil) reports
+// whether the tab was written.
+type SaveFileFunc func(tab *container.TabItem, saveAs bool, done func(saved bool))
+
+// CloseChoice is the user's answer when closing a tab with unsaved changes.
+type CloseChoice int
+
+const (
+	CloseCancel CloseChoice = iota
+	CloseSave
+	CloseDiscard
+)
 
 type queryTab struct {
 	editor  *SQLEditor
 	cancel  func()
 	project string
+
+	name      string            // tab title without the dirty marker
+	pinned    bool              // pinned tabs stay at the front and cannot be closed
+	path      string            // backing .sql file, empty for scratch tabs
+	savedText string            // content as last loaded or saved, for dirty tracking
+	modTime   time.Time         // file mtime at last load or save, for external change detection
+	labels    map[string]string // job labels for queries run from this tab
+}
+
+// TabState is the saved state of an editor tab, used for workspace restore.
+type TabState struct {
+	Name      string
+	Project   string
+	SQL       string
+	Path      string // backing file; SQL still holds the buffer, including unsaved edits
+	Pinned    bool
+	CursorRow int
+	CursorCol int
+	Labels    map[string]string
+}
+
+func (qt *queryTab) dirty() bool {
+	return qt.editor.Text() != qt.savedText
+}
+
+// tabTitle returns the label shown on a tab, marking unsaved changes.
+func tabTitle(name string, dirty bool) string {
+	if dirty {
+		return name + " •"
+	}
+	return name
 }
 
 type Editor struct {
@@ -28,10 +84,22 @@ type Editor struct {
 	tabData         map[*container.TabItem]*queryTab
 	tabCount        int
 	onProjectNeeded func(project string)
+	onRoutineNeeded func(project, dataset, routine string)
 
 	RunQuery RunQueryFunc
 	OnStop   func()
 
+	// File handling. Dialogs live in the app layer, which owns the window.
+	OnOpenFile       func()
+	OnSaveFile       SaveFileFunc
+	ConfirmClose     func(name string, answer func(CloseChoice))
+	OnExternalChange func(name string, reload func())
+
+	// PromptRename asks the user for a new tab name (dialog provided by the app layer).
+	PromptRename func(current string, apply func(name string))
+	// PromptLabels asks the user for the tab's job labels (dialog provided by the app layer).
+	PromptLabels func(current map[string]string, apply func(labels map[string]string))
+
 	Container fyne.CanvasObject
 }
 
@@ -58,22 +126,28 @@ func NewEditor() *Editor {
 		}
 	})
 
+	openBtn := widget.NewButtonWithIcon("Open", theme.Icon(theme.IconNameFolderOpen), e.openFile)
+	saveBtn := widget.NewButtonWithIcon("Save", theme.Icon(theme.IconNameDocumentSave), func() { e.saveFile(e.tabs.Selected(), false, nil) })
+	saveAsBtn := widget.NewButton("Save As", func() { e.saveFile(e.tabs.Selected(), true, nil) })
+	tabMenuBtn := widget.NewButtonWithIcon("", theme.Icon(theme.IconNameMoreVertical), nil)
+	tabMenuBtn.OnTapped = func() { e.showTabMenu(tabMenuBtn) }
+
 	e.tabs = container.NewDocTabs()
-	e.tabs.OnClosed = func(tab *container.TabItem) {
-		e.mu.Lock()
-		delete(e.tabData, tab)
-		e.mu.Unlock()
-	}
+	e.tabs.CloseIntercept = e.requestClose
 	e.tabs.CreateTab = func() *container.TabItem {
 		return e.newTab()
 	}
+	e.tabs.OnSelected = func(tab *container.TabItem) {
+		e.syncProjectSelect(tab)
+		e.checkExternalChange(tab)
+	}
 
 	// Start with one tab
 	first := e.newTab()
 	e.tabs.Append(first)
 	e.tabs.Select(first)
 
-	toolbar := container.NewHBox(e.projects, e.runBtn, e.stopBtn, layout.NewSpacer())
+	toolbar := container.NewHBox(e.projects, e.runBtn, e.stopBtn, openBtn, saveBtn, saveAsBtn, layout.NewSpacer(), tabMenuBtn)
 	e.Container = container.NewBorder(toolbar, nil, nil, nil, e.tabs)
 
 	return e
@@ -84,23 +158,315 @@ func (e *Editor) newTab() *container.TabItem {
 	editor := NewSQLEditor()
 	editor.SetPlaceHolder("Enter SQL query...")
 
-	tab := container.NewTabItem(fmt.Sprintf("Query %d", e.tabCount), editor)
+	name := fmt.Sprintf("Query %d", e.tabCount)
+	tab := container.NewTabItem(name, editor)
 
 	e.mu.Lock()
 	editor.OnProjectNeeded = e.onProjectNeeded
+	editor.OnRoutineNeeded = e.onRoutineNeeded
 	editor.OnSubmit = func() { e.run() }
-	e.tabData[tab] = &queryTab{
+	editor.OnOpen = e.openFile
+	editor.OnSave = func(saveAs bool) { e.saveFile(tab, saveAs, nil) }
+	qt := &queryTab{
 		editor:  editor,
 		project: e.projects.Selected,
+		name:    name,
 	}
+	e.tabData[tab] = qt
 	e.mu.Unlock()
+
+	editor.SetOnChanged(func(string) { e.refreshTabTitle(tab) })
 	return tab
 }
 
+// NewTabWithSQL opens a new scratch tab with the given name, project and SQL and selects it.
+func (e *Editor) NewTabWithSQL(name, project, sql string) {
+	tab := e.newTab()
+	e.mu.Lock()
+	qt := e.tabData[tab]
+	if name != "" {
+		qt.name = name
+	}
+	if project != "" {
+		qt.project = project
+	}
+	e.mu.Unlock()
+	qt.editor.SetText(sql)
+	tab.Text = tabTitle(qt.name, qt.dirty())
+	e.tabs.Append(tab)
+	e.tabs.Select(tab)
+}
+
+// refreshTabTitle updates a tab's label and icon after its name, pin or dirty state changed.
+func (e *Editor) refreshTabTitle(tab *container.TabItem) {
+	e.mu.Lock()
+	qt, ok := e.tabData[tab]
+	e.mu.Unlock()
+	if !ok {
+		return
+	}
+	title := tabTitle(qt.name, qt.dirty())
+	var icon fyne.Resource
+	if qt.pinned {
+		icon = theme.Icon(theme.IconNameRadioButtonChecked)
+	}
+	if tab.Text == title && tab.Icon == icon {
+		return
+	}
+	fyne.Do(func() {
+		tab.Text = title
+		tab.Icon = icon
+		e.tabs.Refresh()
+	})
+}
+
+func (e *Editor) showTabMenu(anchor fyne.CanvasObject) {
+	e.mu.Lock()
+	qt, ok := e.tabData[e.tabs.Selected()]
+	e.mu.Unlock()
+	if !ok {
+		return
+	}
+	pinLabel := "Pin Tab"
+	if qt.pinned {
+		pinLabel = "Unpin Tab"
+	}
+	menu := fyne.NewMenu("",
+		fyne.NewMenuItem("Rename…", func() {
+			if e.PromptRename != nil {
+				e.PromptRename(qt.name, e.RenameCurrentTab)
+			}
+		}),
+		fyne.NewMenuItem("Job Labels…", func() {
+			if e.PromptLabels != nil {
+				e.PromptLabels(e.CurrentTabLabels(), e.SetCurrentTabLabels)
+			}
+		}),
+		fyne.NewMenuItem("Duplicate", e.DuplicateCurrentTab),
+		fyne.NewMenuItem(pinLabel, e.TogglePinCurrentTab),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Move Left", func() { e.MoveCurrentTab(-1) }),
+		fyne.NewMenuItem("Move Right", func() { e.MoveCurrentTab(1) }),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Close Others", e.CloseOtherTabs),
+	)
+	d := fyne.CurrentApp().Driver()
+	pos := d.AbsolutePositionForObject(anchor).Add(fyne.NewPos(0, anchor.Size().Height))
+	widget.ShowPopUpMenuAtPosition(menu, d.CanvasForObject(anchor), pos)
+}
+
+// RenameCurrentTab sets the selected tab's title. Empty names are ignored.
+func (e *Editor) RenameCurrentTab(name string) {
+	if name == "" {
+		return
+	}
+	tab := e.tabs.Selected()
+	e.mu.Lock()
+	qt, ok := e.tabData[tab]
+	if ok {
+		qt.name = name
+	}
+	e.mu.Unlock()
+	e.refreshTabTitle(tab)
+}
+
+// CurrentTabLabels returns a copy of the selected tab's job labels.
+func (e *Editor) CurrentTabLabels() map[string]string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if qt, ok := e.tabData[e.tabs.Selected()]; ok {
+		return maps.Clone(qt.labels)
+	}
+	return nil
+}
+
+// CurrentQueryTab returns the selected tab's name and a copy of its job labels.
+func (e *Editor) CurrentQueryTab() QueryTab {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if qt, ok := e.tabData[e.tabs.Selected()]; ok {
+		return QueryTab{Name: qt.name, Labels: maps.Clone(qt.labels)}
+	}
+	return QueryTab{}
+}
+
+// SetCurrentTabLabels replaces the selected tab's job labels.
+func (e *Editor) SetCurrentTabLabels(labels map[string]string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if qt, ok := e.tabData[e.tabs.Selected()]; ok {
+		qt.labels = maps.Clone(labels)
+	}
+}
+
+// DuplicateCurrentTab opens a copy of the selected tab's SQL and project next
+// to it. The copy is unpinned, so a pinned tab's copy follows the last pinned
+// tab.
+func (e *Editor) DuplicateCurrentTab() {
+	current := e.tabs.Selected()
+	e.mu.Lock()
+	src, ok := e.tabData[current]
+	e.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	tab := e.newTab()
+	e.mu.Lock()
+	qt := e.tabData[tab]
+	qt.name = src.name + " copy"
+	qt.project = src.project
+	qt.labels = maps.Clone(src.labels)
+	e.mu.Unlock()
+	qt.editor.SetText(src.editor.Text())
+	tab.Text = tabTitle(qt.name, qt.dirty())
+
+	after := current
+	if src.pinned {
+		e.mu.Lock()
+		for _, it := range e.tabs.Items {
+			if d, ok := e.tabData[it]; ok && d.pinned {
+				after = it
+			}
+		}
+		e.mu.Unlock()
+	}
+	items := make([]*container.TabItem, 0, len(e.tabs.Items)+1)
+	for _, it := range e.tabs.Items {
+		items = append(items, it)
+		if it == after {
+			items = append(items, tab)
+		}
+	}
+	e.tabs.SetItems(items)
+	e.tabs.Select(tab)
+}
+
+// TogglePinCurrentTab pins or unpins the selected tab. Pinned tabs are kept
+// in front of unpinned ones.
+func (e *Editor) TogglePinCurrentTab() {
+	tab := e.tabs.Selected()
+	e.mu.Lock()
+	qt, ok := e.tabData[tab]
+	if !ok {
+		e.mu.Unlock()
+		return
+	}
+	qt.pinned = !qt.pinned
+	var pinned, unpinned []*container.TabItem
+	for _, it := range e.tabs.Items {
+		if d, ok := e.tabData[it]; ok && d.pinned {
+			pinned = append(pinned, it)
+		} else {
+			unpinned = append(unpinned, it)
+		}
+	}
+	e.mu.Unlock()
+
+	e.tabs.SetItems(append(pinned, unpinned...))
+	e.tabs.Select(tab)
+	e.refreshTabTitle(tab)
+}
+
+// MoveCurrentTab moves the selected tab left (delta < 0) or right (delta > 0)
+// by one position, without crossing between pinned and unpinned tabs.
+func (e *Editor) MoveCurrentTab(delta int) {
+	tab := e.tabs.Selected()
+	idx := e.tabs.SelectedIndex()
+	target := idx + 1
+	if delta < 0 {
+		target = idx - 1
+	}
+	if idx < 0 || target < 0 || target >= len(e.tabs.Items) {
+		return
+	}
+
+	e.mu.Lock()
+	a, aok := e.tabData[tab]
+	b, bok := e.tabData[e.tabs.Items[target]]
+	e.mu.Unlock()
+	if !aok || !bok || a.pinned != b.pinned {
+		return
+	}
+
+	items := make([]*container.TabItem, len(e.tabs.Items))
+	copy(items, e.tabs.Items)
+	items[idx], items[target] = items[target], items[idx]
+	e.tabs.SetItems(items)
+	e.tabs.Select(tab)
+}
+
+// CloseOtherTabs closes every unpinned tab except the selected one, asking
+// about unsaved changes where needed.
+func (e *Editor) CloseOtherTabs() {
+	current := e.tabs.Selected()
+	others := make([]*container.TabItem, 0, len(e.tabs.Items))
+	for _, it := range e.tabs.Items {
+		if it != current {
+			others = append(others, it)
+		}
+	}
+	for _, it := range others {
+		e.requestClose(it)
+	}
+}
+
+func (e *Editor) openFile() {
+	if e.OnOpenFile != nil {
+		e.OnOpenFile()
+	}
+}
+
+func (e *Editor) saveFile(tab *container.TabItem, saveAs bool, done func(saved bool)) {
+	if e.OnSaveFile != nil {
+		e.OnSaveFile(tab, saveAs, done)
+	} else if done != nil {
+		done(false)
+	}
+}
+
+// requestClose closes a tab, asking first when it has unsaved changes.
+func (e *Editor) requestClose(tab *container.TabItem) {
+	e.mu.Lock()
+	qt, ok := e.tabData[tab]
+	e.mu.Unlock()
+	if ok && qt.pinned {
+		return
+	}
+	if !ok || !qt.dirty() || e.ConfirmClose == nil {
+		e.closeTab(tab)
+		return
+	}
+	e.ConfirmClose(qt.name, func(choice CloseChoice) {
+		switch choice {
+		case CloseDiscard:
+			e.closeTab(tab)
+		case CloseSave:
+			e.tabs.Select(tab)
+			e.saveFile(tab, false, func(saved bool) {
+				if saved {
+					e.closeTab(tab)
+				}
+			})
+		}
+	})
+}
+
+func (e *Editor) closeTab(tab *container.TabItem) {
+	e.mu.Lock()
+	delete(e.tabData, tab)
+	e.mu.Unlock()
+	e.tabs.Remove(tab)
+}
+
 func (e *Editor) run() {
 	e.mu.Lock()
 	tab := e.tabs.Selected()
 	qt, ok := e.tabData[tab]
+	var from QueryTab
+	if ok {
+		from = QueryTab{Name: qt.name, Labels: maps.Clone(qt.labels)}
+	}
 	e.mu.Unlock()
 
 	if !ok {
@@ -115,19 +481,37 @@ func (e *Editor) run() {
 		return
 	}
 	if e.RunQuery != nil {
-		e.RunQuery(project, sql)
+		e.RunQuery(project, sql, from)
 	}
 }
 
 func (e *Editor) SetProjects(projects []string) {
 	fyne.Do(func() {
 		e.projects.Options = projects
+		e.mu.Lock()
+		qt, ok := e.tabData[e.tabs.Selected()]
+		e.mu.Unlock()
+		if ok && qt.project != "" {
+			// Keep the tab's own project rather than defaulting to the first one.
+			e.projects.SetSelected(qt.project)
+			return
+		}
 		if len(projects) > 0 && e.projects.Selected == "" {
 			e.projects.SetSelected(projects[0])
 		}
 	})
 }
 
+// syncProjectSelect shows the project of the newly selected tab.
+func (e *Editor) syncProjectSelect(tab *container.TabItem) {
+	e.mu.Lock()
+	qt, ok := e.tabData[tab]
+	e.mu.Unlock()
+	if ok && qt.project != "" {
+		e.projects.SetSelected(qt.project)
+	}
+}
+
 func (e *Editor) SetProject(project string) {
 	fyne.Do(func() {
 		e.projects.SetSelected(project)
@@ -166,6 +550,269 @@ func (e *Editor) SetSQL(sql string) {
 	}
 }
 
+// OpenFile loads a SQL file into a tab. A tab already showing the file is
+// selected; an empty, clean scratch tab is reused; otherwise a new tab is added.
+func (e *Editor) OpenFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", filepath.Base(path), err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", filepath.Base(path), err)
+	}
+
+	e.mu.Lock()
+	var target *container.TabItem
+	for tab, qt := range e.tabData {
+		if qt.path == path {
+			e.mu.Unlock()
+			e.tabs.Select(tab)
+			return nil
+		}
+	}
+	if tab := e.tabs.Selected(); tab != nil {
+		if qt, ok := e.tabData[tab]; ok && qt.path == "" && qt.editor.Text() == "" {
+			target = tab
+		}
+	}
+	e.mu.Unlock()
+
+	if target == nil {
+		target = e.newTab()
+		e.tabs.Append(target)
+	}
+
+	e.mu.Lock()
+	qt := e.tabData[target]
+	qt.path = path
+	qt.name = filepath.Base(path)
+	qt.savedText = string(data)
+	qt.modTime = info.ModTime()
+	e.mu.Unlock()
+
+	qt.editor.SetText(string(data))
+	e.tabs.Select(target)
+	e.refreshTabTitle(target)
+	return nil
+}
+
+// CurrentTab returns the selected tab.
+func (e *Editor) CurrentTab() *container.TabItem {
+	return e.tabs.Selected()
+}
+
+// FilePath returns the file backing tab, or "" for scratch tabs.
+func (e *Editor) FilePath(tab *container.TabItem) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if qt, ok := e.tabData[tab]; ok {
+		return qt.path
+	}
+	return ""
+}
+
+// TabName returns tab's title without the dirty marker.
+func (e *Editor) TabName(tab *container.TabItem) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if qt, ok := e.tabData[tab]; ok {
+		return qt.name
+	}
+	return ""
+}
+
+// SaveFile writes tab to path and makes path its backing file.
+func (e *Editor) SaveFile(tab *container.TabItem, path string) error {
+	e.mu.Lock()
+	qt, ok := e.tabData[tab]
+	e.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("tab is closed")
+	}
+
+	text := qt.editor.Text()
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		return fmt.Errorf("write %s: %w", filepath.Base(path), err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", filepath.Base(path), err)
+	}
+
+	e.mu.Lock()
+	qt.path = path
+	qt.name = filepath.Base(path)
+	qt.savedText = text
+	qt.modTime = info.ModTime()
+	e.mu.Unlock()
+
+	e.refreshTabTitle(tab)
+	return nil
+}
+
+// FileChangedOnDisk reports whether tab's file was modified by another
+// program since it was last loaded or saved.
+func (e *Editor) FileChangedOnDisk(tab *container.TabItem) bool {
+	e.mu.Lock()
+	qt, ok := e.tabData[tab]
+	e.mu.Unlock()
+	if !ok || qt.path == "" {
+		return false
+	}
+	info, err := os.Stat(qt.path)
+	if err != nil {
+		return false
+	}
+	return !info.ModTime().Equal(qt.modTime)
+}
+
+// CheckExternalChanges looks for files modified outside the app. Clean tabs
+// are reloaded; tabs with unsaved edits are offered a reload via OnExternalChange.
+func (e *Editor) CheckExternalChanges() {
+	e.mu.Lock()
+	tabs := make([]*container.TabItem, 0, len(e.tabData))
+	for tab := range e.tabData {
+		tabs = append(tabs, tab)
+	}
+	e.mu.Unlock()
+	for _, tab := range tabs {
+		e.checkExternalChange(tab)
+	}
+}
+
+func (e *Editor) checkExternalChange(tab *container.TabItem) {
+	e.mu.Lock()
+	qt, ok := e.tabData[tab]
+	e.mu.Unlock()
+	if !ok || qt.path == "" {
+		return
+	}
+	info, err := os.Stat(qt.path)
+	if err != nil || info.ModTime().Equal(qt.modTime) {
+		return
+	}
+	data, err := os.ReadFile(qt.path)
+	if err != nil {
+		return
+	}
+
+	dirty := qt.dirty()
+	e.mu.Lock()
+	qt.modTime = info.ModTime()
+	qt.savedText = string(data)
+	e.mu.Unlock()
+
+	reload := func() {
+		fyne.Do(func() {
+			qt.editor.SetText(string(data))
+		})
+		e.refreshTabTitle(tab)
+	}
+	if !dirty {
+		reload()
+		return
+	}
+	// The buffer no longer matches the file; keep the edits marked dirty
+	// unless the user chooses to reload.
+	e.refreshTabTitle(tab)
+	if e.OnExternalChange != nil {
+		e.OnExternalChange(qt.name, reload)
+	}
+}
+
+// Session returns the state of all tabs in display order and the index of the selected one.
+func (e *Editor) Session() (tabs []TabState, selected int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	current := e.tabs.Selected()
+	for _, tab := range e.tabs.Items {
+		qt, ok := e.tabData[tab]
+		if !ok {
+			continue
+		}
+		row, col := qt.editor.CursorPosition()
+		if tab == current {
+			selected = len(tabs)
+		}
+		tabs = append(tabs, TabState{
+			Name:      qt.name,
+			Project:   qt.project,
+			SQL:       qt.editor.Text(),
+			Path:      qt.path,
+			Pinned:    qt.pinned,
+			CursorRow: row,
+			CursorCol: col,
+			Labels:    maps.Clone(qt.labels),
+		})
+	}
+	return tabs, selected
+}
+
+// RestoreSession replaces all tabs with the given saved tabs. File-backed tabs
+// are compared against the file on disk so unsaved edits stay marked dirty.
+// An empty session leaves a single scratch tab.
+func (e *Editor) RestoreSession(tabs []TabState, selected int) {
+	e.mu.Lock()
+	e.tabData = make(map[*container.TabItem]*queryTab)
+	e.tabCount = 0
+	e.mu.Unlock()
+
+	if len(tabs) == 0 {
+		tabs = []TabState{{}}
+	}
+
+	items := make([]*container.TabItem, 0, len(tabs))
+	maxQueryNum := 0
+	for _, ts := range tabs {
+		tab := e.newTab()
+
+		e.mu.Lock()
+		qt := e.tabData[tab]
+		if ts.Name != "" {
+			qt.name = ts.Name
+		}
+		qt.project = ts.Project
+		qt.pinned = ts.Pinned
+		qt.labels = maps.Clone(ts.Labels)
+		if ts.Path != "" {
+			if data, err := os.ReadFile(ts.Path); err == nil {
+				if info, err := os.Stat(ts.Path); err == nil {
+					qt.path = ts.Path
+					qt.savedText = string(data)
+					qt.modTime = info.ModTime()
+				}
+			}
+		}
+		var n int
+		if _, err := fmt.Sscanf(qt.name, "Query %d", &n); err == nil && n > maxQueryNum {
+			maxQueryNum = n
+		}
+		e.mu.Unlock()
+
+		qt.editor.SetText(ts.SQL)
+		qt.editor.SetCursorPosition(ts.CursorRow, ts.CursorCol)
+		tab.Text = tabTitle(qt.name, qt.dirty())
+		if qt.pinned {
+			tab.Icon = theme.Icon(theme.IconNameRadioButtonChecked)
+		}
+		items = append(items, tab)
+	}
+
+	e.mu.Lock()
+	if maxQueryNum > e.tabCount {
+		e.tabCount = maxQueryNum
+	}
+	e.mu.Unlock()
+
+	if selected < 0 || selected >= len(items) {
+		selected = 0
+	}
+	e.tabs.SetItems(items)
+	e.tabs.Select(items[selected])
+	e.syncProjectSelect(items[selected])
+}
+
 // SetCompletions passes autocomplete items to the current tab's SQLEditor.
 func (e *Editor) SetCompletions(items []string) {
 	e.mu.Lock()
@@ -187,6 +834,28 @@ func (e *Editor) SetOnProjectNeeded(fn func(project string)) {
 	e.mu.Unlock()
 }
 
+// SetOnRoutineNeeded sets the callback that loads a routine's signature
+// when the cursor first enters its argument list.
+func (e *Editor) SetOnRoutineNeeded(fn func(project, dataset, routine string)) {
+	e.mu.Lock()
+	e.onRoutineNeeded = fn
+	for _, qt := range e.tabData {
+		qt.editor.OnRoutineNeeded = fn
+	}
+	e.mu.Unlock()
+}
+
+// SetRoutines passes known routines to the current tab's SQLEditor.
+func (e *Editor) SetRoutines(routines []Routine) {
+	e.mu.Lock()
+	tab := e.tabs.Selected()
+	qt, ok := e.tabData[tab]
+	e.mu.Unlock()
+	if ok {
+		qt.editor.SetRoutines(routines)
+	}
+}
+
 // SetProjectData passes project hierarchy data to the current tab's SQLEditor.
 func (e *Editor) SetProjectData(data map[string]map[string][]string) {
 	e.mu.Lock()
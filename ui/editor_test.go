@@ -0,0 +1,356 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/container"
+)
+
+func TestTabTitle(t *testing.T) {
+	if got := tabTitle("report.sql", false); got != "report.sql" {
+		t.Errorf("expected clean title 'report.sql', got %q", got)
+	}
+	if got := tabTitle("report.sql", true); got != "report.sql •" {
+		t.Errorf("expected dirty title 'report.sql •', got %q", got)
+	}
+}
+
+func TestEditor_OpenFileReusesEmptyTab(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daily.sql")
+	if err := os.WriteFile(path, []byte("SELECT 1"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	e := NewEditor()
+	if err := e.OpenFile(path); err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	if len(e.tabs.Items) != 1 {
+		t.Fatalf("expected empty scratch tab to be reused, got %d tabs", len(e.tabs.Items))
+	}
+	if got := e.GetCurrentSQL(); got != "SELECT 1" {
+		t.Errorf("expected file content, got %q", got)
+	}
+	if got := e.tabs.Selected().Text; got != "daily.sql" {
+		t.Errorf("expected tab titled from file name, got %q", got)
+	}
+	if got := e.FilePath(e.CurrentTab()); got != path {
+		t.Errorf("expected current path %q, got %q", path, got)
+	}
+
+	// Opening the same file again selects the existing tab.
+	if err := e.OpenFile(path); err != nil {
+		t.Fatalf("OpenFile again: %v", err)
+	}
+	if len(e.tabs.Items) != 1 {
+		t.Errorf("expected no new tab for an already open file, got %d tabs", len(e.tabs.Items))
+	}
+}
+
+func TestEditor_SaveClearsDirty(t *testing.T) {
+	dir := t.TempDir()
+	e := NewEditor()
+	e.SetSQL("SELECT 2")
+
+	tab := e.tabs.Selected()
+	if got := tab.Text; got != "Query 1 •" {
+		t.Errorf("expected dirty scratch tab, got %q", got)
+	}
+
+	path := filepath.Join(dir, "saved.sql")
+	if err := e.SaveFile(tab, path); err != nil {
+		t.Fatalf("SaveFile: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read back: %v", err)
+	}
+	if string(data) != "SELECT 2" {
+		t.Errorf("expected saved content 'SELECT 2', got %q", data)
+	}
+	if got := tab.Text; got != "saved.sql" {
+		t.Errorf("expected clean tab titled 'saved.sql', got %q", got)
+	}
+	if e.FileChangedOnDisk(e.CurrentTab()) {
+		t.Error("expected file to be unchanged on disk right after saving")
+	}
+}
+
+func TestEditor_CloseSavesTheClosedTab(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "closed.sql")
+	e := NewEditor()
+	e.RestoreSession([]TabState{{Name: "a", SQL: "SELECT 'a'"}, {Name: "b", SQL: "SELECT 'b'"}}, 0)
+	closing := e.tabs.Items[1]
+
+	e.ConfirmClose = func(name string, answer func(CloseChoice)) { answer(CloseSave) }
+	e.OnSaveFile = func(tab *container.TabItem, saveAs bool, done func(saved bool)) {
+		e.tabs.SelectIndex(0) // the selection may move while a save dialog is open
+		done(e.SaveFile(tab, path) == nil)
+	}
+	e.requestClose(closing)
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "SELECT 'b'" {
+		t.Errorf("saved %q (%v), want the closed tab's SQL", data, err)
+	}
+	if got := tabNames(e); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("tabs after close = %v", got)
+	}
+}
+
+func TestEditor_ExternalChangeReloadsCleanTab(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ext.sql")
+	if err := os.WriteFile(path, []byte("SELECT 1"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	e := NewEditor()
+	if err := e.OpenFile(path); err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("SELECT 3"), 0644); err != nil {
+		t.Fatalf("rewrite: %v", err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if !e.FileChangedOnDisk(e.CurrentTab()) {
+		t.Fatal("expected external modification to be detected")
+	}
+
+	e.CheckExternalChanges()
+	if got := e.GetCurrentSQL(); got != "SELECT 3" {
+		t.Errorf("expected clean tab to reload from disk, got %q", got)
+	}
+}
+
+func TestEditor_ExternalChangePromptsForDirtyTab(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ext.sql")
+	if err := os.WriteFile(path, []byte("SELECT 1"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	e := NewEditor()
+	if err := e.OpenFile(path); err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	e.SetSQL("SELECT 1 -- local edit")
+
+	var prompted string
+	e.OnExternalChange = func(name string, reload func()) { prompted = name }
+
+	later := time.Now().Add(time.Minute)
+	if err := os.WriteFile(path, []byte("SELECT 4"), 0644); err != nil {
+		t.Fatalf("rewrite: %v", err)
+	}
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	e.CheckExternalChanges()
+	if prompted != "ext.sql" {
+		t.Errorf("expected prompt for ext.sql, got %q", prompted)
+	}
+	if got := e.GetCurrentSQL(); got != "SELECT 1 -- local edit" {
+		t.Errorf("expected local edits to be kept until reload, got %q", got)
+	}
+}
+
+func TestEditor_CloseDirtyTabAsks(t *testing.T) {
+	e := NewEditor()
+	e.SetSQL("SELECT 1")
+	tab := e.tabs.Selected()
+
+	var asked bool
+	e.ConfirmClose = func(name string, answer func(CloseChoice)) {
+		asked = true
+		answer(CloseCancel)
+	}
+	e.requestClose(tab)
+	if !asked {
+		t.Fatal("expected close confirmation for a dirty tab")
+	}
+	if len(e.tabs.Items) != 1 {
+		t.Fatalf("expected tab to stay open after cancel, got %d tabs", len(e.tabs.Items))
+	}
+
+	e.ConfirmClose = func(name string, answer func(CloseChoice)) { answer(CloseDiscard) }
+	e.requestClose(tab)
+	if len(e.tabs.Items) != 0 {
+		t.Errorf("expected tab to close after discard, got %d tabs", len(e.tabs.Items))
+	}
+}
+
+func TestEditor_SessionRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checks.sql")
+	if err := os.WriteFile(path, []byte("SELECT 1"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	saved := []TabState{
+		{Name: "Query 4", Project: "proj-a", SQL: "SELECT a\nFROM t", CursorRow: 1, CursorCol: 3, Labels: map[string]string{"team": "bi"}},
+		{Name: "checks.sql", Project: "proj-b", SQL: "SELECT 1 -- edited", Path: path},
+	}
+
+	e := NewEditor()
+	e.RestoreSession(saved, 1)
+
+	if len(e.tabs.Items) != 2 {
+		t.Fatalf("expected 2 restored tabs, got %d", len(e.tabs.Items))
+	}
+	if got := e.GetCurrentProject(); got != "proj-b" {
+		t.Errorf("expected selected tab project proj-b, got %q", got)
+	}
+	if got := e.tabs.Items[1].Text; got != "checks.sql •" {
+		t.Errorf("expected unsaved edits to be marked dirty, got %q", got)
+	}
+
+	tabs, selected := e.Session()
+	if selected != 1 {
+		t.Errorf("expected selected index 1, got %d", selected)
+	}
+	if len(tabs) != 2 {
+		t.Fatalf("expected 2 tabs in session, got %d", len(tabs))
+	}
+	for i := range saved {
+		if !reflect.DeepEqual(tabs[i], saved[i]) {
+			t.Errorf("tab %d: expected %+v, got %+v", i, saved[i], tabs[i])
+		}
+	}
+
+	// New tabs continue numbering after restored scratch tabs.
+	next := e.newTab()
+	if next.Text != "Query 5" {
+		t.Errorf("expected next scratch tab 'Query 5', got %q", next.Text)
+	}
+}
+
+func TestEditor_RestoreEmptySession(t *testing.T) {
+	e := NewEditor()
+	e.SetSQL("SELECT 1")
+	e.RestoreSession(nil, 0)
+	if len(e.tabs.Items) != 1 {
+		t.Fatalf("expected a single scratch tab, got %d", len(e.tabs.Items))
+	}
+	if got := e.GetCurrentSQL(); got != "" {
+		t.Errorf("expected empty scratch tab, got %q", got)
+	}
+}
+
+func tabNames(e *Editor) []string {
+	names := make([]string, len(e.tabs.Items))
+	for i, tab := range e.tabs.Items {
+		names[i] = e.tabData[tab].name
+	}
+	return names
+}
+
+func TestEditor_RenameAndDuplicate(t *testing.T) {
+	e := NewEditor()
+	e.RestoreSession([]TabState{{Name: "Query 1", Project: "proj-a", SQL: "SELECT 1"}, {Name: "Query 2"}}, 0)
+
+	e.RenameCurrentTab("revenue by day")
+	e.DuplicateCurrentTab()
+
+	want := []string{"revenue by day", "revenue by day copy", "Query 2"}
+	if got := tabNames(e); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected tabs %v, got %v", want, got)
+	}
+	if e.tabs.SelectedIndex() != 1 {
+		t.Errorf("expected duplicate to be selected, got index %d", e.tabs.SelectedIndex())
+	}
+	if got := e.GetCurrentSQL(); got != "SELECT 1" {
+		t.Errorf("expected duplicated SQL, got %q", got)
+	}
+	if got := e.GetCurrentProject(); got != "proj-a" {
+		t.Errorf("expected duplicated project proj-a, got %q", got)
+	}
+
+	e.RenameCurrentTab("")
+	if got := e.TabName(e.CurrentTab()); got != "revenue by day copy" {
+		t.Errorf("expected empty rename to be ignored, got %q", got)
+	}
+}
+
+func TestEditor_PinAndMove(t *testing.T) {
+	e := NewEditor()
+	e.RestoreSession([]TabState{{Name: "a"}, {Name: "b"}, {Name: "c"}}, 2)
+
+	e.TogglePinCurrentTab()
+	if got := tabNames(e); !reflect.DeepEqual(got, []string{"c", "a", "b"}) {
+		t.Fatalf("expected pinned tab moved to front, got %v", got)
+	}
+
+	// A pinned tab cannot move past unpinned ones.
+	e.MoveCurrentTab(1)
+	if got := tabNames(e); !reflect.DeepEqual(got, []string{"c", "a", "b"}) {
+		t.Errorf("expected pinned tab to stay in front, got %v", got)
+	}
+
+	e.tabs.SelectIndex(1)
+	e.MoveCurrentTab(1)
+	if got := tabNames(e); !reflect.DeepEqual(got, []string{"c", "b", "a"}) {
+		t.Errorf("expected 'a' moved right, got %v", got)
+	}
+
+	// Pinned tabs survive close requests and Close Others.
+	e.CloseOtherTabs()
+	if got := tabNames(e); !reflect.DeepEqual(got, []string{"c", "a"}) {
+		t.Errorf("expected only pinned and current tab left, got %v", got)
+	}
+	e.requestClose(e.tabs.Items[0])
+	if len(e.tabs.Items) != 2 {
+		t.Errorf("expected pinned tab to stay open, got %v", tabNames(e))
+	}
+
+	tabs, _ := e.Session()
+	if !tabs[0].Pinned || tabs[1].Pinned {
+		t.Errorf("expected pin state in session, got %+v", tabs)
+	}
+}
+
+func TestEditor_DuplicatePinned(t *testing.T) {
+	e := NewEditor()
+	e.RestoreSession([]TabState{{Name: "a", Pinned: true}, {Name: "b", Pinned: true}, {Name: "c"}}, 0)
+
+	e.DuplicateCurrentTab()
+	if got := tabNames(e); !reflect.DeepEqual(got, []string{"a", "b", "a copy", "c"}) {
+		t.Fatalf("expected copy after the pinned tabs, got %v", got)
+	}
+	tabs, selected := e.Session()
+	if tabs[2].Pinned || selected != 2 {
+		t.Errorf("expected unpinned copy selected, got %+v (selected %d)", tabs, selected)
+	}
+}
+
+func TestEditor_RunPassesItsTab(t *testing.T) {
+	e := NewEditor()
+	e.RestoreSession([]TabState{{Name: "revenue", Project: "proj-a", SQL: "SELECT 1"}}, 0)
+	e.SetCurrentTabLabels(map[string]string{"team": "finance"})
+
+	var got QueryTab
+	e.RunQuery = func(project, sql string, tab QueryTab) { got = tab }
+	e.run()
+	if got.Name != "revenue" || got.Labels["team"] != "finance" {
+		t.Errorf("run passed tab %+v", got)
+	}
+}
+
+func TestTabLabels(t *testing.T) {
+	e := NewEditor()
+	e.SetCurrentTabLabels(map[string]string{"team": "finance"})
+	labels := e.CurrentTabLabels()
+	labels["team"] = "changed"
+	if got := e.CurrentTabLabels()["team"]; got != "finance" {
+		t.Errorf("labels should be copied, got %q", got)
+	}
+
+	e.DuplicateCurrentTab()
+	if got := e.CurrentTabLabels()["team"]; got != "finance" {
+		t.Errorf("duplicated tab labels = %v", e.CurrentTabLabels())
+	}
+}
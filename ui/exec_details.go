@@ -0,0 +1,462 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ExecStats are the execution statistics of a query job.
+type ExecStats struct {
+	StatementType    string
+	CacheHit         bool
+	BytesProcessed   int64
+	BytesBilled      int64
+	SlotMillis       int64
+	Elapsed          time.Duration
+	ReferencedTables []string
+	Stages           []ExecStage
+	Timeline         []ExecSample
+}
+
+// ExecStage is one stage of a query plan. Ratios are relative to the
+// slowest worker of the whole query.
+type ExecStage struct {
+	ID     int64
+	Name   string
+	Status string
+	Inputs []int64 // IDs of the stages this one reads from
+
+	RecordsRead     int64
+	RecordsWritten  int64
+	ShuffleBytes    int64
+	ShuffleSpilled  int64
+	ParallelInputs  int64
+	CompletedInputs int64
+
+	WaitRatioAvg, WaitRatioMax       float64
+	ReadRatioAvg, ReadRatioMax       float64
+	ComputeRatioAvg, ComputeRatioMax float64
+	WriteRatioAvg, WriteRatioMax     float64
+	ComputeAvg, ComputeMax           time.Duration
+
+	Duration time.Duration
+	Steps    []string
+}
+
+// ExecSample is one point of the slot usage timeline.
+type ExecSample struct {
+	Elapsed      time.Duration
+	ActiveUnits  int64
+	PendingUnits int64
+	SlotMillis   int64 // cumulative
+}
+
+// skewFactor is how much slower than average the slowest worker of a stage
+// must be for the stage to be flagged as skewed.
+const skewFactor = 3
+
+// Skew returns how much longer the slowest worker computed than the
+// average worker, or 0 when unknown.
+func (s ExecStage) Skew() float64 {
+	if s.ComputeAvg > 0 {
+		return float64(s.ComputeMax) / float64(s.ComputeAvg)
+	}
+	if s.ComputeRatioAvg > 0 {
+		return s.ComputeRatioMax / s.ComputeRatioAvg
+	}
+	return 0
+}
+
+// Skewed reports whether the stage has a few workers doing most of the
+// work, typically from a join or aggregation on an unevenly distributed key.
+func (s ExecStage) Skewed() bool {
+	return s.Skew() >= skewFactor && (s.ComputeMax >= time.Second || s.ComputeRatioMax >= 0.2)
+}
+
+// stageDepths assigns each stage the length of the longest input chain
+// leading to it, so inputs are always drawn to the left of their readers.
+func stageDepths(stages []ExecStage) map[int64]int {
+	byID := make(map[int64]*ExecStage, len(stages))
+	for i := range stages {
+		byID[stages[i].ID] = &stages[i]
+	}
+	depth := make(map[int64]int, len(stages))
+	visiting := make(map[int64]bool)
+	var visit func(id int64) int
+	visit = func(id int64) int {
+		if d, ok := depth[id]; ok {
+			return d
+		}
+		s := byID[id]
+		if s == nil || visiting[id] {
+			return -1 // unknown input or a cycle; ignore it
+		}
+		visiting[id] = true
+		d := 0
+		for _, in := range s.Inputs {
+			d = max(d, visit(in)+1)
+		}
+		visiting[id] = false
+		depth[id] = d
+		return d
+	}
+	for _, s := range stages {
+		visit(s.ID)
+	}
+	return depth
+}
+
+const (
+	stageNodeWidth  float32 = 170
+	stageNodeHeight float32 = 46
+	stageGapX       float32 = 48
+	stageGapY       float32 = 16
+	stagePad        float32 = 12
+)
+
+// stagePositions lays out stages left to right by depth, returning the top
+// left corner of each stage's box and the total size.
+func stagePositions(stages []ExecStage) (map[int64]fyne.Position, fyne.Size) {
+	depth := stageDepths(stages)
+	rows := make(map[int]int)
+	pos := make(map[int64]fyne.Position, len(stages))
+	var size fyne.Size
+	for _, s := range stages {
+		d := depth[s.ID]
+		r := rows[d]
+		rows[d]++
+		p := fyne.NewPos(stagePad+float32(d)*(stageNodeWidth+stageGapX), stagePad+float32(r)*(stageNodeHeight+stageGapY))
+		pos[s.ID] = p
+		size.Width = max(size.Width, p.X+stageNodeWidth+stagePad)
+		size.Height = max(size.Height, p.Y+stageNodeHeight+stagePad)
+	}
+	return pos, size
+}
+
+// stageGraph draws the query plan as boxes joined by lines from each input
+// stage to its reader. Tapping a stage selects it.
+type stageGraph struct {
+	widget.BaseWidget
+	stages   []ExecStage
+	selected int64 // -1 for none
+
+	onSelect func(id int64)
+}
+
+func newStageGraph(onSelect func(id int64)) *stageGraph {
+	g := &stageGraph{selected: -1, onSelect: onSelect}
+	g.ExtendBaseWidget(g)
+	return g
+}
+
+func (g *stageGraph) Tapped(ev *fyne.PointEvent) {
+	pos, _ := stagePositions(g.stages)
+	for _, s := range g.stages {
+		p := pos[s.ID]
+		if ev.Position.X >= p.X && ev.Position.X <= p.X+stageNodeWidth &&
+			ev.Position.Y >= p.Y && ev.Position.Y <= p.Y+stageNodeHeight {
+			g.selected = s.ID
+			g.Refresh()
+			if g.onSelect != nil {
+				g.onSelect(s.ID)
+			}
+			return
+		}
+	}
+}
+
+func (g *stageGraph) CreateRenderer() fyne.WidgetRenderer {
+	r := &stageGraphRenderer{graph: g}
+	r.build()
+	return r
+}
+
+type stageGraphRenderer struct {
+	graph   *stageGraph
+	objects []fyne.CanvasObject
+	size    fyne.Size
+}
+
+func (r *stageGraphRenderer) build() {
+	g := r.graph
+	pos, size := stagePositions(g.stages)
+	r.size = size
+	r.objects = nil
+	fg := theme.Color(theme.ColorNameForeground)
+
+	// Edges first, so boxes are drawn over them.
+	for _, s := range g.stages {
+		to := pos[s.ID]
+		for _, in := range s.Inputs {
+			from, ok := pos[in]
+			if !ok {
+				continue
+			}
+			l := canvas.NewLine(theme.Color(theme.ColorNameDisabled))
+			l.StrokeWidth = 1.5
+			l.Position1 = fyne.NewPos(from.X+stageNodeWidth, from.Y+stageNodeHeight/2)
+			l.Position2 = fyne.NewPos(to.X, to.Y+stageNodeHeight/2)
+			r.objects = append(r.objects, l)
+		}
+	}
+	for _, s := range g.stages {
+		p := pos[s.ID]
+		box := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+		box.CornerRadius = 4
+		box.StrokeWidth = 1
+		box.StrokeColor = theme.Color(theme.ColorNameSeparator)
+		if s.Skewed() {
+			box.StrokeColor = theme.Color(theme.ColorNameWarning)
+			box.StrokeWidth = 2
+		}
+		if s.ID == g.selected {
+			box.StrokeColor = theme.Color(theme.ColorNamePrimary)
+			box.StrokeWidth = 2
+		}
+		box.Move(p)
+		box.Resize(fyne.NewSize(stageNodeWidth, stageNodeHeight))
+
+		title := canvas.NewText(truncate(s.Name, 22), fg)
+		title.TextSize = chartTextSize + 1
+		title.TextStyle = fyne.TextStyle{Bold: true}
+		title.Move(p.Add(fyne.NewPos(8, 6)))
+
+		sub := fmt.Sprintf("%s → %s rows", formatCount(s.RecordsRead), formatCount(s.RecordsWritten))
+		if s.Skewed() {
+			sub += " · skew"
+		}
+		detail := canvas.NewText(sub, fg)
+		detail.TextSize = chartTextSize
+		detail.Move(p.Add(fyne.NewPos(8, 26)))
+
+		r.objects = append(r.objects, box, title, detail)
+	}
+}
+
+func (r *stageGraphRenderer) Layout(fyne.Size) {}
+
+func (r *stageGraphRenderer) MinSize() fyne.Size { return r.size }
+
+func (r *stageGraphRenderer) Refresh() {
+	r.build()
+	canvas.Refresh(r.graph)
+}
+
+func (r *stageGraphRenderer) Objects() []fyne.CanvasObject { return r.objects }
+func (r *stageGraphRenderer) Destroy()                     {}
+
+// ExecDetails shows the execution statistics of the last query: a summary,
+// the stage graph, details of the selected stage and the slot usage
+// timeline.
+type ExecDetails struct {
+	summary  *widget.Label
+	graph    *stageGraph
+	stageBox *widget.Label
+	timeline *chartPlot
+	stats    *ExecStats
+
+	Container fyne.CanvasObject
+}
+
+func NewExecDetails() *ExecDetails {
+	d := &ExecDetails{
+		summary:  widget.NewLabel(""),
+		stageBox: widget.NewLabel(""),
+		timeline: newChartPlot(),
+	}
+	d.summary.Wrapping = fyne.TextWrapWord
+	d.stageBox.TextStyle = fyne.TextStyle{Monospace: true}
+	d.graph = newStageGraph(d.showStage)
+
+	planSplit := container.NewHSplit(container.NewScroll(d.graph), container.NewVScroll(d.stageBox))
+	planSplit.Offset = 0.65
+	split := container.NewVSplit(planSplit, d.timeline)
+	split.Offset = 0.65
+	d.Container = container.NewBorder(d.summary, nil, nil, nil, split)
+	d.SetStats(nil)
+	return d
+}
+
+// SetStats shows the statistics of a query, or a placeholder when stats is
+// nil (e.g. for an offline snapshot).
+func (d *ExecDetails) SetStats(stats *ExecStats) {
+	fyne.Do(func() {
+		d.stats = stats
+		d.graph.selected = -1
+		if stats == nil {
+			d.summary.SetText("No execution details. Run a query to see its plan and slot usage.")
+			d.graph.stages = nil
+			d.stageBox.SetText("")
+			d.timeline.data = chartData{}
+		} else {
+			d.summary.SetText(stats.describe())
+			d.graph.stages = stats.Stages
+			d.stageBox.SetText("Select a stage to see its steps and timing.")
+			if s := mostSkewed(stats.Stages); s != nil {
+				d.graph.selected = s.ID
+				d.stageBox.SetText(s.describe())
+			}
+			d.timeline.data = timelineChart(stats.Timeline)
+		}
+		d.graph.Refresh()
+		d.timeline.Refresh()
+	})
+}
+
+func (d *ExecDetails) showStage(id int64) {
+	if d.stats == nil {
+		return
+	}
+	for _, s := range d.stats.Stages {
+		if s.ID == id {
+			d.stageBox.SetText(s.describe())
+		}
+	}
+}
+
+// mostSkewed returns the skewed stage with the highest skew, if any.
+func mostSkewed(stages []ExecStage) *ExecStage {
+	var best *ExecStage
+	for i := range stages {
+		if stages[i].Skewed() && (best == nil || stages[i].Skew() > best.Skew()) {
+			best = &stages[i]
+		}
+	}
+	return best
+}
+
+func (s *ExecStats) describe() string {
+	parts := []string{}
+	if s.StatementType != "" {
+		parts = append(parts, s.StatementType)
+	}
+	if s.CacheHit {
+		parts = append(parts, "served from cache")
+	}
+	parts = append(parts,
+		formatBytes(s.BytesProcessed)+" processed",
+		formatBytes(s.BytesBilled)+" billed",
+		fmt.Sprintf("%.1f slot-seconds", float64(s.SlotMillis)/1000),
+		"elapsed "+s.Elapsed.Round(time.Millisecond).String(),
+		fmt.Sprintf("%d stages", len(s.Stages)),
+	)
+	if n := len(s.ReferencedTables); n > 0 {
+		tables := s.ReferencedTables
+		if n > 3 {
+			tables = append(tables[:3:3], fmt.Sprintf("%d more", n-3))
+		}
+		parts = append(parts, "reads "+strings.Join(tables, ", "))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// describe renders a stage's metrics with text bars for the share of time
+// workers spent waiting, reading, computing and writing.
+func (s ExecStage) describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Stage %d: %s (%s)\n", s.ID, s.Name, strings.ToLower(s.Status))
+	if len(s.Inputs) > 0 {
+		ins := make([]string, len(s.Inputs))
+		for i, in := range s.Inputs {
+			ins[i] = fmt.Sprint(in)
+		}
+		fmt.Fprintf(&b, "Inputs:    stages %s\n", strings.Join(ins, ", "))
+	}
+	fmt.Fprintf(&b, "Records:   %s read, %s written\n", formatCount(s.RecordsRead), formatCount(s.RecordsWritten))
+	fmt.Fprintf(&b, "Shuffle:   %s", formatBytes(s.ShuffleBytes))
+	if s.ShuffleSpilled > 0 {
+		fmt.Fprintf(&b, " (%s spilled to disk)", formatBytes(s.ShuffleSpilled))
+	}
+	fmt.Fprintf(&b, "\nWorkers:   %d of %d inputs done, %s\n", s.CompletedInputs, s.ParallelInputs, s.Duration.Round(time.Millisecond))
+	b.WriteString("\n           avg          max\n")
+	for _, r := range []struct {
+		name     string
+		avg, max float64
+	}{
+		{"Wait", s.WaitRatioAvg, s.WaitRatioMax},
+		{"Read", s.ReadRatioAvg, s.ReadRatioMax},
+		{"Compute", s.ComputeRatioAvg, s.ComputeRatioMax},
+		{"Write", s.WriteRatioAvg, s.WriteRatioMax},
+	} {
+		fmt.Fprintf(&b, "%-9s  %s %.2f  %s %.2f\n", r.name, ratioBar(r.avg), r.avg, ratioBar(r.max), r.max)
+	}
+	if s.Skewed() {
+		fmt.Fprintf(&b, "\nSkewed: the slowest worker computed %.1f× longer than average.\n", s.Skew())
+	}
+	if len(s.Steps) > 0 {
+		b.WriteString("\nSteps:\n")
+		for _, st := range s.Steps {
+			fmt.Fprintf(&b, "  %s\n", st)
+		}
+	}
+	return b.String()
+}
+
+// ratioBar draws a 0-1 ratio as a fixed-width bar.
+func ratioBar(r float64) string {
+	const width = 6
+	n := int(math.Round(math.Max(0, math.Min(1, r)) * width))
+	return strings.Repeat("█", n) + strings.Repeat("░", width-n)
+}
+
+// timelineChart plots active and pending work units and the slots in use
+// over the elapsed time of the query.
+func timelineChart(samples []ExecSample) chartData {
+	sorted := append([]ExecSample(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Elapsed < sorted[j].Elapsed })
+	active := chartSeries{Name: "active units"}
+	pending := chartSeries{Name: "pending units"}
+	slots := chartSeries{Name: "slots"}
+	var prev ExecSample
+	for i, s := range sorted {
+		x := s.Elapsed.Seconds()
+		active.Points = append(active.Points, chartPoint{X: x, Y: float64(s.ActiveUnits)})
+		pending.Points = append(pending.Points, chartPoint{X: x, Y: float64(s.PendingUnits)})
+		if i > 0 {
+			if dt := (s.Elapsed - prev.Elapsed).Milliseconds(); dt > 0 {
+				slots.Points = append(slots.Points, chartPoint{X: x, Y: float64(s.SlotMillis-prev.SlotMillis) / float64(dt)})
+			}
+		}
+		prev = s
+	}
+	return chartData{
+		Kind:   ChartLine,
+		XKind:  axisNumber,
+		XName:  "elapsed seconds",
+		Series: []chartSeries{active, pending, slots},
+	}
+}
+
+func formatCount(n int64) string {
+	switch {
+	case n >= 1e9:
+		return fmt.Sprintf("%.1fB", float64(n)/1e9)
+	case n >= 1e6:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1e4:
+		return fmt.Sprintf("%.1fK", float64(n)/1e3)
+	}
+	return fmt.Sprint(n)
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
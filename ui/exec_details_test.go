@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var execStages = []ExecStage{
+	{ID: 0, Name: "S00: Input"},
+	{ID: 1, Name: "S01: Input"},
+	{ID: 2, Name: "S02: Join+", Inputs: []int64{0, 1}, ComputeAvg: time.Second, ComputeMax: 8 * time.Second},
+	{ID: 3, Name: "S03: Output", Inputs: []int64{2, 0}, ComputeAvg: time.Second, ComputeMax: 2 * time.Second},
+	{ID: 4, Name: "S04: Bad", Inputs: []int64{99}},
+}
+
+func TestStageDepths(t *testing.T) {
+	got := stageDepths(execStages)
+	want := map[int64]int{0: 0, 1: 0, 2: 1, 3: 2, 4: 0}
+	for id, d := range want {
+		if got[id] != d {
+			t.Errorf("depth of stage %d = %d, want %d", id, got[id], d)
+		}
+	}
+
+	pos, size := stagePositions(execStages)
+	if pos[0].X != pos[1].X || pos[0].Y == pos[1].Y {
+		t.Errorf("stages of equal depth should stack: %v %v", pos[0], pos[1])
+	}
+	if pos[3].X <= pos[2].X || size.Width < pos[3].X+stageNodeWidth {
+		t.Errorf("readers should be right of inputs: %v, size %v", pos, size)
+	}
+}
+
+func TestStageSkew(t *testing.T) {
+	if !execStages[2].Skewed() || execStages[3].Skewed() || execStages[0].Skewed() {
+		t.Error("only the join should be skewed")
+	}
+	if s := mostSkewed(execStages); s == nil || s.ID != 2 {
+		t.Errorf("mostSkewed = %+v", s)
+	}
+	if d := execStages[2].describe(); !strings.Contains(d, "8.0× longer") {
+		t.Errorf("describe = %q", d)
+	}
+}
+
+func TestTimelineChart(t *testing.T) {
+	d := timelineChart([]ExecSample{
+		{Elapsed: 2 * time.Second, ActiveUnits: 1, SlotMillis: 5000},
+		{Elapsed: time.Second, ActiveUnits: 4, PendingUnits: 2, SlotMillis: 1000},
+	})
+	if len(d.Series) != 3 || len(d.Series[0].Points) != 2 || d.Series[0].Points[0].Y != 4 {
+		t.Fatalf("series = %+v", d.Series)
+	}
+	if slots := d.Series[2].Points; len(slots) != 1 || slots[0].Y != 4 {
+		t.Errorf("slots = %+v, want 4000 slot-ms over 1000 ms", slots)
+	}
+}
+
+func TestExecDetailsSetStats(t *testing.T) {
+	d := NewExecDetails()
+	d.SetStats(&ExecStats{StatementType: "SELECT", BytesProcessed: 3 << 20, Stages: execStages})
+	if !strings.Contains(d.summary.Text, "3.0 MiB processed") || d.graph.selected != 2 {
+		t.Errorf("summary = %q, selected = %d", d.summary.Text, d.graph.selected)
+	}
+	d.SetStats(nil)
+	if d.graph.stages != nil || d.graph.selected != -1 {
+		t.Error("nil stats should clear the plan")
+	}
+}
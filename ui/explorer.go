@@ -19,6 +19,7 @@ import (
 //   "p:<project>"
 //   "d:<project>/<dataset>"
 //   "t:<project>/<dataset>/<table>"
+//   "r:<project>/<dataset>/<routine>"
 
 func ProjectNodeID(project string) string          { return "p:" + project }
 func DatasetNodeID(project, dataset string) string { return fmt.Sprintf("d:%s/%s", project, dataset) }
@@ -26,6 +27,10 @@ func TableNodeID(project, dataset, table string) string {
 	return fmt.Sprintf("t:%s/%s/%s", project, dataset, table)
 }
 
+func RoutineNodeID(project, dataset, routine string) string {
+	return fmt.Sprintf("r:%s/%s/%s", project, dataset, routine)
+}
+
 func ParseNodeID(id string) (kind string, project, dataset, table string) {
 	if len(id) < 2 {
 		return "", "", "", ""
@@ -45,6 +50,29 @@ func ParseNodeID(id string) (kind string, project, dataset, table string) {
 	return
 }
 
+// TableAction is a change offered in a table's context menu.
+type TableAction string
+
+const (
+	TableDDL        TableAction = "ddl"
+	TableCopy       TableAction = "copy"
+	TableSnapshot   TableAction = "snapshot"
+	TableRename     TableAction = "rename"
+	TableExpiration TableAction = "expiration"
+	TableEdit       TableAction = "edit"
+	TableDelete     TableAction = "delete"
+)
+
+// DatasetAction is a change offered in a project's or dataset's context
+// menu.
+type DatasetAction string
+
+const (
+	DatasetCreate  DatasetAction = "create"
+	DatasetDetails DatasetAction = "details"
+	DatasetEdit    DatasetAction = "edit"
+)
+
 type LoadChildrenFunc func(nodeID string) ([]string, error)
 type OnTableSelectedFunc func(project, dataset, table string)
 
@@ -92,6 +120,18 @@ type Explorer struct {
 	OnProjectSelected func(project string) // callback when a project node is clicked (set in editor)
 	OnSearchProject   func(project string) // callback: load all datasets+tables for a project
 	OnChildrenChanged func()               // callback: children were loaded/cached (for autocomplete refresh)
+	// OnRoutineSelected shows a function or procedure listed under a
+	// dataset.
+	OnRoutineSelected func(project, dataset, routine string)
+
+	// OnUploadFile loads a local file into a table of the dataset; table is
+	// empty when chosen from the dataset's menu.
+	OnUploadFile func(project, dataset, table string)
+	// OnTableAction starts a change to a table chosen from its menu.
+	OnTableAction func(action TableAction, project, dataset, table string)
+	// OnDatasetAction starts a change to a dataset chosen from its menu;
+	// dataset is empty for DatasetCreate, chosen from the project's menu.
+	OnDatasetAction func(action DatasetAction, project, dataset string)
 
 	Container fyne.CanvasObject
 }
@@ -130,7 +170,7 @@ func NewExplorer() *Explorer {
 			icon := widget.NewIcon(theme.NavigateNextIcon())
 			label := canvas.NewText("template", color.White)
 			leftGroup := container.NewHBox(spacer, icon)
-			return container.NewBorder(nil, nil, leftGroup, nil, label)
+			return newExplorerRow(container.NewBorder(nil, nil, leftGroup, nil, label), e.selectNode, e.showNodeMenu)
 		},
 		func(id widget.ListItemID, obj fyne.CanvasObject) {
 			e.mu.Lock()
@@ -141,7 +181,9 @@ func NewExplorer() *Explorer {
 			node := e.visible[id]
 			e.mu.Unlock()
 
-			c := obj.(*fyne.Container)
+			row := obj.(*explorerRow)
+			row.nodeID = node.id
+			c := row.content
 			label := c.Objects[0].(*canvas.Text)
 			leftGroup := c.Objects[1].(*fyne.Container)
 			spacer := leftGroup.Objects[0].(*widget.Label)
@@ -177,6 +219,8 @@ func NewExplorer() *Explorer {
 				} else {
 					icon.SetResource(theme.NavigateNextIcon())
 				}
+			} else if strings.HasPrefix(node.id, "r:") {
+				icon.SetResource(theme.ComputerIcon())
 			} else {
 				icon.SetResource(theme.DocumentIcon())
 			}
@@ -232,8 +276,11 @@ func NewExplorer() *Explorer {
 			e.toggleBranch(node.id)
 		} else {
 			kind, project, dataset, table := ParseNodeID(node.id)
-			if kind == "t" && e.OnTableSelected != nil {
+			switch {
+			case kind == "t" && e.OnTableSelected != nil:
 				e.OnTableSelected(project, dataset, table)
+			case kind == "r" && e.OnRoutineSelected != nil:
+				e.OnRoutineSelected(project, dataset, table)
 			}
 		}
 	}
@@ -243,6 +290,122 @@ func NewExplorer() *Explorer {
 	return e
 }
 
+// nodeMenu returns the context menu items of a node, or nil if it has none.
+func (e *Explorer) nodeMenu(nodeID string) []*fyne.MenuItem {
+	kind, project, dataset, table := ParseNodeID(nodeID)
+	var items []*fyne.MenuItem
+	if e.OnDatasetAction != nil {
+		action := func(a DatasetAction) func() {
+			return func() { e.OnDatasetAction(a, project, dataset) }
+		}
+		switch kind {
+		case "p":
+			items = append(items, fyne.NewMenuItem("Create Dataset…", action(DatasetCreate)))
+		case "d":
+			items = append(items,
+				fyne.NewMenuItem("Dataset Info…", action(DatasetDetails)),
+				fyne.NewMenuItem("Edit Dataset…", action(DatasetEdit)),
+			)
+		}
+	}
+	switch kind {
+	case "d", "t":
+		if e.OnUploadFile != nil {
+			if len(items) > 0 {
+				items = append(items, fyne.NewMenuItemSeparator())
+			}
+			label := "Upload File…"
+			if kind == "t" {
+				label = "Upload File into Table…"
+			}
+			items = append(items, fyne.NewMenuItem(label, func() { e.OnUploadFile(project, dataset, table) }))
+		}
+	}
+	if kind == "t" && e.OnTableAction != nil {
+		action := func(a TableAction) func() {
+			return func() { e.OnTableAction(a, project, dataset, table) }
+		}
+		if len(items) > 0 {
+			items = append(items, fyne.NewMenuItemSeparator())
+		}
+		items = append(items,
+			fyne.NewMenuItem("Copy CREATE Statement", action(TableDDL)),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Copy Table…", action(TableCopy)),
+			fyne.NewMenuItem("Snapshot or Clone…", action(TableSnapshot)),
+			fyne.NewMenuItem("Rename…", action(TableRename)),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Set Expiration…", action(TableExpiration)),
+			fyne.NewMenuItem("Edit Description and Labels…", action(TableEdit)),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Delete Table…", action(TableDelete)),
+		)
+	}
+	return items
+}
+
+func (e *Explorer) showNodeMenu(nodeID string, pos fyne.Position) {
+	items := e.nodeMenu(nodeID)
+	if len(items) == 0 {
+		return
+	}
+	c := fyne.CurrentApp().Driver().CanvasForObject(e.list)
+	if c == nil {
+		return
+	}
+	widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", items...), c, pos)
+}
+
+// selectNode selects a visible node as if its list row was tapped.
+func (e *Explorer) selectNode(nodeID string) {
+	e.mu.Lock()
+	idx := -1
+	for i, n := range e.visible {
+		if n.id == nodeID {
+			idx = i
+			break
+		}
+	}
+	e.mu.Unlock()
+	if idx >= 0 {
+		e.list.Select(idx)
+	}
+}
+
+// explorerRow is a row of the explorer list. Secondary tap opens the node's
+// context menu. The row handles primary taps too, passing them on to the
+// list, because the driver delivers taps only to the topmost tappable
+// object.
+type explorerRow struct {
+	widget.BaseWidget
+	content *fyne.Container
+	nodeID  string
+	onTap   func(nodeID string)
+	onMenu  func(nodeID string, pos fyne.Position)
+}
+
+func newExplorerRow(content *fyne.Container, onTap func(nodeID string), onMenu func(nodeID string, pos fyne.Position)) *explorerRow {
+	r := &explorerRow{content: content, onTap: onTap, onMenu: onMenu}
+	r.ExtendBaseWidget(r)
+	return r
+}
+
+func (r *explorerRow) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(r.content)
+}
+
+func (r *explorerRow) Tapped(*fyne.PointEvent) {
+	if r.nodeID != "" && r.onTap != nil {
+		r.onTap(r.nodeID)
+	}
+}
+
+func (r *explorerRow) TappedSecondary(ev *fyne.PointEvent) {
+	if r.nodeID != "" && r.onMenu != nil {
+		r.onMenu(r.nodeID, ev.AbsolutePosition)
+	}
+}
+
 // rebuildVisible reconstructs the visible list from the three data sources,
 // applying search filter. Must NOT hold e.mu when calling.
 func (e *Explorer) rebuildVisible() {
@@ -473,9 +636,9 @@ func (e *Explorer) appendExpandedChildren(nodes []explorerNode, parentID string,
 }
 
 // CacheProjectData is called after parallel BQ loading completes.
-// It populates children caches for the project's datasets and tables,
-// clears searchInProgress, and triggers rebuildVisible.
-func (e *Explorer) CacheProjectData(project string, datasets map[string][]string) {
+// It populates children caches for the project's datasets, their tables
+// and their routines, clears searchInProgress, and triggers rebuildVisible.
+func (e *Explorer) CacheProjectData(project string, datasets, routines map[string][]string) {
 	e.mu.Lock()
 	pid := ProjectNodeID(project)
 
@@ -498,13 +661,20 @@ func (e *Explorer) CacheProjectData(project string, datasets map[string][]string
 
 		// Build table child nodes for each dataset
 		tables := datasets[ds]
-		tblNodes := make([]explorerNode, len(tables))
-		for j, tbl := range tables {
-			tblNodes[j] = explorerNode{
+		tblNodes := make([]explorerNode, 0, len(tables)+len(routines[ds]))
+		for _, tbl := range tables {
+			tblNodes = append(tblNodes, explorerNode{
 				id:    TableNodeID(project, ds, tbl),
 				label: tbl,
 				depth: 2,
-			}
+			})
+		}
+		for _, r := range routines[ds] {
+			tblNodes = append(tblNodes, explorerNode{
+				id:    RoutineNodeID(project, ds, r),
+				label: r,
+				depth: 2,
+			})
 		}
 		e.children[did] = tblNodes
 	}
@@ -617,8 +787,11 @@ func (e *Explorer) AllCachedNames() (datasets []string, tables []string) {
 				}
 			}
 		case "d":
-			// Children are tables
+			// Children are tables, then routines
 			for _, n := range childNodes {
+				if strings.HasPrefix(n.id, "r:") {
+					continue
+				}
 				if !seenTbl[n.label] {
 					seenTbl[n.label] = true
 					tables = append(tables, n.label)
@@ -679,9 +852,11 @@ func (e *Explorer) CachedHierarchy() map[string]map[string][]string {
 				dsMap[dataset] = nil
 				continue
 			}
-			tables := make([]string, len(tblNodes))
-			for i, t := range tblNodes {
-				tables[i] = t.label
+			tables := make([]string, 0, len(tblNodes))
+			for _, t := range tblNodes {
+				if !strings.HasPrefix(t.id, "r:") {
+					tables = append(tables, t.label)
+				}
 			}
 			dsMap[dataset] = tables
 		}
@@ -771,30 +946,7 @@ func (e *Explorer) toggleBranch(id string) {
 
 		log.Printf("explorer: loaded %d children for %s", len(childIDs), id)
 
-		// Build child nodes
-		childNodes := make([]explorerNode, len(childIDs))
-		for i, cid := range childIDs {
-			ckind, _, dataset, table := ParseNodeID(cid)
-			label := cid
-			isBranch := false
-			depth := 0
-			switch ckind {
-			case "d":
-				label = dataset
-				isBranch = true
-				depth = 1
-			case "t":
-				label = table
-				depth = 2
-			}
-			childNodes[i] = explorerNode{
-				id:       cid,
-				label:    label,
-				depth:    depth,
-				isBranch: isBranch,
-			}
-		}
-
+		childNodes := childNodesFromIDs(childIDs)
 		e.children[id] = childNodes
 
 		// Find the node again and expand
@@ -814,6 +966,58 @@ func (e *Explorer) toggleBranch(id string) {
 	}()
 }
 
+// ReloadChildren reloads the children of a loaded node, e.g. after a table
+// was created or deleted, keeping expanded nodes expanded.
+func (e *Explorer) ReloadChildren(id string) {
+	e.mu.Lock()
+	_, cached := e.children[id]
+	e.mu.Unlock()
+	if !cached || e.LoadChildren == nil {
+		return
+	}
+	go func() {
+		childIDs, err := e.LoadChildren(id)
+		if err != nil {
+			log.Printf("explorer: error reloading children for %s: %v", id, err)
+			return
+		}
+		e.mu.Lock()
+		e.children[id] = childNodesFromIDs(childIDs)
+		e.mu.Unlock()
+		e.rebuildVisible()
+		if e.OnChildrenChanged != nil {
+			e.OnChildrenChanged()
+		}
+	}()
+}
+
+// childNodesFromIDs builds the nodes for loaded child IDs.
+func childNodesFromIDs(childIDs []string) []explorerNode {
+	childNodes := make([]explorerNode, len(childIDs))
+	for i, cid := range childIDs {
+		ckind, _, dataset, table := ParseNodeID(cid)
+		label := cid
+		isBranch := false
+		depth := 0
+		switch ckind {
+		case "d":
+			label = dataset
+			isBranch = true
+			depth = 1
+		case "t", "r":
+			label = table
+			depth = 2
+		}
+		childNodes[i] = explorerNode{
+			id:       cid,
+			label:    label,
+			depth:    depth,
+			isBranch: isBranch,
+		}
+	}
+	return childNodes
+}
+
 // countDescendants returns how many items after idx belong as descendants.
 // Must be called with e.mu held.
 func (e *Explorer) countDescendants(idx int) int {
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"fyne.io/fyne/v2/test"
@@ -278,3 +279,167 @@ func TestAllKnownProjects(t *testing.T) {
 		}
 	}
 }
+
+func TestNodeMenuUploadFile(t *testing.T) {
+	e := NewExplorer()
+	if items := e.nodeMenu(DatasetNodeID("p", "ds")); len(items) != 0 {
+		t.Fatalf("expected no menu without callbacks, got %d items", len(items))
+	}
+
+	var got []string
+	e.OnUploadFile = func(project, dataset, table string) {
+		got = []string{project, dataset, table}
+	}
+	if items := e.nodeMenu(ProjectNodeID("p")); len(items) != 0 {
+		t.Errorf("project menu has %d items, want none", len(items))
+	}
+	if items := e.nodeMenu(headerAll); len(items) != 0 {
+		t.Errorf("header menu has %d items, want none", len(items))
+	}
+
+	items := e.nodeMenu(DatasetNodeID("p", "ds"))
+	if len(items) != 1 {
+		t.Fatalf("dataset menu has %d items, want 1", len(items))
+	}
+	items[0].Action()
+	if got == nil || got[0] != "p" || got[1] != "ds" || got[2] != "" {
+		t.Errorf("OnUploadFile got %q", got)
+	}
+
+	items = e.nodeMenu(TableNodeID("p", "ds", "lookup"))
+	items[0].Action()
+	if got[2] != "lookup" {
+		t.Errorf("table upload got %q", got)
+	}
+}
+
+func TestExplorerRowTapSelectsNode(t *testing.T) {
+	e := NewExplorer()
+	e.mu.Lock()
+	e.favProjects = []string{"proj-a"}
+	e.children[ProjectNodeID("proj-a")] = []explorerNode{
+		{id: DatasetNodeID("proj-a", "raw"), label: "raw", depth: 1, isBranch: true},
+	}
+	e.children[DatasetNodeID("proj-a", "raw")] = []explorerNode{
+		{id: TableNodeID("proj-a", "raw", "orders"), label: "orders", depth: 2},
+	}
+	e.searchFilter = "orders"
+	e.mu.Unlock()
+	e.rebuildVisible()
+
+	var selected string
+	e.OnTableSelected = func(project, dataset, table string) {
+		selected = project + "." + dataset + "." + table
+	}
+	row := newExplorerRow(nil, e.selectNode, nil)
+	row.nodeID = TableNodeID("proj-a", "raw", "orders")
+	row.Tapped(nil)
+	if selected != "proj-a.raw.orders" {
+		t.Errorf("tapping the row selected %q", selected)
+	}
+}
+
+func TestNodeMenuTableActions(t *testing.T) {
+	e := NewExplorer()
+	var got []string
+	e.OnTableAction = func(action TableAction, project, dataset, table string) {
+		got = append(got, string(action)+" "+project+"."+dataset+"."+table)
+	}
+	if items := e.nodeMenu(DatasetNodeID("p", "ds")); len(items) != 0 {
+		t.Errorf("dataset menu has %d items, want none", len(items))
+	}
+
+	var labels []string
+	for _, item := range e.nodeMenu(TableNodeID("p", "ds", "t")) {
+		if item.IsSeparator {
+			continue
+		}
+		labels = append(labels, item.Label)
+		item.Action()
+	}
+	if len(labels) != 7 || labels[len(labels)-1] != "Delete Table…" {
+		t.Errorf("table menu = %q", labels)
+	}
+	want := []string{"ddl p.ds.t", "copy p.ds.t", "snapshot p.ds.t", "rename p.ds.t", "expiration p.ds.t", "edit p.ds.t", "delete p.ds.t"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("actions = %q, want %q", got, want)
+	}
+}
+
+func TestNodeMenuDatasetActions(t *testing.T) {
+	e := NewExplorer()
+	var got []string
+	e.OnDatasetAction = func(action DatasetAction, project, dataset string) {
+		got = append(got, string(action)+" "+project+":"+dataset)
+	}
+	e.OnUploadFile = func(project, dataset, table string) {}
+
+	items := e.nodeMenu(ProjectNodeID("p"))
+	if len(items) != 1 || items[0].Label != "Create Dataset…" {
+		t.Fatalf("project menu = %d items", len(items))
+	}
+	items[0].Action()
+
+	var labels []string
+	for _, item := range e.nodeMenu(DatasetNodeID("p", "ds")) {
+		if item.IsSeparator {
+			continue
+		}
+		labels = append(labels, item.Label)
+		if item.Label != "Upload File…" {
+			item.Action()
+		}
+	}
+	if strings.Join(labels, ",") != "Dataset Info…,Edit Dataset…,Upload File…" {
+		t.Errorf("dataset menu = %q", labels)
+	}
+	want := []string{"create p:", "details p:ds", "edit p:ds"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("actions = %q, want %q", got, want)
+	}
+	for _, item := range e.nodeMenu(TableNodeID("p", "ds", "t")) {
+		if strings.Contains(item.Label, "Dataset") {
+			t.Errorf("table menu has dataset item %q", item.Label)
+		}
+	}
+}
+
+func TestExplorerRoutineNodes(t *testing.T) {
+	e := NewExplorer()
+	e.CacheProjectData("p", map[string][]string{"ds": {"orders"}}, map[string][]string{"ds": {"add_tax"}})
+
+	if h := e.CachedHierarchy(); len(h["p"]["ds"]) != 1 || h["p"]["ds"][0] != "orders" {
+		t.Errorf("hierarchy tables = %v, want only orders", h["p"]["ds"])
+	}
+	if _, tables := e.AllCachedNames(); len(tables) != 1 || tables[0] != "orders" {
+		t.Errorf("cached tables = %v, want only orders", tables)
+	}
+
+	e.mu.Lock()
+	children := e.children[DatasetNodeID("p", "ds")]
+	e.mu.Unlock()
+	if len(children) != 2 || children[1].id != RoutineNodeID("p", "ds", "add_tax") || children[1].label != "add_tax" {
+		t.Fatalf("dataset children = %+v", children)
+	}
+	if kind, project, dataset, routine := ParseNodeID(children[1].id); kind != "r" || project != "p" || dataset != "ds" || routine != "add_tax" {
+		t.Errorf("ParseNodeID = %q %q %q %q", kind, project, dataset, routine)
+	}
+
+	e.mu.Lock()
+	e.favProjects = []string{"p"}
+	e.searchFilter = "add_"
+	e.mu.Unlock()
+	e.rebuildVisible()
+
+	var selected string
+	e.OnRoutineSelected = func(project, dataset, routine string) {
+		selected = project + "." + dataset + "." + routine
+	}
+	e.OnTableSelected = func(project, dataset, table string) {
+		t.Errorf("routine selected as a table")
+	}
+	e.selectNode(RoutineNodeID("p", "ds", "add_tax"))
+	if selected != "p.ds.add_tax" {
+		t.Errorf("selecting the routine gave %q", selected)
+	}
+}
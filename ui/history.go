@@ -7,6 +7,7 @@ import (
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
@@ -18,17 +19,37 @@ type HistoryEntry struct {
 	Duration  time.Duration
 	RowCount  int64
 	Error     string
+
+	SnapshotName string // latest snapshot saved from this run, if any
+}
+
+// SnapshotInfo describes a saved result snapshot.
+type SnapshotInfo struct {
+	ID        int64
+	Name      string
+	SQL       string
+	Project   string
+	CreatedAt time.Time
+	RowCount  int64
 }
 
 type OnHistorySelectFunc func(sql string)
 
 type History struct {
-	list    *widget.List
-	entries []HistoryEntry
+	list      *widget.List
+	entries   []HistoryEntry
+	snapList  *widget.List
+	snapshots []SnapshotInfo
+	body      *fyne.Container
 
 	OnSelect  OnHistorySelectFunc
 	OnRefresh func()
 
+	// OnOpenSnapshot reopens a saved snapshot in the results grid.
+	OnOpenSnapshot func(id int64)
+	// OnDeleteSnapshot deletes a saved snapshot.
+	OnDeleteSnapshot func(id int64)
+
 	Container fyne.CanvasObject
 }
 
@@ -44,7 +65,17 @@ func NewHistory() *History {
 		h.entries = nil
 		h.list.Refresh()
 	})
-	toolbar := container.NewHBox(refreshBtn, clearBtn)
+	mode := widget.NewRadioGroup([]string{"Queries", "Snapshots"}, func(m string) {
+		if m == "Snapshots" {
+			h.body.Objects = []fyne.CanvasObject{h.snapList}
+		} else {
+			h.body.Objects = []fyne.CanvasObject{h.list}
+		}
+		h.body.Refresh()
+	})
+	mode.Horizontal = true
+	mode.Required = true
+	toolbar := container.NewHBox(refreshBtn, clearBtn, mode)
 
 	h.list = widget.NewList(
 		func() int { return len(h.entries) },
@@ -65,7 +96,11 @@ func NewHistory() *History {
 			if e.Error != "" {
 				label.SetText(fmt.Sprintf("[%s] ERR: %s", ts, sql))
 			} else {
-				label.SetText(fmt.Sprintf("[%s] %s (%d rows, %s)", ts, sql, e.RowCount, e.Duration.Round(time.Millisecond)))
+				text := fmt.Sprintf("[%s] %s (%d rows, %s)", ts, sql, e.RowCount, e.Duration.Round(time.Millisecond))
+				if e.SnapshotName != "" {
+					text += fmt.Sprintf(" · snapshot %q", e.SnapshotName)
+				}
+				label.SetText(text)
 			}
 		},
 	)
@@ -77,10 +112,50 @@ func NewHistory() *History {
 		h.list.UnselectAll()
 	}
 
-	h.Container = container.NewBorder(toolbar, nil, nil, nil, h.list)
+	h.snapList = widget.NewList(
+		func() int { return len(h.snapshots) },
+		func() fyne.CanvasObject {
+			del := widget.NewButtonWithIcon("", theme.DeleteIcon(), nil)
+			return container.NewBorder(nil, nil, nil, del, widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(h.snapshots) {
+				return
+			}
+			sn := h.snapshots[id]
+			row := obj.(*fyne.Container)
+			label := row.Objects[0].(*widget.Label)
+			del := row.Objects[1].(*widget.Button)
+			label.SetText(fmt.Sprintf("[%s] %s (%d rows, %s)", sn.CreatedAt.Format("2006-01-02 15:04"),
+				sn.Name, sn.RowCount, sn.Project))
+			del.OnTapped = func() {
+				if h.OnDeleteSnapshot != nil {
+					h.OnDeleteSnapshot(sn.ID)
+				}
+			}
+		},
+	)
+	h.snapList.OnSelected = func(id widget.ListItemID) {
+		if id < len(h.snapshots) && h.OnOpenSnapshot != nil {
+			h.OnOpenSnapshot(h.snapshots[id].ID)
+		}
+		h.snapList.UnselectAll()
+	}
+
+	h.body = container.NewStack(h.list)
+	mode.SetSelected("Queries")
+	h.Container = container.NewBorder(toolbar, nil, nil, nil, h.body)
 	return h
 }
 
+// SetSnapshots replaces the list of saved snapshots.
+func (h *History) SetSnapshots(snapshots []SnapshotInfo) {
+	fyne.Do(func() {
+		h.snapshots = snapshots
+		h.snapList.Refresh()
+	})
+}
+
 func (h *History) SetEntries(entries []HistoryEntry) {
 	h.entries = entries
 	fyne.Do(func() {
@@ -0,0 +1,257 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ProjectJob is a job listed from a project's INFORMATION_SCHEMA, run by
+// any client.
+type ProjectJob struct {
+	ProjectID     string
+	JobID         string
+	Location      string
+	User          string
+	JobType       string
+	StatementType string
+	State         string
+	Query         string
+	Created       time.Time
+	Duration      time.Duration
+	BytesBilled   int64
+	SlotMillis    int64
+	CacheHit      bool
+	Error         string
+
+	// Destination table holding the job's results, if any.
+	DestProject, DestDataset, DestTable string
+}
+
+// HasResults reports whether the job has a destination table to read
+// results from.
+func (j ProjectJob) HasResults() bool { return j.DestTable != "" }
+
+var jobBrowserColumns = []string{"Created", "State", "User", "Type", "Billed", "Slot Time", "Duration", "Job ID", "Query / Error"}
+
+var jobBrowserWidths = []float32{140, 70, 180, 80, 90, 90, 80, 220, 500}
+
+// JobRegions are common INFORMATION_SCHEMA region qualifiers offered by the
+// job browser; any other region can be typed.
+var JobRegions = []string{"us", "eu", "us-central1", "us-east1", "us-west1", "europe-west1", "europe-west2", "europe-west3", "asia-northeast1", "asia-southeast1", "australia-southeast1"}
+
+var jobBrowserDays = []string{"1 day", "7 days", "30 days", "180 days"}
+
+// JobBrowser lists recent jobs of a project from INFORMATION_SCHEMA.JOBS,
+// including those run outside this app.
+type JobBrowser struct {
+	project    *widget.SelectEntry
+	region     *widget.SelectEntry
+	scope      *widget.Select
+	days       *widget.Select
+	table      *widget.Table
+	status     *widget.Label
+	openBtn    *widget.Button
+	resultsBtn *widget.Button
+	jobs       []ProjectJob
+	selected   int // index into jobs, -1 for none
+
+	// OnLoad is called to list jobs; the app answers with SetJobs.
+	OnLoad func(project, region string, allUsers bool, days int)
+	// OnOpenSQL opens a job's query in a new editor tab.
+	OnOpenSQL func(job ProjectJob)
+	// OnLoadResults shows a job's cached results from its destination table.
+	OnLoadResults func(job ProjectJob)
+
+	Container fyne.CanvasObject
+}
+
+func NewJobBrowser() *JobBrowser {
+	b := &JobBrowser{
+		project:  widget.NewSelectEntry(nil),
+		region:   widget.NewSelectEntry(JobRegions),
+		scope:    widget.NewSelect([]string{"My jobs", "All users"}, nil),
+		days:     widget.NewSelect(jobBrowserDays, nil),
+		status:   widget.NewLabel("List recent jobs of a project from any client, such as the console, bq or scheduled queries."),
+		selected: -1,
+	}
+	b.project.SetPlaceHolder("project")
+	b.region.SetText("us")
+	b.scope.SetSelected("My jobs")
+	b.days.SetSelected("7 days")
+	b.status.Truncation = fyne.TextTruncateEllipsis
+
+	b.table = widget.NewTableWithHeaders(
+		func() (int, int) { return len(b.jobs), len(jobBrowserColumns) },
+		func() fyne.CanvasObject {
+			l := widget.NewLabel("")
+			l.Truncation = fyne.TextTruncateEllipsis
+			return l
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			if id.Row >= len(b.jobs) {
+				return
+			}
+			j := b.jobs[id.Row]
+			label.Importance = widget.MediumImportance
+			if j.Error != "" {
+				label.Importance = widget.DangerImportance
+			}
+			label.SetText(jobBrowserCell(j, id.Col))
+		},
+	)
+	b.table.UpdateHeader = func(id widget.TableCellID, template fyne.CanvasObject) {
+		label := template.(*widget.Label)
+		if id.Row < 0 && id.Col >= 0 && id.Col < len(jobBrowserColumns) {
+			label.SetText(jobBrowserColumns[id.Col])
+		} else if id.Col < 0 && id.Row >= 0 {
+			label.SetText(fmt.Sprintf("%d", id.Row+1))
+		}
+	}
+	for i, w := range jobBrowserWidths {
+		b.table.SetColumnWidth(i, w)
+	}
+	b.table.OnSelected = func(id widget.TableCellID) {
+		if id.Row >= 0 && id.Row < len(b.jobs) {
+			b.selected = id.Row
+			b.updateButtons()
+		}
+	}
+
+	b.openBtn = widget.NewButtonWithIcon("Open SQL", theme.Icon(theme.IconNameDocumentCreate), func() {
+		if j, ok := b.Selected(); ok && b.OnOpenSQL != nil {
+			b.OnOpenSQL(j)
+		}
+	})
+	b.resultsBtn = widget.NewButtonWithIcon("Load Results", theme.Icon(theme.IconNameDownload), func() {
+		if j, ok := b.Selected(); ok && b.OnLoadResults != nil {
+			b.OnLoadResults(j)
+		}
+	})
+	b.updateButtons()
+
+	refreshBtn := widget.NewButtonWithIcon("List Jobs", theme.Icon(theme.IconNameViewRefresh), b.Load)
+	filters := container.NewHBox(
+		widget.NewLabel("Region:"), container.NewGridWrap(fyne.NewSize(150, b.region.MinSize().Height), b.region),
+		b.scope, b.days, refreshBtn,
+	)
+	top := container.NewBorder(nil, nil, widget.NewLabel("Project:"), filters, b.project)
+	bottom := container.NewBorder(nil, nil, nil, container.NewHBox(b.openBtn, b.resultsBtn), b.status)
+	b.Container = container.NewBorder(top, bottom, nil, nil, b.table)
+	return b
+}
+
+// Load asks the app to list jobs with the current filters.
+func (b *JobBrowser) Load() {
+	if b.OnLoad == nil {
+		return
+	}
+	days := 7
+	fmt.Sscanf(b.days.Selected, "%d", &days)
+	b.OnLoad(strings.TrimSpace(b.project.Text), strings.TrimSpace(b.region.Text), b.scope.Selected == "All users", days)
+}
+
+// SetProjects sets the projects offered in the project box, and selects
+// the first one when none is entered yet.
+func (b *JobBrowser) SetProjects(projects []string) {
+	fyne.Do(func() {
+		b.project.SetOptions(projects)
+		if b.project.Text == "" && len(projects) > 0 {
+			b.project.SetText(projects[0])
+		}
+	})
+}
+
+// SetProject enters project in the project box.
+func (b *JobBrowser) SetProject(project string) {
+	fyne.Do(func() { b.project.SetText(project) })
+}
+
+// SetRegion enters region in the region box.
+func (b *JobBrowser) SetRegion(region string) {
+	fyne.Do(func() { b.region.SetText(region) })
+}
+
+// SetJobs shows listed jobs, newest first.
+func (b *JobBrowser) SetJobs(jobs []ProjectJob, status string) {
+	fyne.Do(func() {
+		b.jobs = jobs
+		b.selected = -1
+		b.table.UnselectAll()
+		b.table.Refresh()
+		b.status.SetText(status)
+		b.updateButtons()
+	})
+}
+
+// SetStatus shows progress or an error below the table.
+func (b *JobBrowser) SetStatus(text string) {
+	fyne.Do(func() { b.status.SetText(text) })
+}
+
+// Selected returns the selected job.
+func (b *JobBrowser) Selected() (ProjectJob, bool) {
+	if b.selected < 0 || b.selected >= len(b.jobs) {
+		return ProjectJob{}, false
+	}
+	return b.jobs[b.selected], true
+}
+
+func (b *JobBrowser) updateButtons() {
+	j, ok := b.Selected()
+	if ok && j.Query != "" {
+		b.openBtn.Enable()
+	} else {
+		b.openBtn.Disable()
+	}
+	if ok && j.HasResults() && j.Error == "" {
+		b.resultsBtn.Enable()
+	} else {
+		b.resultsBtn.Disable()
+	}
+}
+
+func jobBrowserCell(j ProjectJob, col int) string {
+	switch col {
+	case 0:
+		return j.Created.Local().Format("2006-01-02 15:04:05")
+	case 1:
+		if j.Error != "" {
+			return "FAILED"
+		}
+		return j.State
+	case 2:
+		return j.User
+	case 3:
+		if j.StatementType != "" {
+			return j.StatementType
+		}
+		return j.JobType
+	case 4:
+		if j.CacheHit {
+			return "cached"
+		}
+		return formatBytes(j.BytesBilled)
+	case 5:
+		return (time.Duration(j.SlotMillis) * time.Millisecond).Round(100 * time.Millisecond).String()
+	case 6:
+		if j.Duration <= 0 {
+			return ""
+		}
+		return j.Duration.Round(100 * time.Millisecond).String()
+	case 7:
+		return j.JobID
+	case 8:
+		if j.Error != "" {
+			return j.Error
+		}
+		return strings.Join(strings.Fields(j.Query), " ")
+	}
+	return ""
+}
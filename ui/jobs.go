@@ -0,0 +1,215 @@
+package ui
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+type JobState int
+
+const (
+	JobRunning JobState = iota
+	JobDone
+	JobFailed
+)
+
+// JobEntry is one background operation started in this session, such as
+// saving a result to a table.
+type JobEntry struct {
+	ID       int
+	Title    string
+	Detail   string // latest progress message or result summary
+	State    JobState
+	Started  time.Time
+	Finished time.Time
+
+	cancel func()
+}
+
+// Elapsed returns how long the job ran, or has been running.
+func (j JobEntry) Elapsed() time.Duration {
+	if j.State == JobRunning {
+		return time.Since(j.Started)
+	}
+	return j.Finished.Sub(j.Started)
+}
+
+// Jobs lists background jobs started in this session, newest first, and
+// browses a project's job history. Its methods are safe to call from any
+// goroutine.
+type Jobs struct {
+	list *widget.List
+	body *fyne.Container
+
+	// Browser lists jobs from a project's INFORMATION_SCHEMA.
+	Browser *JobBrowser
+	// Audit lists changes made to tables and datasets.
+	Audit *AuditLog
+
+	mu      sync.Mutex
+	entries []*JobEntry
+	nextID  int
+
+	Container fyne.CanvasObject
+}
+
+func NewJobs() *Jobs {
+	j := &Jobs{Browser: NewJobBrowser(), Audit: NewAuditLog()}
+
+	clearBtn := widget.NewButton("Clear Finished", j.ClearFinished)
+
+	j.list = widget.NewList(
+		func() int {
+			j.mu.Lock()
+			defer j.mu.Unlock()
+			return len(j.entries)
+		},
+		func() fyne.CanvasObject {
+			icon := widget.NewIcon(nil)
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			cancelBtn := widget.NewButtonWithIcon("", theme.Icon(theme.IconNameCancel), nil)
+			return container.NewBorder(nil, nil, icon, cancelBtn, label)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			j.mu.Lock()
+			if id >= len(j.entries) {
+				j.mu.Unlock()
+				return
+			}
+			e := *j.entries[id]
+			j.mu.Unlock()
+
+			row := obj.(*fyne.Container)
+			label := row.Objects[0].(*widget.Label)
+			icon := row.Objects[1].(*widget.Icon)
+			cancelBtn := row.Objects[2].(*widget.Button)
+
+			switch e.State {
+			case JobRunning:
+				icon.SetResource(theme.Icon(theme.IconNameViewRefresh))
+			case JobDone:
+				icon.SetResource(theme.Icon(theme.IconNameConfirm))
+			case JobFailed:
+				icon.SetResource(theme.Icon(theme.IconNameError))
+			}
+			text := fmt.Sprintf("[%s] %s (%s)", e.Started.Format("15:04:05"), e.Title, e.Elapsed().Round(time.Second))
+			if e.Detail != "" {
+				text += " — " + e.Detail
+			}
+			label.SetText(text)
+
+			if e.State == JobRunning && e.cancel != nil {
+				cancelBtn.OnTapped = e.cancel
+				cancelBtn.Show()
+			} else {
+				cancelBtn.OnTapped = nil
+				cancelBtn.Hide()
+			}
+		},
+	)
+
+	session := container.NewBorder(container.NewHBox(clearBtn), nil, nil, nil, j.list)
+	j.body = container.NewStack(session)
+	mode := widget.NewRadioGroup([]string{"This Session", "Project History", "Audit Log"}, func(m string) {
+		switch m {
+		case "Project History":
+			j.body.Objects = []fyne.CanvasObject{j.Browser.Container}
+		case "Audit Log":
+			j.body.Objects = []fyne.CanvasObject{j.Audit.Container}
+			if j.Audit.OnRefresh != nil {
+				j.Audit.OnRefresh()
+			}
+		default:
+			j.body.Objects = []fyne.CanvasObject{session}
+		}
+		j.body.Refresh()
+	})
+	mode.Horizontal = true
+	mode.Required = true
+	mode.SetSelected("This Session")
+
+	j.Container = container.NewBorder(mode, nil, nil, nil, j.body)
+	return j
+}
+
+// Start adds a running job and returns its ID. cancel, if non-nil, is
+// offered as a cancel button while the job runs.
+func (j *Jobs) Start(title string, cancel func()) int {
+	j.mu.Lock()
+	j.nextID++
+	e := &JobEntry{ID: j.nextID, Title: title, State: JobRunning, Started: time.Now(), cancel: cancel}
+	j.entries = append([]*JobEntry{e}, j.entries...)
+	j.mu.Unlock()
+	j.refresh()
+	return e.ID
+}
+
+// SetProgress updates the detail text of a running job.
+func (j *Jobs) SetProgress(id int, detail string) {
+	j.update(id, func(e *JobEntry) { e.Detail = detail })
+}
+
+// Finish marks a job as done, or failed when err is non-nil.
+func (j *Jobs) Finish(id int, err error, detail string) {
+	j.update(id, func(e *JobEntry) {
+		e.Finished = time.Now()
+		e.cancel = nil
+		if err != nil {
+			e.State = JobFailed
+			e.Detail = err.Error()
+			return
+		}
+		e.State = JobDone
+		e.Detail = detail
+	})
+}
+
+// Entries returns a copy of the listed jobs, newest first.
+func (j *Jobs) Entries() []JobEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	out := make([]JobEntry, len(j.entries))
+	for i, e := range j.entries {
+		out[i] = *e
+	}
+	return out
+}
+
+// ClearFinished removes jobs that are no longer running.
+func (j *Jobs) ClearFinished() {
+	j.mu.Lock()
+	kept := j.entries[:0]
+	for _, e := range j.entries {
+		if e.State == JobRunning {
+			kept = append(kept, e)
+		}
+	}
+	j.entries = kept
+	j.mu.Unlock()
+	j.refresh()
+}
+
+func (j *Jobs) update(id int, fn func(e *JobEntry)) {
+	j.mu.Lock()
+	for _, e := range j.entries {
+		if e.ID == id {
+			fn(e)
+			break
+		}
+	}
+	j.mu.Unlock()
+	j.refresh()
+}
+
+func (j *Jobs) refresh() {
+	fyne.Do(func() {
+		j.list.Refresh()
+	})
+}
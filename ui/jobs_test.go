@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestJobs_Lifecycle(t *testing.T) {
+	j := NewJobs()
+	first := j.Start("Save to a.b.c", func() {})
+	second := j.Start("Save to a.b.d", nil)
+
+	entries := j.Entries()
+	if len(entries) != 2 || entries[0].ID != second || entries[1].ID != first {
+		t.Fatalf("expected newest job first, got %+v", entries)
+	}
+
+	j.SetProgress(first, "job abc running")
+	j.Finish(first, nil, "42 rows")
+	j.Finish(second, errors.New("access denied"), "")
+
+	entries = j.Entries()
+	if entries[1].State != JobDone || entries[1].Detail != "42 rows" {
+		t.Errorf("first job = %+v, want done with detail", entries[1])
+	}
+	if entries[0].State != JobFailed || entries[0].Detail != "access denied" {
+		t.Errorf("second job = %+v, want failed with error detail", entries[0])
+	}
+	if entries[1].cancel != nil {
+		t.Error("finished job should drop its cancel func")
+	}
+}
+
+func TestJobs_ClearFinished(t *testing.T) {
+	j := NewJobs()
+	running := j.Start("running", nil)
+	done := j.Start("done", nil)
+	j.Finish(done, nil, "")
+
+	j.ClearFinished()
+	entries := j.Entries()
+	if len(entries) != 1 || entries[0].ID != running {
+		t.Fatalf("expected only the running job to remain, got %+v", entries)
+	}
+}
+
+func TestJobBrowser_Buttons(t *testing.T) {
+	b := NewJobBrowser()
+	b.SetJobs([]ProjectJob{
+		{JobID: "a", Query: "SELECT 1", DestProject: "p", DestDataset: "_anon", DestTable: "t"},
+		{JobID: "b", Query: "SELECT x", Error: "invalidQuery: bad", DestTable: "t"},
+		{JobID: "c", JobType: "LOAD"},
+	}, "3 jobs")
+
+	if !b.openBtn.Disabled() || !b.resultsBtn.Disabled() {
+		t.Fatal("buttons should be disabled without a selection")
+	}
+	tests := []struct {
+		row              int
+		canOpen, canLoad bool
+	}{
+		{0, true, true},
+		{1, true, false},
+		{2, false, false},
+	}
+	for _, tt := range tests {
+		b.table.Select(widget.TableCellID{Row: tt.row, Col: 0})
+		if b.openBtn.Disabled() == tt.canOpen || b.resultsBtn.Disabled() == tt.canLoad {
+			t.Errorf("row %d: open enabled=%v load enabled=%v, want %v %v",
+				tt.row, !b.openBtn.Disabled(), !b.resultsBtn.Disabled(), tt.canOpen, tt.canLoad)
+		}
+	}
+
+	b.SetJobs(nil, "")
+	if _, ok := b.Selected(); ok {
+		t.Error("SetJobs should clear the selection")
+	}
+}
+
+func TestJobBrowser_Load(t *testing.T) {
+	b := NewJobBrowser()
+	var got []any
+	b.OnLoad = func(project, region string, allUsers bool, days int) {
+		got = []any{project, region, allUsers, days}
+	}
+	b.project.SetText(" my-proj ")
+	b.region.SetText("eu")
+	b.scope.SetSelected("All users")
+	b.days.SetSelected("30 days")
+	b.Load()
+	want := []any{"my-proj", "eu", true, 30}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("OnLoad got %v, want %v", got, want)
+	}
+}
+
+func TestJobBrowserCell(t *testing.T) {
+	j := ProjectJob{
+		State:         "DONE",
+		StatementType: "SELECT",
+		JobType:       "QUERY",
+		BytesBilled:   3 << 20,
+		SlotMillis:    1530,
+		Duration:      2040 * time.Millisecond,
+		Query:         "SELECT\n  1",
+	}
+	checks := map[int]string{1: "DONE", 3: "SELECT", 4: "3.0 MiB", 5: "1.5s", 6: "2s", 8: "SELECT 1"}
+	for col, want := range checks {
+		if got := jobBrowserCell(j, col); got != want {
+			t.Errorf("col %d = %q, want %q", col, got, want)
+		}
+	}
+	j.CacheHit = true
+	j.Error = "accessDenied: no"
+	if got := jobBrowserCell(j, 4); got != "cached" {
+		t.Errorf("cache hit billed = %q", got)
+	}
+	if got := jobBrowserCell(j, 1); got != "FAILED" {
+		t.Errorf("failed state = %q", got)
+	}
+	if got := jobBrowserCell(j, 8); got != j.Error {
+		t.Errorf("failed query column = %q", got)
+	}
+}
@@ -0,0 +1,366 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+type jsonKind int
+
+const (
+	jsonObject jsonKind = iota
+	jsonArray
+	jsonString
+	jsonNumber
+	jsonBool
+	jsonNull
+)
+
+// jsonNode is one value in a parsed JSON document. Object keys keep their
+// original order.
+type jsonNode struct {
+	key      string // object key, or "[i]" for array elements; empty for the root
+	path     string // JSON path from the root, e.g. $.user.tags[0]
+	kind     jsonKind
+	value    string // scalar value; strings are unquoted
+	children []*jsonNode
+}
+
+var jsonIdentRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseJSONTree parses a JSON document into an ordered tree.
+func parseJSONTree(s string) (*jsonNode, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	root, err := decodeJSONNode(dec, "", "$")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return root, nil
+}
+
+func decodeJSONNode(dec *json.Decoder, key, path string) (*jsonNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	n := &jsonNode{key: key, path: path}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			n.kind = jsonObject
+			for dec.More() {
+				kt, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				k, _ := kt.(string)
+				child, err := decodeJSONNode(dec, k, path+jsonPathKey(k))
+				if err != nil {
+					return nil, err
+				}
+				n.children = append(n.children, child)
+			}
+		case '[':
+			n.kind = jsonArray
+			for i := 0; dec.More(); i++ {
+				idx := fmt.Sprintf("[%d]", i)
+				child, err := decodeJSONNode(dec, idx, path+idx)
+				if err != nil {
+					return nil, err
+				}
+				n.children = append(n.children, child)
+			}
+		default:
+			return nil, fmt.Errorf("unexpected %v", t)
+		}
+		if _, err := dec.Token(); err != nil { // closing delimiter
+			return nil, err
+		}
+	case string:
+		n.kind, n.value = jsonString, t
+	case json.Number:
+		n.kind, n.value = jsonNumber, t.String()
+	case bool:
+		n.kind, n.value = jsonBool, strconv.FormatBool(t)
+	case nil:
+		n.kind, n.value = jsonNull, "null"
+	}
+	return n, nil
+}
+
+// jsonPathKey returns the path segment for an object key.
+func jsonPathKey(k string) string {
+	if jsonIdentRe.MatchString(k) {
+		return "." + k
+	}
+	return "[" + strconv.Quote(k) + "]"
+}
+
+// summary returns a short description of a container node.
+func (n *jsonNode) summary() string {
+	switch n.kind {
+	case jsonObject:
+		if len(n.children) == 1 {
+			return "{1 field}"
+		}
+		return fmt.Sprintf("{%d fields}", len(n.children))
+	case jsonArray:
+		if len(n.children) == 1 {
+			return "[1 item]"
+		}
+		return fmt.Sprintf("[%d items]", len(n.children))
+	case jsonString:
+		return strconv.Quote(n.value)
+	}
+	return n.value
+}
+
+// preview renders the node inline, descending depth levels before
+// summarizing nested containers.
+func (n *jsonNode) preview(depth int) string {
+	switch n.kind {
+	case jsonObject, jsonArray:
+		open, close := "{", "}"
+		if n.kind == jsonArray {
+			open, close = "[", "]"
+		}
+		if len(n.children) == 0 {
+			return open + close
+		}
+		if depth <= 0 {
+			return open + "…" + close
+		}
+		parts := make([]string, len(n.children))
+		for i, c := range n.children {
+			parts[i] = c.preview(depth - 1)
+			if n.kind == jsonObject {
+				parts[i] = c.key + ": " + parts[i]
+			}
+		}
+		return open + strings.Join(parts, ", ") + close
+	}
+	return n.summary()
+}
+
+// maxPreviewRunes bounds the length of nested value previews in the grid.
+const maxPreviewRunes = 120
+
+// nestedPreview returns a compact, readable preview of a JSON value for a
+// grid cell, e.g. `{name: "ann", tags: […]}` or `3 × [1, 2, 3]`. Values that
+// are not valid JSON are returned unchanged.
+func nestedPreview(s string) string {
+	n, err := parseJSONTree(s)
+	if err != nil {
+		return s
+	}
+	p := n.preview(2)
+	if n.kind == jsonArray && len(n.children) > 0 {
+		p = fmt.Sprintf("%d × %s", len(n.children), p)
+	}
+	if r := []rune(p); len(r) > maxPreviewRunes {
+		p = string(r[:maxPreviewRunes-1]) + "…"
+	}
+	return p
+}
+
+// marshal re-encodes the node as indented JSON.
+func (n *jsonNode) marshal() string {
+	var b bytes.Buffer
+	n.write(&b)
+	var out bytes.Buffer
+	if err := json.Indent(&out, b.Bytes(), "", "  "); err != nil {
+		return b.String()
+	}
+	return out.String()
+}
+
+func (n *jsonNode) write(b *bytes.Buffer) {
+	switch n.kind {
+	case jsonObject:
+		b.WriteByte('{')
+		for i, c := range n.children {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			k, _ := json.Marshal(c.key)
+			b.Write(k)
+			b.WriteByte(':')
+			c.write(b)
+		}
+		b.WriteByte('}')
+	case jsonArray:
+		b.WriteByte('[')
+		for i, c := range n.children {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			c.write(b)
+		}
+		b.WriteByte(']')
+	case jsonString:
+		s, _ := json.Marshal(n.value)
+		b.Write(s)
+	default:
+		b.WriteString(n.value)
+	}
+}
+
+// JSONViewer shows a JSON value as a collapsible tree with syntax colors.
+// Selecting a node shows its JSON path, which can be copied.
+type JSONViewer struct {
+	tree      *widget.Tree
+	pathLabel *widget.Label
+
+	root     *jsonNode
+	nodes    map[string]*jsonNode // by path
+	selected string
+
+	Container fyne.CanvasObject
+}
+
+func NewJSONViewer() *JSONViewer {
+	v := &JSONViewer{nodes: make(map[string]*jsonNode)}
+
+	v.tree = widget.NewTree(
+		func(id widget.TreeNodeID) []widget.TreeNodeID {
+			if id == "" {
+				if v.root == nil {
+					return nil
+				}
+				return []widget.TreeNodeID{v.root.path}
+			}
+			n := v.nodes[id]
+			if n == nil {
+				return nil
+			}
+			ids := make([]widget.TreeNodeID, len(n.children))
+			for i, c := range n.children {
+				ids[i] = c.path
+			}
+			return ids
+		},
+		func(id widget.TreeNodeID) bool {
+			if id == "" {
+				return true
+			}
+			n := v.nodes[id]
+			return n != nil && (n.kind == jsonObject || n.kind == jsonArray) && len(n.children) > 0
+		},
+		func(bool) fyne.CanvasObject {
+			key := canvas.NewText("", color.White)
+			key.TextStyle = fyne.TextStyle{Bold: true}
+			val := canvas.NewText("", color.White)
+			val.TextStyle = fyne.TextStyle{Monospace: true}
+			return container.NewHBox(key, val)
+		},
+		func(id widget.TreeNodeID, _ bool, obj fyne.CanvasObject) {
+			n := v.nodes[id]
+			if n == nil {
+				return
+			}
+			row := obj.(*fyne.Container)
+			key := row.Objects[0].(*canvas.Text)
+			val := row.Objects[1].(*canvas.Text)
+
+			key.Text = n.key
+			if n == v.root {
+				key.Text = "$"
+			}
+			key.Text += ":"
+			key.Color = theme.Color(theme.ColorNameForeground)
+			key.TextSize = theme.Size(theme.SizeNameText)
+
+			val.Text = n.summary()
+			val.Color = jsonValueColor(n.kind)
+			val.TextSize = theme.Size(theme.SizeNameText)
+			key.Refresh()
+			val.Refresh()
+		},
+	)
+	v.tree.OnSelected = func(id widget.TreeNodeID) {
+		v.selected = id
+		v.pathLabel.SetText(id)
+	}
+
+	v.pathLabel = widget.NewLabel("")
+	v.pathLabel.Truncation = fyne.TextTruncateEllipsis
+	copyPath := widget.NewButtonWithIcon("Path", theme.ContentCopyIcon(), func() { copyToClipboard(v.SelectedPath()) })
+	copyValue := widget.NewButtonWithIcon("Value", theme.ContentCopyIcon(), func() { copyToClipboard(v.SelectedJSON()) })
+	expand := widget.NewButton("Expand All", v.tree.OpenAllBranches)
+	collapse := widget.NewButton("Collapse All", v.tree.CloseAllBranches)
+	toolbar := container.NewBorder(nil, nil, nil,
+		container.NewHBox(copyPath, copyValue, expand, collapse), v.pathLabel)
+
+	v.Container = container.NewBorder(toolbar, nil, nil, nil, v.tree)
+	return v
+}
+
+// jsonValueColor picks the syntax color for a value, reusing the SQL
+// editor's palette.
+func jsonValueColor(k jsonKind) color.Color {
+	switch k {
+	case jsonString:
+		return theme.Color("sqlString")
+	case jsonNumber:
+		return theme.Color("sqlNumber")
+	case jsonBool, jsonNull:
+		return theme.Color("sqlKeyword")
+	}
+	return theme.Color("sqlComment")
+}
+
+// SetValue shows a JSON document, expanded to its first level. Values that
+// are not JSON are shown as a single string.
+func (v *JSONViewer) SetValue(s string) {
+	root, err := parseJSONTree(s)
+	if err != nil {
+		root = &jsonNode{path: "$", kind: jsonString, value: s}
+	}
+	v.root = root
+	v.nodes = make(map[string]*jsonNode)
+	var index func(n *jsonNode)
+	index = func(n *jsonNode) {
+		v.nodes[n.path] = n
+		for _, c := range n.children {
+			index(c)
+		}
+	}
+	index(root)
+
+	v.tree.UnselectAll()
+	v.tree.CloseAllBranches()
+	v.tree.OpenBranch(root.path)
+	v.tree.Select(root.path)
+	v.tree.Refresh()
+}
+
+// SelectedPath returns the JSON path of the selected node.
+func (v *JSONViewer) SelectedPath() string {
+	return v.selected
+}
+
+// SelectedJSON returns the selected node's value as indented JSON.
+func (v *JSONViewer) SelectedJSON() string {
+	n := v.nodes[v.selected]
+	if n == nil {
+		return ""
+	}
+	return n.marshal()
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestParseJSONTree_PathsAndOrder(t *testing.T) {
+	root, err := parseJSONTree(`{"z":1,"user":{"tags":["a","b"]},"odd key":null}`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	var keys []string
+	for _, c := range root.children {
+		keys = append(keys, c.key)
+	}
+	if len(keys) != 3 || keys[0] != "z" || keys[1] != "user" || keys[2] != "odd key" {
+		t.Errorf("keys = %v, want original order", keys)
+	}
+	tag := root.children[1].children[0].children[1]
+	if tag.path != "$.user.tags[1]" || tag.value != "b" {
+		t.Errorf("tag path = %q value %q", tag.path, tag.value)
+	}
+	if p := root.children[2].path; p != `$["odd key"]` {
+		t.Errorf("quoted key path = %q", p)
+	}
+	if _, err := parseJSONTree(`{"a":1} trailing`); err == nil {
+		t.Error("expected error for trailing data")
+	}
+}
+
+func TestNestedPreview(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{`{"name":"ann","tags":["a"],"meta":{"k":{"deep":1}}}`, `{name: "ann", tags: ["a"], meta: {k: {…}}}`},
+		{`[1,2,3]`, `3 × [1, 2, 3]`},
+		{`[]`, `[]`},
+		{`not json`, `not json`},
+	}
+	for _, tt := range tests {
+		if got := nestedPreview(tt.in); got != tt.want {
+			t.Errorf("nestedPreview(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestJSONViewer_SelectionCopy(t *testing.T) {
+	v := NewJSONViewer()
+	v.SetValue(`{"user":{"id":7,"tags":["x"]}}`)
+	if v.SelectedPath() != "$" {
+		t.Errorf("root should be selected after SetValue, got %q", v.SelectedPath())
+	}
+	v.tree.Select("$.user")
+	if v.SelectedPath() != "$.user" {
+		t.Errorf("SelectedPath = %q", v.SelectedPath())
+	}
+	want := "{\n  \"id\": 7,\n  \"tags\": [\n    \"x\"\n  ]\n}"
+	if got := v.SelectedJSON(); got != want {
+		t.Errorf("SelectedJSON = %q, want %q", got, want)
+	}
+}
+
+func TestResults_TapNestedCellOpensViewer(t *testing.T) {
+	r := NewResults()
+	r.SetData([]string{"id", "payload"}, []string{"INTEGER", "RECORD"},
+		[][]string{{"1", `{"a":[1,2]}`}, {"2", "NULL"}}, [][]bool{nil, {false, true}})
+
+	r.tapCell(widget.TableCellID{Row: 1, Col: 1})
+	if r.showRecord {
+		t.Fatal("NULL nested value should not open the viewer")
+	}
+	r.tapCell(widget.TableCellID{Row: 0, Col: 1})
+	if !r.showRecord || r.detailTabs.Selected() != r.valueTab {
+		t.Fatal("tapping a nested value should show the value viewer")
+	}
+	if r.viewer.root == nil || r.viewer.root.children[0].key != "a" {
+		t.Error("viewer should show the tapped value")
+	}
+}
@@ -0,0 +1,219 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/farbodahm/delephon/export"
+)
+
+// Pivot groups and pivots the current result set in memory. The output is
+// shown in its own results grid, which can be sorted and exported like a
+// query result.
+type Pivot struct {
+	rowsBtn   *widget.Button
+	colsBtn   *widget.Button
+	valuesBtn *widget.Button
+	clearBtn  *widget.Button
+	specLabel *widget.Label
+	columns   []string
+	types     []string
+	rows      [][]string
+	spec      pivotSpec
+
+	// Results shows the pivoted rows.
+	Results *Results
+
+	Container fyne.CanvasObject
+}
+
+func NewPivot() *Pivot {
+	p := &Pivot{Results: NewResults()}
+
+	p.rowsBtn = widget.NewButton("Rows", func() {
+		p.showDimensionMenu(&p.spec.Rows, p.rowsBtn)
+	})
+	p.colsBtn = widget.NewButton("Columns", func() {
+		p.showDimensionMenu(&p.spec.Cols, p.colsBtn)
+	})
+	p.valuesBtn = widget.NewButton("Values", p.showValuesMenu)
+	p.clearBtn = widget.NewButtonWithIcon("", theme.ContentClearIcon(), func() {
+		p.spec = pivotSpec{}
+		p.update()
+	})
+	p.specLabel = widget.NewLabel("")
+	p.specLabel.Truncation = fyne.TextTruncateEllipsis
+
+	toolbar := container.NewBorder(nil, nil,
+		container.NewHBox(p.rowsBtn, p.colsBtn, p.valuesBtn, p.clearBtn), nil, p.specLabel)
+	p.Container = container.NewBorder(toolbar, nil, nil, nil, p.Results.Container)
+	p.setEnabled(false)
+	p.update()
+	return p
+}
+
+func (p *Pivot) setEnabled(on bool) {
+	for _, w := range []fyne.Disableable{p.rowsBtn, p.colsBtn, p.valuesBtn, p.clearBtn} {
+		if on {
+			w.Enable()
+		} else {
+			w.Disable()
+		}
+	}
+}
+
+// SetData replaces the source result set and clears the pivot layout.
+func (p *Pivot) SetData(columns, types []string, rows [][]string) {
+	fyne.Do(func() {
+		p.columns, p.types, p.rows = columns, types, rows
+		p.spec = pivotSpec{}
+		p.setEnabled(len(columns) > 0)
+		p.update()
+	})
+}
+
+// showDimensionMenu lists the columns with a check mark on those in dims.
+// Picking a column adds it to the end of dims or removes it.
+func (p *Pivot) showDimensionMenu(dims *[]int, anchor fyne.CanvasObject) {
+	var items []*fyne.MenuItem
+	for i, col := range p.columns {
+		item := fyne.NewMenuItem(col, func() {
+			*dims = toggleIndex(*dims, i)
+			p.update()
+		})
+		item.Checked = indexOf(*dims, i) >= 0
+		items = append(items, item)
+	}
+	showMenuBelow(fyne.NewMenu("", items...), anchor)
+}
+
+// showValuesMenu offers each aggregation with a submenu of the columns it
+// applies to.
+func (p *Pivot) showValuesMenu() {
+	var items []*fyne.MenuItem
+	for _, agg := range pivotAggs {
+		var sub []*fyne.MenuItem
+		if agg == PivotCount {
+			sub = append(sub, p.valueItem("All rows", pivotValue{Agg: agg, Col: -1}))
+		}
+		for i, col := range p.columns {
+			typ := ""
+			if i < len(p.types) {
+				typ = p.types[i]
+			}
+			if (agg == PivotSum || agg == PivotAvg) && !isNumericType(typ) {
+				continue
+			}
+			sub = append(sub, p.valueItem(col, pivotValue{Agg: agg, Col: i}))
+		}
+		item := fyne.NewMenuItem(string(agg), nil)
+		if len(sub) == 0 {
+			item.Disabled = true
+		} else {
+			item.ChildMenu = fyne.NewMenu("", sub...)
+		}
+		items = append(items, item)
+	}
+	showMenuBelow(fyne.NewMenu("", items...), p.valuesBtn)
+}
+
+func (p *Pivot) valueItem(label string, v pivotValue) *fyne.MenuItem {
+	item := fyne.NewMenuItem(label, func() {
+		p.toggleValue(v)
+	})
+	for _, have := range p.spec.Values {
+		if have == v {
+			item.Checked = true
+		}
+	}
+	return item
+}
+
+func (p *Pivot) toggleValue(v pivotValue) {
+	for i, have := range p.spec.Values {
+		if have == v {
+			p.spec.Values = append(p.spec.Values[:i:i], p.spec.Values[i+1:]...)
+			p.update()
+			return
+		}
+	}
+	p.spec.Values = append(p.spec.Values, v)
+	p.update()
+}
+
+// update recomputes the pivot and shows it.
+func (p *Pivot) update() {
+	p.specLabel.SetText(p.describe())
+	if len(p.columns) == 0 {
+		p.Results.Clear()
+		p.Results.SetStatus("Run a query to pivot its results")
+		return
+	}
+	if len(p.spec.Rows) == 0 && len(p.spec.Cols) == 0 {
+		p.Results.Clear()
+		p.Results.SetStatus("Pick row or column dimensions to group by")
+		return
+	}
+	cols, types, rows, err := pivotResult(p.columns, p.types, p.rows, p.spec)
+	if err != nil {
+		p.Results.Clear()
+		p.Results.SetStatus(fmt.Sprintf("Error: %v", err))
+		return
+	}
+	// Pivot cells are computed display values; "NULL" marks a missing one.
+	p.Results.SetData(cols, types, rows, export.DisplayNulls(rows))
+	p.Results.SetStatus(fmt.Sprintf("%d groups from %d rows", len(rows), len(p.rows)))
+}
+
+// describe summarizes the layout, e.g. "Rows: region · Columns: day ·
+// Values: sum(sales)".
+func (p *Pivot) describe() string {
+	names := func(dims []int) string {
+		parts := make([]string, len(dims))
+		for i, d := range dims {
+			parts[i] = p.columns[d]
+		}
+		return strings.Join(parts, ", ")
+	}
+	var parts []string
+	if len(p.spec.Rows) > 0 {
+		parts = append(parts, "Rows: "+names(p.spec.Rows))
+	}
+	if len(p.spec.Cols) > 0 {
+		parts = append(parts, "Columns: "+names(p.spec.Cols))
+	}
+	values := "count(*)"
+	if len(p.spec.Values) > 0 {
+		vs := make([]string, len(p.spec.Values))
+		for i, v := range p.spec.Values {
+			vs[i] = v.name(p.columns)
+		}
+		values = strings.Join(vs, ", ")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(append(parts, "Values: "+values), " · ")
+}
+
+func indexOf(s []int, v int) int {
+	for i, x := range s {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// toggleIndex removes v from s, or appends it when absent.
+func toggleIndex(s []int, v int) []int {
+	if i := indexOf(s, v); i >= 0 {
+		return append(s[:i:i], s[i+1:]...)
+	}
+	return append(s, v)
+}
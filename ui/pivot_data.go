@@ -0,0 +1,238 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type PivotAgg string
+
+const (
+	PivotCount    PivotAgg = "count"
+	PivotSum      PivotAgg = "sum"
+	PivotAvg      PivotAgg = "avg"
+	PivotMin      PivotAgg = "min"
+	PivotMax      PivotAgg = "max"
+	PivotDistinct PivotAgg = "distinct"
+)
+
+var pivotAggs = []PivotAgg{PivotCount, PivotSum, PivotAvg, PivotMin, PivotMax, PivotDistinct}
+
+// maxPivotColumns bounds the number of value columns a pivot may produce,
+// so a high-cardinality column dimension cannot build an unusable grid.
+const maxPivotColumns = 500
+
+// pivotValue is one aggregated measure. Col is -1 for a row count.
+type pivotValue struct {
+	Agg PivotAgg
+	Col int
+}
+
+// pivotSpec describes a pivot of a result set: rows are grouped by the Rows
+// columns, the distinct values of the Cols columns become output columns,
+// and every cell holds the Values aggregated over its group.
+type pivotSpec struct {
+	Rows   []int
+	Cols   []int
+	Values []pivotValue
+}
+
+func (v pivotValue) name(columns []string) string {
+	if v.Col < 0 || v.Col >= len(columns) {
+		return string(v.Agg) + "(*)"
+	}
+	return fmt.Sprintf("%s(%s)", v.Agg, columns[v.Col])
+}
+
+// resultType returns the BigQuery type of the aggregate, so the derived
+// grid sorts and exports it correctly.
+func (v pivotValue) resultType(types []string) string {
+	src := ""
+	if v.Col >= 0 && v.Col < len(types) {
+		src = types[v.Col]
+	}
+	switch v.Agg {
+	case PivotCount, PivotDistinct:
+		return "INTEGER"
+	case PivotSum:
+		if src == "INTEGER" || src == "INT64" {
+			return "INTEGER"
+		}
+		return "FLOAT"
+	case PivotAvg:
+		return "FLOAT"
+	}
+	return src // min and max keep the column type
+}
+
+// pivotKey is a tuple of dimension values joined with a separator that
+// cannot appear in a cell.
+type pivotKey string
+
+const pivotKeySep = "\x00"
+
+func makePivotKey(row []string, dims []int) pivotKey {
+	parts := make([]string, len(dims))
+	for i, d := range dims {
+		parts[i] = cellAt(row, d)
+	}
+	return pivotKey(strings.Join(parts, pivotKeySep))
+}
+
+func (k pivotKey) parts() []string {
+	return strings.Split(string(k), pivotKeySep)
+}
+
+// pivotResult computes a pivot over rows and returns it as a new result
+// set. Aggregates skip NULLs as SQL does; combinations with no rows are
+// NULL. Row and column groups are sorted by value.
+func pivotResult(columns, types []string, rows [][]string, spec pivotSpec) (outCols, outTypes []string, out [][]string, err error) {
+	typeOf := func(col int) string {
+		if col >= 0 && col < len(types) {
+			return types[col]
+		}
+		return ""
+	}
+	values := spec.Values
+	if len(values) == 0 {
+		values = []pivotValue{{Agg: PivotCount, Col: -1}}
+	}
+
+	// Group rows by (row key, column key).
+	type cell struct{ vals [][]string } // per value, the inputs
+	groups := make(map[pivotKey]map[pivotKey]*cell)
+	var rowKeys []pivotKey
+	colSeen := make(map[pivotKey]bool)
+	var colKeys []pivotKey
+	for _, row := range rows {
+		rk, ck := makePivotKey(row, spec.Rows), makePivotKey(row, spec.Cols)
+		byCol, ok := groups[rk]
+		if !ok {
+			byCol = make(map[pivotKey]*cell)
+			groups[rk] = byCol
+			rowKeys = append(rowKeys, rk)
+		}
+		if !colSeen[ck] {
+			colSeen[ck] = true
+			colKeys = append(colKeys, ck)
+		}
+		c, ok := byCol[ck]
+		if !ok {
+			c = &cell{vals: make([][]string, len(values))}
+			byCol[ck] = c
+		}
+		for i, v := range values {
+			if v.Col < 0 {
+				c.vals[i] = append(c.vals[i], "")
+				continue
+			}
+			if s := cellAt(row, v.Col); s != "NULL" {
+				c.vals[i] = append(c.vals[i], s)
+			}
+		}
+	}
+	if n := len(colKeys) * len(values); n > maxPivotColumns {
+		return nil, nil, nil, fmt.Errorf("the pivot would have %d value columns; the limit is %d", n, maxPivotColumns)
+	}
+	sortPivotKeys(rowKeys, spec.Rows, types)
+	sortPivotKeys(colKeys, spec.Cols, types)
+
+	for _, d := range spec.Rows {
+		outCols = append(outCols, columns[d])
+		outTypes = append(outTypes, typeOf(d))
+	}
+	for _, ck := range colKeys {
+		prefix := ""
+		if len(spec.Cols) > 0 {
+			prefix = strings.Join(ck.parts(), " / ")
+		}
+		for _, v := range values {
+			name := v.name(columns)
+			if prefix != "" {
+				name = prefix
+				if len(values) > 1 {
+					name += " · " + v.name(columns)
+				}
+			}
+			outCols = append(outCols, name)
+			outTypes = append(outTypes, v.resultType(types))
+		}
+	}
+
+	for _, rk := range rowKeys {
+		var row []string
+		if len(spec.Rows) > 0 {
+			row = append(row, rk.parts()...)
+		}
+		for _, ck := range colKeys {
+			c := groups[rk][ck]
+			for i, v := range values {
+				if c == nil {
+					row = append(row, "NULL")
+					continue
+				}
+				row = append(row, pivotAggregate(c.vals[i], v, typeOf(v.Col)))
+			}
+		}
+		out = append(out, row)
+	}
+	return outCols, outTypes, out, nil
+}
+
+func sortPivotKeys(keys []pivotKey, dims []int, types []string) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := keys[i].parts(), keys[j].parts()
+		for n, d := range dims {
+			typ := ""
+			if d < len(types) {
+				typ = types[d]
+			}
+			if c := compareValues(a[n], b[n], typ); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// pivotAggregate reduces the non-NULL inputs of one cell.
+func pivotAggregate(vals []string, v pivotValue, typ string) string {
+	switch v.Agg {
+	case PivotCount:
+		return strconv.Itoa(len(vals))
+	case PivotDistinct:
+		seen := make(map[string]bool, len(vals))
+		for _, s := range vals {
+			seen[s] = true
+		}
+		return strconv.Itoa(len(seen))
+	case PivotMin, PivotMax:
+		if len(vals) == 0 {
+			return "NULL"
+		}
+		m := vals[0]
+		for _, s := range vals[1:] {
+			c := compareValues(s, m, typ)
+			if (v.Agg == PivotMin && c < 0) || (v.Agg == PivotMax && c > 0) {
+				m = s
+			}
+		}
+		return m
+	}
+
+	var nums []float64
+	for _, s := range vals {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			nums = append(nums, f)
+		}
+	}
+	if len(nums) == 0 {
+		return "NULL"
+	}
+	if v.Agg == PivotAvg {
+		return strconv.FormatFloat(sumFloats(nums)/float64(len(nums)), 'f', -1, 64)
+	}
+	return strconv.FormatFloat(sumFloats(nums), 'f', -1, 64)
+}
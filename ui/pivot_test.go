@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var pivotCols = []string{"region", "year", "sales", "customer"}
+var pivotTypes = []string{"STRING", "INTEGER", "FLOAT", "STRING"}
+var pivotRows = [][]string{
+	{"us", "2024", "10", "ann"},
+	{"eu", "2023", "4", "bob"},
+	{"us", "2023", "2.5", "ann"},
+	{"eu", "2024", "NULL", "cid"},
+	{"us", "2024", "5", "dee"},
+}
+
+func TestPivotResult_GroupBy(t *testing.T) {
+	cols, types, rows, err := pivotResult(pivotCols, pivotTypes, pivotRows, pivotSpec{
+		Rows: []int{0},
+		Values: []pivotValue{
+			{Agg: PivotCount, Col: -1},
+			{Agg: PivotSum, Col: 2},
+			{Agg: PivotAvg, Col: 2},
+			{Agg: PivotDistinct, Col: 3},
+			{Agg: PivotMax, Col: 3},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"region", "count(*)", "sum(sales)", "avg(sales)", "distinct(customer)", "max(customer)"}; !reflect.DeepEqual(cols, want) {
+		t.Errorf("columns = %v", cols)
+	}
+	if want := []string{"STRING", "INTEGER", "FLOAT", "FLOAT", "INTEGER", "STRING"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("types = %v", types)
+	}
+	want := [][]string{
+		{"eu", "2", "4", "4", "2", "cid"}, // NULL sales are skipped
+		{"us", "3", "17.5", "5.833333333333333", "2", "dee"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+}
+
+func TestPivotResult_ColumnDimensions(t *testing.T) {
+	cols, _, rows, err := pivotResult(pivotCols, pivotTypes, pivotRows, pivotSpec{
+		Rows:   []int{0},
+		Cols:   []int{1},
+		Values: []pivotValue{{Agg: PivotSum, Col: 2}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"region", "2023", "2024"}; !reflect.DeepEqual(cols, want) {
+		t.Errorf("columns = %v", cols)
+	}
+	want := [][]string{{"eu", "4", "NULL"}, {"us", "2.5", "15"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+
+	cols, _, rows, _ = pivotResult(pivotCols, pivotTypes, pivotRows[:2], pivotSpec{
+		Cols:   []int{0},
+		Values: []pivotValue{{Agg: PivotCount, Col: -1}, {Agg: PivotMin, Col: 1}},
+	})
+	if want := []string{"eu · count(*)", "eu · min(year)", "us · count(*)", "us · min(year)"}; !reflect.DeepEqual(cols, want) {
+		t.Errorf("columns = %v", cols)
+	}
+	if want := [][]string{{"1", "2023", "1", "2024"}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v", rows)
+	}
+}
+
+func TestPivotResult_TooManyColumns(t *testing.T) {
+	var rows [][]string
+	for i := 0; i <= maxPivotColumns; i++ {
+		rows = append(rows, []string{strings.Repeat("x", i)})
+	}
+	_, _, _, err := pivotResult([]string{"k"}, []string{"STRING"}, rows, pivotSpec{Cols: []int{0}})
+	if err == nil {
+		t.Error("expected an error for a high-cardinality column dimension")
+	}
+}
+
+func TestPivot_Update(t *testing.T) {
+	p := NewPivot()
+	p.SetData(pivotCols, pivotTypes, pivotRows)
+	p.spec.Rows = toggleIndex(p.spec.Rows, 1)
+	p.toggleValue(pivotValue{Agg: PivotSum, Col: 2})
+
+	cols, _, rows, _ := p.Results.Data()
+	if !reflect.DeepEqual(cols, []string{"year", "sum(sales)"}) || len(rows) != 2 {
+		t.Errorf("pivot grid = %v %v", cols, rows)
+	}
+	if got := p.specLabel.Text; got != "Rows: year · Values: sum(sales)" {
+		t.Errorf("spec label = %q", got)
+	}
+}
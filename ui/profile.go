@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+var profileColumns = []string{"Column", "Type", "Nulls", "Distinct", "Min", "Max", "Mean", "Std Dev", "Histogram", "Top Values"}
+
+var profileWidths = []float32{140, 90, 110, 90, 120, 120, 90, 90, 110, 300}
+
+// Profile shows per-column statistics for a result set or a table.
+type Profile struct {
+	table     *widget.Table
+	title     *widget.Label
+	status    *widget.Label
+	resultBtn *widget.Button
+	profiles  []ColumnProfile
+
+	// OnProfileResults is called when the current results should be
+	// profiled; the app answers with ProfileResult.
+	OnProfileResults func()
+
+	Container fyne.CanvasObject
+}
+
+func NewProfile() *Profile {
+	p := &Profile{
+		title:  widget.NewLabel("No profile"),
+		status: widget.NewLabel("Profile the current results here, or a table from its schema view."),
+	}
+	p.title.TextStyle = fyne.TextStyle{Bold: true}
+	p.title.Truncation = fyne.TextTruncateEllipsis
+
+	p.table = widget.NewTableWithHeaders(
+		func() (int, int) { return len(p.profiles), len(profileColumns) },
+		func() fyne.CanvasObject {
+			l := widget.NewLabel("")
+			l.Truncation = fyne.TextTruncateEllipsis
+			return l
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			if id.Row >= len(p.profiles) {
+				return
+			}
+			label.SetText(profileCell(p.profiles[id.Row], id.Col))
+		},
+	)
+	p.table.UpdateHeader = func(id widget.TableCellID, template fyne.CanvasObject) {
+		label := template.(*widget.Label)
+		if id.Row < 0 && id.Col >= 0 && id.Col < len(profileColumns) {
+			label.SetText(profileColumns[id.Col])
+		} else if id.Col < 0 && id.Row >= 0 {
+			label.SetText(fmt.Sprintf("%d", id.Row+1))
+		}
+	}
+	for i, w := range profileWidths {
+		p.table.SetColumnWidth(i, w)
+	}
+
+	p.resultBtn = widget.NewButtonWithIcon("Profile Results", theme.Icon(theme.IconNameSearch), func() {
+		if p.OnProfileResults != nil {
+			p.OnProfileResults()
+		}
+	})
+
+	top := container.NewBorder(nil, nil, nil, p.resultBtn, p.title)
+	p.Container = container.NewBorder(top, p.status, nil, nil, p.table)
+	return p
+}
+
+// ProfileResult computes an exact profile of fetched rows and shows it.
+func (p *Profile) ProfileResult(source string, columns, types []string, rows [][]string) {
+	profiles := profileRows(columns, types, rows)
+	p.SetProfiles(source, profiles, fmt.Sprintf("Exact statistics over %d fetched rows", len(rows)))
+}
+
+// SetProfiles shows column profiles computed elsewhere, e.g. on the server.
+func (p *Profile) SetProfiles(source string, profiles []ColumnProfile, status string) {
+	fyne.Do(func() {
+		p.profiles = profiles
+		p.title.SetText(source)
+		p.status.SetText(status)
+		p.table.Refresh()
+	})
+}
+
+// SetStatus shows progress or an error below the profile.
+func (p *Profile) SetStatus(text string) {
+	fyne.Do(func() { p.status.SetText(text) })
+}
+
+// profileCell formats one statistic of a column profile.
+func profileCell(c ColumnProfile, col int) string {
+	approx := ""
+	if c.Approx {
+		approx = "≈"
+	}
+	switch col {
+	case 0:
+		return c.Name
+	case 1:
+		return c.Type
+	case 2:
+		return fmt.Sprintf("%d (%.1f%%)", c.Nulls, c.NullPercent())
+	case 3:
+		if c.Distinct < 0 {
+			return "—"
+		}
+		return approx + strconv.FormatInt(c.Distinct, 10)
+	case 4:
+		return c.Min
+	case 5:
+		return c.Max
+	case 6:
+		if !c.Numeric || c.Nulls == c.Rows {
+			return ""
+		}
+		return formatStat(c.Mean)
+	case 7:
+		if !c.Numeric || c.Nulls == c.Rows {
+			return ""
+		}
+		return formatStat(c.StdDev)
+	case 8:
+		return sparkline(c.Histogram)
+	case 9:
+		parts := make([]string, len(c.Top))
+		for i, t := range c.Top {
+			parts[i] = fmt.Sprintf("%s (%s%d)", truncate(t.Value, 30), approx, t.Count)
+		}
+		return strings.Join(parts, ", ")
+	}
+	return ""
+}
+
+func formatStat(v float64) string {
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/farbodahm/delephon/export"
+)
+
+// profileTopK is how many most frequent values a profile shows.
+const profileTopK = 5
+
+// profileBins is the histogram size for numeric columns.
+const profileBins = 10
+
+// ColumnProfile holds summary statistics for one column.
+type ColumnProfile struct {
+	Name      string
+	Type      string
+	Rows      int64
+	Nulls     int64
+	Distinct  int64 // -1 when not computed for the type
+	Approx    bool  // distinct and top counts are estimates
+	Min, Max  string
+	Numeric   bool
+	Mean      float64
+	StdDev    float64
+	Top       []ValueCount
+	Histogram []HistogramBin
+}
+
+type ValueCount struct {
+	Value string
+	Count int64
+}
+
+// HistogramBin counts the values in [Lo, Hi).
+type HistogramBin struct {
+	Lo, Hi float64
+	Count  int64
+}
+
+// NullPercent returns the share of NULL values, from 0 to 100.
+func (p ColumnProfile) NullPercent() float64 {
+	if p.Rows == 0 {
+		return 0
+	}
+	return float64(p.Nulls) * 100 / float64(p.Rows)
+}
+
+// profileRows computes exact statistics for every column of a fetched
+// result set.
+func profileRows(columns, types []string, rows [][]string) []ColumnProfile {
+	out := make([]ColumnProfile, len(columns))
+	for c, name := range columns {
+		typ := ""
+		if c < len(types) {
+			typ = types[c]
+		}
+		out[c] = profileColumn(name, typ, rows, c)
+	}
+	return out
+}
+
+func profileColumn(name, typ string, rows [][]string, col int) ColumnProfile {
+	p := ColumnProfile{Name: name, Type: typ, Rows: int64(len(rows)), Numeric: isNumericType(typ)}
+	ordered := !export.IsNested(typ)
+	counts := make(map[string]int64)
+	var nums []float64
+	for _, row := range rows {
+		v := cellAt(row, col)
+		if v == "NULL" {
+			p.Nulls++
+			continue
+		}
+		first := len(counts) == 0
+		counts[v]++
+		if ordered && (first || compareValues(v, p.Min, typ) < 0) {
+			p.Min = v
+		}
+		if ordered && (first || compareValues(v, p.Max, typ) > 0) {
+			p.Max = v
+		}
+		if p.Numeric {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				nums = append(nums, f)
+			}
+		}
+	}
+	p.Distinct = int64(len(counts))
+
+	for v, n := range counts {
+		p.Top = append(p.Top, ValueCount{Value: v, Count: n})
+	}
+	sort.Slice(p.Top, func(i, j int) bool {
+		if p.Top[i].Count != p.Top[j].Count {
+			return p.Top[i].Count > p.Top[j].Count
+		}
+		return p.Top[i].Value < p.Top[j].Value
+	})
+	if len(p.Top) > profileTopK {
+		p.Top = p.Top[:profileTopK]
+	}
+
+	if len(nums) > 0 {
+		p.Mean = sumFloats(nums) / float64(len(nums))
+		if len(nums) > 1 {
+			var ss float64
+			for _, f := range nums {
+				ss += (f - p.Mean) * (f - p.Mean)
+			}
+			// Sample standard deviation, as BigQuery's STDDEV.
+			p.StdDev = math.Sqrt(ss / float64(len(nums)-1))
+		}
+		p.Histogram = equalWidthHistogram(nums, profileBins)
+	}
+	return p
+}
+
+func equalWidthHistogram(vals []float64, bins int) []HistogramBin {
+	lo, hi := vals[0], vals[0]
+	for _, v := range vals {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	if hi == lo {
+		return []HistogramBin{{Lo: lo, Hi: hi, Count: int64(len(vals))}}
+	}
+	width := (hi - lo) / float64(bins)
+	out := make([]HistogramBin, bins)
+	for i := range out {
+		out[i] = HistogramBin{Lo: lo + float64(i)*width, Hi: lo + float64(i+1)*width}
+	}
+	for _, v := range vals {
+		i := int((v - lo) / width)
+		if i >= bins {
+			i = bins - 1
+		}
+		out[i].Count++
+	}
+	return out
+}
+
+// sparkline draws histogram counts as a row of block characters.
+func sparkline(bins []HistogramBin) string {
+	levels := []rune("▁▂▃▄▅▆▇█")
+	var most int64
+	for _, b := range bins {
+		most = max(most, b.Count)
+	}
+	if most == 0 {
+		return ""
+	}
+	out := make([]rune, len(bins))
+	for i, b := range bins {
+		out[i] = levels[int(float64(b.Count)/float64(most)*float64(len(levels)-1))]
+	}
+	return string(out)
+}
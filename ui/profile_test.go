@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProfileRows(t *testing.T) {
+	got := profileRows([]string{"n", "name", "ok"}, viewTypes, viewRows)
+
+	n := got[0]
+	if n.Rows != 4 || n.Nulls != 1 || n.Distinct != 3 || n.Min != "9" || n.Max != "100" {
+		t.Errorf("numeric profile = %+v, want numeric min and max", n)
+	}
+	if n.Mean != 39.666666666666664 || len(n.Histogram) != profileBins {
+		t.Errorf("mean = %v, bins = %d", n.Mean, len(n.Histogram))
+	}
+	if n.NullPercent() != 25 {
+		t.Errorf("NullPercent = %v", n.NullPercent())
+	}
+
+	ok := got[2]
+	if !reflect.DeepEqual(ok.Top, []ValueCount{{"true", 2}, {"false", 1}}) {
+		t.Errorf("top values = %+v", ok.Top)
+	}
+	if ok.Numeric || ok.Histogram != nil {
+		t.Error("booleans should not get numeric statistics")
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	bins := []HistogramBin{{Count: 0}, {Count: 4}, {Count: 8}}
+	if got := sparkline(bins); got != "▁▄█" {
+		t.Errorf("sparkline = %q", got)
+	}
+	if got := sparkline(nil); got != "" {
+		t.Errorf("empty sparkline = %q", got)
+	}
+}
@@ -1,22 +1,66 @@
 package ui
 
 import (
+	"bytes"
 	"fmt"
 	"image/color"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+
+	"github.com/farbodahm/delephon/export"
 )
 
 type Results struct {
-	table     *widget.Table
-	statusBar *widget.Label
+	table       *resultsTable
+	body        *fyne.Container // the grid, or the grid and record panel
+	statusBar   *widget.Label
+	exportBtn   *widget.Button
+	pinBtn      *widget.Button
+	columnsBtn  *widget.Button
+	searchEntry *widget.Entry
+	viewLabel   *widget.Label
+	clearBtn    *widget.Button
+	copyBtn     *widget.Button
+	recordBtn   *widget.Button
+
+	// Detail panel beside the grid: the selected row as a vertical list of
+	// fields, and a tree viewer for the selected nested value.
+	recordBox   *fyne.Container
+	recordTab   *container.TabItem
+	valueTab    *container.TabItem
+	detailTabs  *container.AppTabs
+	recordSplit *container.Split
+	showRecord  bool
+	viewer      *JSONViewer
 
 	columns []string
+	types   []string
 	rows    [][]string
+	nulls   [][]bool  // SQL NULL mask per row; a nil mask has no NULLs
+	widths  []float32 // measured width per column
+
+	// Client-side presentation; only touched on the UI goroutine.
+	view        resultView
+	visibleRows []int               // indices into rows, after filtering and sorting
+	visibleCols []int               // indices into columns, in display order
+	selected    *widget.TableCellID // selected cell in display coordinates
+
+	// OnExport is called when a file format is picked from the export menu.
+	OnExport func(format export.Format)
+	// OnSaveAsTable is called when "Save as BigQuery Table" is picked. The
+	// menu item is only shown when it is set.
+	OnSaveAsTable func()
+	// OnSaveSnapshot is called when "Save Snapshot" is picked. The menu item
+	// is only shown when it is set.
+	OnSaveSnapshot func()
+	// OnPin is called when the result is pinned for comparison. The Pin
+	// button is only shown when it is set.
+	OnPin func()
 
 	Container fyne.CanvasObject
 }
@@ -24,46 +68,64 @@ type Results struct {
 func NewResults() *Results {
 	r := &Results{
 		statusBar: widget.NewLabel("Ready"),
+		viewLabel: widget.NewLabel(""),
+		view:      newResultView(0),
 	}
 
-	r.table = widget.NewTableWithHeaders(
+	r.table = newResultsTable(
 		func() (int, int) {
-			if len(r.columns) == 0 {
+			if len(r.visibleCols) == 0 {
 				return 0, 0
 			}
-			return len(r.rows), len(r.columns)
+			return len(r.visibleRows), len(r.visibleCols)
 		},
 		func() fyne.CanvasObject {
-			txt := canvas.NewText("", color.White)
-			txt.TextSize = theme.Size(theme.SizeNameText)
-			return txt
+			return newResultCell(r.tapCell, r.showCellMenu)
 		},
 		func(id widget.TableCellID, obj fyne.CanvasObject) {
-			txt := obj.(*canvas.Text)
+			cell := obj.(*resultCell)
+			cell.id = id
+			bg, txt := cell.bg, cell.text
 			txt.TextSize = theme.Size(theme.SizeNameText)
 			txt.Color = theme.Color(theme.ColorNameForeground)
-			if id.Row < len(r.rows) && id.Col < len(r.rows[id.Row]) {
-				txt.Text = r.rows[id.Row][id.Col]
+			value := r.cellText(id.Row, id.Col)
+			txt.Text = value
+			if r.isNestedCol(id.Col) && value != "NULL" {
+				txt.Text = nestedPreview(value)
+			}
+			if r.view.search != "" && containsFold(value, r.view.search) {
+				bg.FillColor = theme.Color(theme.ColorNameSelection)
 			} else {
-				txt.Text = ""
+				bg.FillColor = color.Transparent
 			}
+			bg.Refresh()
 			txt.Refresh()
 		},
 	)
+	r.table.onCopy = r.CopyCell
+	r.table.OnSelected = func(id widget.TableCellID) {
+		r.selected = &id
+		r.copyBtn.Enable()
+		r.updateRecord()
+	}
 
 	r.table.CreateHeader = func() fyne.CanvasObject {
-		txt := canvas.NewText("", color.White)
-		txt.TextSize = theme.Size(theme.SizeNameText)
-		txt.TextStyle = fyne.TextStyle{Bold: true}
-		return txt
+		return newHeaderCell(r.toggleSort, r.showColumnMenu)
 	}
 	r.table.UpdateHeader = func(id widget.TableCellID, template fyne.CanvasObject) {
-		txt := template.(*canvas.Text)
+		h := template.(*headerCell)
+		txt := h.text
 		txt.TextSize = theme.Size(theme.SizeNameText)
 		txt.Color = theme.Color(theme.ColorNameForeground)
 		txt.TextStyle = fyne.TextStyle{Bold: true}
-		if id.Row < 0 && id.Col >= 0 && id.Col < len(r.columns) {
-			txt.Text = r.columns[id.Col]
+		h.col = -1
+		if id.Row < 0 && id.Col >= 0 && id.Col < len(r.visibleCols) {
+			src := r.visibleCols[id.Col]
+			h.col = src
+			txt.Text = r.columns[src] + r.view.sortIndicator(src)
+			if _, ok := r.view.filters[src]; ok {
+				txt.Text += " ⧩"
+			}
 		} else if id.Col < 0 && id.Row >= 0 {
 			txt.Text = fmt.Sprintf("%d", id.Row+1)
 		} else {
@@ -72,14 +134,497 @@ func NewResults() *Results {
 		txt.Refresh()
 	}
 
-	r.Container = container.NewBorder(nil, r.statusBar, nil, nil, r.table)
+	r.searchEntry = widget.NewEntry()
+	r.searchEntry.SetPlaceHolder("Search results...")
+	r.searchEntry.OnChanged = func(s string) {
+		r.view.search = strings.TrimSpace(s)
+		r.updateViewLabel()
+		r.table.Refresh()
+	}
+	r.clearBtn = widget.NewButton("Clear Filters", r.ClearFilters)
+	r.clearBtn.Hide()
+	r.columnsBtn = widget.NewButtonWithIcon("Columns", theme.Icon(theme.IconNameList), r.showColumnsMenu)
+	r.columnsBtn.Disable()
+
+	r.copyBtn = widget.NewButtonWithIcon("Copy", theme.ContentCopyIcon(), nil)
+	r.copyBtn.OnTapped = func() { showMenuBelow(r.copyMenu(), r.copyBtn) }
+	r.copyBtn.Disable()
+	r.recordBtn = widget.NewButtonWithIcon("Details", theme.Icon(theme.IconNameList), r.ToggleDetails)
+
+	r.recordBox = container.NewVBox()
+	r.viewer = NewJSONViewer()
+	r.recordTab = container.NewTabItem("Record", container.NewVScroll(r.recordBox))
+	r.valueTab = container.NewTabItem("Value", r.viewer.Container)
+	r.detailTabs = container.NewAppTabs(r.recordTab, r.valueTab)
+	r.recordSplit = container.NewHSplit(r.table, r.detailTabs)
+	r.recordSplit.Offset = 0.7
+	r.updateRecord()
+
+	r.exportBtn = widget.NewButtonWithIcon("Export", theme.DocumentSaveIcon(), nil)
+	r.exportBtn.OnTapped = r.showExportMenu
+	r.exportBtn.Disable()
+
+	top := container.NewBorder(nil, nil, nil,
+		container.NewHBox(r.viewLabel, r.clearBtn, r.columnsBtn, r.copyBtn, r.recordBtn), r.searchEntry)
+	r.pinBtn = widget.NewButtonWithIcon("Pin for Diff", theme.Icon(theme.IconNameRadioButtonChecked), func() {
+		if r.OnPin != nil {
+			r.OnPin()
+		}
+	})
+	r.pinBtn.Hide()
+
+	bottom := container.NewBorder(nil, nil, nil, container.NewHBox(r.pinBtn, r.exportBtn), r.statusBar)
+	r.body = container.NewStack(r.table)
+	r.Container = container.NewBorder(top, bottom, nil, nil, r.body)
 	return r
 }
 
-func (r *Results) SetData(columns []string, rows [][]string) {
-	r.columns = columns
-	r.rows = rows
+// ToggleDetails shows or hides the record and value panel beside the grid.
+func (r *Results) ToggleDetails() {
+	r.showRecord = !r.showRecord
+	if r.showRecord {
+		r.recordSplit.Leading = r.table
+		r.body.Objects = []fyne.CanvasObject{r.recordSplit}
+		r.recordBtn.Importance = widget.HighImportance
+	} else {
+		r.body.Objects = []fyne.CanvasObject{r.table}
+		r.recordBtn.Importance = widget.MediumImportance
+	}
+	r.recordSplit.Refresh()
+	r.body.Refresh()
+	r.recordBtn.Refresh()
+}
+
+// tapCell selects a cell. Tapping a STRUCT, ARRAY or JSON value opens it
+// in the value viewer.
+func (r *Results) tapCell(id widget.TableCellID) {
+	r.table.Select(id)
+	if c := r.canvas(); c != nil {
+		c.Focus(r.table)
+	}
+	if value := r.cellText(id.Row, id.Col); r.isNestedCol(id.Col) && value != "NULL" {
+		r.viewer.SetValue(value)
+		if !r.showRecord {
+			r.ToggleDetails()
+		}
+		r.detailTabs.Select(r.valueTab)
+	}
+}
+
+// isNestedCol reports whether a display column holds JSON-rendered values.
+func (r *Results) isNestedCol(col int) bool {
+	if col >= len(r.visibleCols) {
+		return false
+	}
+	c := r.visibleCols[col]
+	return c < len(r.types) && export.IsNested(r.types[c])
+}
+
+// updateRecord fills the record panel with the selected row's fields.
+func (r *Results) updateRecord() {
+	row := r.selectedRow()
+	if row < 0 {
+		r.recordBox.Objects = []fyne.CanvasObject{widget.NewLabel("Select a cell to see its row here.")}
+		r.recordBox.Refresh()
+		return
+	}
+	objs := []fyne.CanvasObject{
+		widget.NewLabelWithStyle(fmt.Sprintf("Row %d", r.selected.Row+1), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	}
+	for _, c := range r.visibleCols {
+		key := r.columns[c]
+		if c < len(r.types) && r.types[c] != "" {
+			key += "  " + r.types[c]
+		}
+		name := widget.NewLabelWithStyle(key, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		text := cellAt(r.rows[row], c)
+		if c < len(r.types) && export.IsNested(r.types[c]) && text != "NULL" {
+			if n, err := parseJSONTree(text); err == nil {
+				text = n.marshal()
+			}
+		}
+		value := widget.NewLabel(text)
+		value.Wrapping = fyne.TextWrapBreak
+		value.Selectable = true
+		objs = append(objs, name, value, widget.NewSeparator())
+	}
+	r.recordBox.Objects = objs
+	r.recordBox.Refresh()
+}
+
+// selectedRow returns the source index of the selected row, or -1.
+func (r *Results) selectedRow() int {
+	if r.selected == nil || r.selected.Row < 0 || r.selected.Row >= len(r.visibleRows) {
+		return -1
+	}
+	return r.visibleRows[r.selected.Row]
+}
+
+func (r *Results) copyMenu() *fyne.Menu {
+	return fyne.NewMenu("",
+		fyne.NewMenuItem("Copy Cell", r.CopyCell),
+		fyne.NewMenuItem("Copy Row as TSV", r.CopyRowTSV),
+		fyne.NewMenuItem("Copy Row as JSON", r.CopyRowJSON),
+		fyne.NewMenuItem("Copy Column", r.CopyColumn),
+	)
+}
+
+// showCellMenu selects a cell and shows its copy actions.
+func (r *Results) showCellMenu(id widget.TableCellID, pos fyne.Position) {
+	r.table.Select(id)
+	if c := r.canvas(); c != nil {
+		widget.ShowPopUpMenuAtPosition(r.copyMenu(), c, pos)
+	}
+}
+
+// CellText returns the selected cell's value.
+func (r *Results) CellText() string {
+	if r.selected == nil {
+		return ""
+	}
+	return r.cellText(r.selected.Row, r.selected.Col)
+}
+
+// RowTSV returns the selected row's visible values, tab-separated.
+func (r *Results) RowTSV() string {
+	row := r.selectedRow()
+	if row < 0 {
+		return ""
+	}
+	values := make([]string, len(r.visibleCols))
+	nulls := make([]bool, len(r.visibleCols))
+	for i, c := range r.visibleCols {
+		values[i] = cellAt(r.rows[row], c)
+		nulls[i] = r.isNull(row, c)
+	}
+	// TSVString writes an empty header line for nil columns; values never
+	// contain newlines, so trimming leaves just the row.
+	return strings.Trim(export.TSVString(nil, [][]string{values}, [][]bool{nulls}), "\n")
+}
+
+// RowJSON returns the selected row's visible columns as a JSON object,
+// with values typed by column type.
+func (r *Results) RowJSON() string {
+	row := r.selectedRow()
+	if row < 0 {
+		return ""
+	}
+	cols := make([]string, len(r.visibleCols))
+	types := make([]string, len(r.visibleCols))
+	values := make([]string, len(r.visibleCols))
+	nulls := make([]bool, len(r.visibleCols))
+	for i, c := range r.visibleCols {
+		cols[i] = r.columns[c]
+		if c < len(r.types) {
+			types[i] = r.types[c]
+		}
+		values[i] = cellAt(r.rows[row], c)
+		nulls[i] = r.isNull(row, c)
+	}
+	var b bytes.Buffer
+	if err := export.WriteAll(&b, export.JSONLines, cols, types, [][]string{values}, [][]bool{nulls}); err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// ColumnText returns the selected column's values for every displayed row,
+// one per line.
+func (r *Results) ColumnText() string {
+	if r.selected == nil || r.selected.Col >= len(r.visibleCols) {
+		return ""
+	}
+	c := r.visibleCols[r.selected.Col]
+	values := make([]string, len(r.visibleRows))
+	for i, ri := range r.visibleRows {
+		values[i] = cellAt(r.rows[ri], c)
+	}
+	return strings.Join(values, "\n")
+}
+
+func (r *Results) CopyCell()    { copyToClipboard(r.CellText()) }
+func (r *Results) CopyRowTSV()  { copyToClipboard(r.RowTSV()) }
+func (r *Results) CopyRowJSON() { copyToClipboard(r.RowJSON()) }
+func (r *Results) CopyColumn()  { copyToClipboard(r.ColumnText()) }
+
+func copyToClipboard(text string) {
+	if text == "" {
+		return
+	}
+	if cb := fyne.CurrentApp().Clipboard(); cb != nil {
+		cb.SetContent(text)
+	}
+}
+
+// cellText returns the value shown at a display position.
+func (r *Results) cellText(row, col int) string {
+	if row >= len(r.visibleRows) || col >= len(r.visibleCols) {
+		return ""
+	}
+	return cellAt(r.rows[r.visibleRows[row]], r.visibleCols[col])
+}
+
+// isNull reports whether a cell of the full result set is SQL NULL.
+func (r *Results) isNull(row, col int) bool {
+	return row < len(r.nulls) && col < len(r.nulls[row]) && r.nulls[row][col]
+}
+
+func (r *Results) showExportMenu() {
+	var items []*fyne.MenuItem
+	for _, f := range export.Formats {
+		items = append(items, fyne.NewMenuItem(f.Label()+"…", func() {
+			if r.OnExport != nil {
+				r.OnExport(f)
+			}
+		}))
+	}
+	items = append(items,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Copy as TSV", r.CopyTSV),
+	)
+	if r.OnSaveSnapshot != nil || r.OnSaveAsTable != nil {
+		items = append(items, fyne.NewMenuItemSeparator())
+	}
+	if r.OnSaveSnapshot != nil {
+		items = append(items, fyne.NewMenuItem("Save Snapshot…", r.OnSaveSnapshot))
+	}
+	if r.OnSaveAsTable != nil {
+		items = append(items, fyne.NewMenuItem("Save as BigQuery Table…", r.OnSaveAsTable))
+	}
+	showMenuBelow(fyne.NewMenu("", items...), r.exportBtn)
+}
+
+// showColumnsMenu lists every column with a check mark for visible ones.
+func (r *Results) showColumnsMenu() {
+	var items []*fyne.MenuItem
+	for _, c := range r.view.order {
+		item := fyne.NewMenuItem(r.columns[c], func() {
+			r.view.setHidden(c, !r.view.hidden[c])
+			r.refreshView()
+		})
+		item.Checked = !r.view.hidden[c]
+		items = append(items, item)
+	}
+	items = append(items,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Show All Columns", func() {
+			r.view.hidden = make(map[int]bool)
+			r.refreshView()
+		}),
+		fyne.NewMenuItem("Reset Column Order", func() {
+			for i := range r.view.order {
+				r.view.order[i] = i
+			}
+			r.refreshView()
+		}),
+	)
+	showMenuBelow(fyne.NewMenu("", items...), r.columnsBtn)
+}
+
+// showColumnMenu shows sort, filter and layout actions for one column.
+func (r *Results) showColumnMenu(col int, pos fyne.Position) {
+	if col < 0 || col >= len(r.columns) {
+		return
+	}
+	sortBy := func(desc bool) func() {
+		return func() {
+			r.view.sortCol, r.view.sortDesc = col, desc
+			r.refreshView()
+		}
+	}
+	freezeLabel := "Freeze Through This Column"
+	freeze := func() {
+		for i, c := range r.visibleCols {
+			if c == col {
+				r.view.frozen = i + 1
+			}
+		}
+		r.refreshView()
+	}
+	if r.view.frozen > 0 {
+		for i, c := range r.visibleCols {
+			if c == col && i == r.view.frozen-1 {
+				freezeLabel = "Unfreeze Columns"
+				freeze = func() {
+					r.view.frozen = 0
+					r.refreshView()
+				}
+			}
+		}
+	}
+	items := []*fyne.MenuItem{
+		fyne.NewMenuItem("Sort Ascending", sortBy(false)),
+		fyne.NewMenuItem("Sort Descending", sortBy(true)),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Filter…", func() { r.showFilterPopup(col, pos) }),
+	}
+	if _, ok := r.view.filters[col]; ok {
+		items = append(items, fyne.NewMenuItem("Clear Filter", func() {
+			r.view.setFilter(col, "")
+			r.refreshView()
+		}))
+	}
+	items = append(items,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Hide Column", func() {
+			r.view.setHidden(col, true)
+			r.refreshView()
+		}),
+		fyne.NewMenuItem("Move Left", func() {
+			r.view.moveColumn(col, -1)
+			r.refreshView()
+		}),
+		fyne.NewMenuItem("Move Right", func() {
+			r.view.moveColumn(col, 1)
+			r.refreshView()
+		}),
+		fyne.NewMenuItem(freezeLabel, freeze),
+	)
+	widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", items...), r.canvas(), pos)
+}
+
+// showFilterPopup shows a quick filter entry for a column. Rows are
+// filtered as the user types.
+func (r *Results) showFilterPopup(col int, pos fyne.Position) {
+	c := r.canvas()
+	if c == nil {
+		return
+	}
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("Rows where " + r.columns[col] + " contains...")
+	entry.SetText(r.view.filters[col])
+	entry.OnChanged = func(s string) {
+		r.view.setFilter(col, s)
+		r.refreshView()
+	}
+	var pop *widget.PopUp
+	entry.OnSubmitted = func(string) { pop.Hide() }
+	pop = widget.NewPopUp(entry, c)
+	pop.Resize(fyne.NewSize(260, entry.MinSize().Height))
+	pop.ShowAtPosition(pos)
+	c.Focus(entry)
+}
+
+func (r *Results) canvas() fyne.Canvas {
+	app := fyne.CurrentApp()
+	if app == nil {
+		return nil
+	}
+	return app.Driver().CanvasForObject(r.table)
+}
+
+func (r *Results) toggleSort(col int) {
+	if col < 0 {
+		return
+	}
+	r.view.toggleSort(col)
+	r.refreshView()
+}
+
+// ClearFilters removes all column filters and the search text.
+func (r *Results) ClearFilters() {
+	r.view.filters = make(map[int]string)
+	r.searchEntry.SetText("")
+	r.refreshView()
+}
+
+// refreshView recomputes visible rows and columns after a view change.
+// Must run on the UI goroutine.
+func (r *Results) refreshView() {
+	r.visibleCols = r.view.visibleColumns()
+	r.visibleRows = r.view.rowIndices(r.rows, r.types)
+	if r.view.frozen > len(r.visibleCols) {
+		r.view.frozen = len(r.visibleCols)
+	}
+	r.table.StickyColumnCount = r.view.frozen
+
+	// Display positions change with the view, so drop the selection.
+	r.selected = nil
+	r.table.UnselectAll()
+	r.copyBtn.Disable()
+	r.updateRecord()
+
+	for i, c := range r.visibleCols {
+		if c < len(r.widths) {
+			r.table.SetColumnWidth(i, r.widths[c])
+		}
+	}
+	r.updateViewLabel()
+	r.table.Refresh()
+}
+
+func (r *Results) updateViewLabel() {
+	var parts []string
+	if len(r.visibleRows) != len(r.rows) {
+		parts = append(parts, fmt.Sprintf("%d of %d rows", len(r.visibleRows), len(r.rows)))
+	}
+	if r.view.search != "" {
+		parts = append(parts, fmt.Sprintf("%d matches", r.countMatches()))
+	}
+	if hidden := len(r.columns) - len(r.visibleCols); hidden > 0 {
+		parts = append(parts, fmt.Sprintf("%d hidden", hidden))
+	}
+	r.viewLabel.SetText(strings.Join(parts, " | "))
+	if len(r.view.filters) > 0 || r.view.search != "" {
+		r.clearBtn.Show()
+	} else {
+		r.clearBtn.Hide()
+	}
+}
 
+func (r *Results) countMatches() int {
+	n := 0
+	for _, ri := range r.visibleRows {
+		for _, c := range r.visibleCols {
+			if containsFold(cellAt(r.rows[ri], c), r.view.search) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// viewData returns the rows and columns as currently displayed, after
+// filtering, sorting and hiding columns, with their NULL masks.
+func (r *Results) viewData() (columns []string, rows [][]string, nulls [][]bool) {
+	for _, c := range r.visibleCols {
+		columns = append(columns, r.columns[c])
+	}
+	rows = make([][]string, len(r.visibleRows))
+	nulls = make([][]bool, len(r.visibleRows))
+	for i, ri := range r.visibleRows {
+		row := make([]string, len(r.visibleCols))
+		rowNulls := make([]bool, len(r.visibleCols))
+		for j, c := range r.visibleCols {
+			row[j] = cellAt(r.rows[ri], c)
+			rowNulls[j] = r.isNull(ri, c)
+		}
+		rows[i] = row
+		nulls[i] = rowNulls
+	}
+	return columns, rows, nulls
+}
+
+// CopyTSV copies the displayed rows to the clipboard as tab-separated text,
+// which spreadsheets paste as cells.
+func (r *Results) CopyTSV() {
+	if len(r.visibleCols) == 0 {
+		return
+	}
+	columns, rows, nulls := r.viewData()
+	copyToClipboard(export.TSVString(columns, rows, nulls))
+}
+
+// Data returns the full result set and its NULL masks, ignoring filters and
+// column layout.
+func (r *Results) Data() (columns, types []string, rows [][]string, nulls [][]bool) {
+	return r.columns, r.types, r.rows, r.nulls
+}
+
+// SetData shows a result set. types holds the BigQuery type of each column
+// and may be nil; nulls marks the SQL NULL cells of each row, as in
+// bq.QueryResult.Nulls. Sorting, filters and column layout are reset.
+func (r *Results) SetData(columns, types []string, rows [][]string, nulls [][]bool) {
 	// Measure column widths based on content.
 	textSize := fyne.CurrentApp().Settings().Theme().Size("text")
 	boldStyle := fyne.TextStyle{Bold: true}
@@ -95,7 +640,8 @@ func (r *Results) SetData(columns []string, rows [][]string) {
 
 	widths := make([]float32, len(columns))
 	for i, col := range columns {
-		w := fyne.MeasureText(col, textSize, boldStyle).Width + padding
+		// Leave room for the sort and filter indicators.
+		w := fyne.MeasureText(col+" ▲ ⧩", textSize, boldStyle).Width + padding
 		widths[i] = w
 	}
 
@@ -107,18 +653,35 @@ func (r *Results) SetData(columns []string, rows [][]string) {
 			}
 		}
 	}
+	for i, w := range widths {
+		if w < minWidth {
+			widths[i] = minWidth
+		}
+		if w > maxWidth {
+			widths[i] = maxWidth
+		}
+	}
 
 	fyne.Do(func() {
-		for i, w := range widths {
-			if w < minWidth {
-				w = minWidth
+		r.columns = columns
+		r.types = types
+		r.rows = rows
+		r.nulls = nulls
+		r.widths = widths
+		r.view = newResultView(len(columns))
+		r.searchEntry.SetText("")
+		if len(columns) > 0 {
+			r.exportBtn.Enable()
+			r.columnsBtn.Enable()
+			if r.OnPin != nil {
+				r.pinBtn.Show()
 			}
-			if w > maxWidth {
-				w = maxWidth
-			}
-			r.table.SetColumnWidth(i, w)
+		} else {
+			r.exportBtn.Disable()
+			r.columnsBtn.Disable()
+			r.pinBtn.Hide()
 		}
-		r.table.Refresh()
+		r.refreshView()
 	})
 }
 
@@ -129,10 +692,120 @@ func (r *Results) SetStatus(text string) {
 }
 
 func (r *Results) Clear() {
-	r.columns = nil
-	r.rows = nil
 	fyne.Do(func() {
-		r.table.Refresh()
+		r.columns = nil
+		r.types = nil
+		r.rows = nil
+		r.nulls = nil
+		r.widths = nil
+		r.view = newResultView(0)
+		r.exportBtn.Disable()
+		r.columnsBtn.Disable()
+		r.pinBtn.Hide()
+		r.refreshView()
 		r.statusBar.SetText("Ready")
 	})
 }
+
+// showMenuBelow pops up a menu under a button.
+func showMenuBelow(menu *fyne.Menu, anchor fyne.CanvasObject) {
+	d := fyne.CurrentApp().Driver()
+	pos := d.AbsolutePositionForObject(anchor).Add(fyne.NewPos(0, anchor.Size().Height))
+	widget.ShowPopUpMenuAtPosition(menu, d.CanvasForObject(anchor), pos)
+}
+
+// headerCell is a results column header: tap to sort, secondary tap for
+// the column menu.
+type headerCell struct {
+	widget.BaseWidget
+	text *canvas.Text
+	col  int // source column index, -1 for row number headers
+
+	onTap  func(col int)
+	onMenu func(col int, pos fyne.Position)
+}
+
+func newHeaderCell(onTap func(col int), onMenu func(col int, pos fyne.Position)) *headerCell {
+	txt := canvas.NewText("", color.White)
+	txt.TextSize = theme.Size(theme.SizeNameText)
+	txt.TextStyle = fyne.TextStyle{Bold: true}
+	h := &headerCell{text: txt, col: -1, onTap: onTap, onMenu: onMenu}
+	h.ExtendBaseWidget(h)
+	return h
+}
+
+func (h *headerCell) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(h.text)
+}
+
+func (h *headerCell) Tapped(*fyne.PointEvent) {
+	if h.col >= 0 && h.onTap != nil {
+		h.onTap(h.col)
+	}
+}
+
+func (h *headerCell) TappedSecondary(e *fyne.PointEvent) {
+	if h.col >= 0 && h.onMenu != nil {
+		h.onMenu(h.col, e.AbsolutePosition)
+	}
+}
+
+// resultsTable is a Table that copies the selected cell on Cmd/Ctrl+C.
+type resultsTable struct {
+	widget.Table
+	onCopy func()
+}
+
+func newResultsTable(length func() (int, int), create func() fyne.CanvasObject, update func(widget.TableCellID, fyne.CanvasObject)) *resultsTable {
+	t := &resultsTable{}
+	t.Length = length
+	t.CreateCell = create
+	t.UpdateCell = update
+	t.ShowHeaderRow = true
+	t.ShowHeaderColumn = true
+	t.ExtendBaseWidget(t)
+	return t
+}
+
+func (t *resultsTable) TypedShortcut(s fyne.Shortcut) {
+	if _, ok := s.(*fyne.ShortcutCopy); ok && t.onCopy != nil {
+		t.onCopy()
+	}
+}
+
+// resultCell is a grid cell. Tap selects it; secondary tap opens the copy
+// menu. The cell handles both because the driver delivers taps only to the
+// topmost tappable object, which would otherwise hide them from the table.
+type resultCell struct {
+	widget.BaseWidget
+	bg   *canvas.Rectangle
+	text *canvas.Text
+	id   widget.TableCellID
+
+	onTap  func(id widget.TableCellID)
+	onMenu func(id widget.TableCellID, pos fyne.Position)
+}
+
+func newResultCell(onTap func(id widget.TableCellID), onMenu func(id widget.TableCellID, pos fyne.Position)) *resultCell {
+	txt := canvas.NewText("", color.White)
+	txt.TextSize = theme.Size(theme.SizeNameText)
+	c := &resultCell{bg: canvas.NewRectangle(color.Transparent), text: txt, onTap: onTap, onMenu: onMenu}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+func (c *resultCell) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewStack(c.bg, c.text))
+}
+
+func (c *resultCell) Tapped(*fyne.PointEvent) {
+	if c.onTap != nil {
+		c.onTap(c.id)
+	}
+}
+
+func (c *resultCell) TappedSecondary(e *fyne.PointEvent) {
+	if c.onMenu != nil {
+		c.onMenu(c.id, e.AbsolutePosition)
+	}
+}
@@ -0,0 +1,198 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+var viewRows = [][]string{
+	{"10", "banana", "true"},
+	{"9", "Apple", "NULL"},
+	{"NULL", "cherry", "false"},
+	{"100", "apple pie", "true"},
+}
+
+var viewTypes = []string{"INTEGER", "STRING", "BOOLEAN"}
+
+// viewNulls marks the "NULL" cells of viewRows as SQL NULL.
+var viewNulls = [][]bool{nil, {false, false, true}, {true, false, false}, nil}
+
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		a, b, typ string
+		want      int
+	}{
+		{"9", "10", "INTEGER", -1},
+		{"9", "10", "STRING", 1},
+		{"1.5", "-2", "FLOAT", 1},
+		{"9007199254740993", "9007199254740992", "INT64", 1}, // beyond float64 precision
+		{"-9223372036854775808", "9223372036854775807", "INTEGER", -1},
+		{"12345678901234567890.000000001", "12345678901234567890", "NUMERIC", 1},
+		{"1E+3", "999.5", "BIGNUMERIC", 1},
+		{"12.50", "12.5", "NUMERIC", 0},
+		{"NULL", "0", "INTEGER", -1},
+		{"0", "NULL", "INTEGER", 1},
+		{"false", "true", "BOOLEAN", -1},
+		{"abc", "10", "INTEGER", 1}, // unparseable falls back to string order
+		{"2024-01-02", "2023-12-31", "DATE", 1},
+	}
+	for _, tt := range tests {
+		if got := compareValues(tt.a, tt.b, tt.typ); got != tt.want {
+			t.Errorf("compareValues(%q, %q, %s) = %d, want %d", tt.a, tt.b, tt.typ, got, tt.want)
+		}
+	}
+}
+
+func TestResultView_SortCycle(t *testing.T) {
+	v := newResultView(3)
+	v.toggleSort(0)
+	if got := v.rowIndices(viewRows, viewTypes); !reflect.DeepEqual(got, []int{2, 1, 0, 3}) {
+		t.Errorf("ascending = %v, want NULL first then numeric order", got)
+	}
+	v.toggleSort(0)
+	if got := v.rowIndices(viewRows, viewTypes); !reflect.DeepEqual(got, []int{3, 0, 1, 2}) {
+		t.Errorf("descending = %v", got)
+	}
+	v.toggleSort(0)
+	if got := v.rowIndices(viewRows, viewTypes); !reflect.DeepEqual(got, []int{0, 1, 2, 3}) {
+		t.Errorf("unsorted = %v, want original order", got)
+	}
+}
+
+func TestResultView_Filters(t *testing.T) {
+	v := newResultView(3)
+	v.setFilter(1, "APPLE")
+	if got := v.rowIndices(viewRows, viewTypes); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Errorf("filter on name = %v, want case-insensitive matches", got)
+	}
+	v.setFilter(2, "true")
+	if got := v.rowIndices(viewRows, viewTypes); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("two filters = %v, want rows matching both", got)
+	}
+	v.setFilter(1, "")
+	if _, ok := v.filters[1]; ok {
+		t.Error("empty filter text should remove the filter")
+	}
+}
+
+func TestResultView_HideAndMove(t *testing.T) {
+	v := newResultView(3)
+	v.moveColumn(2, -1)
+	if got := v.visibleColumns(); !reflect.DeepEqual(got, []int{0, 2, 1}) {
+		t.Errorf("after move left = %v", got)
+	}
+	v.setHidden(2, true)
+	if got := v.visibleColumns(); !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Errorf("after hide = %v", got)
+	}
+	v.moveColumn(0, 1) // moves past the hidden column
+	if got := v.visibleColumns(); !reflect.DeepEqual(got, []int{1, 0}) {
+		t.Errorf("after move right = %v", got)
+	}
+	v.moveColumn(0, 1) // already last: no-op
+	if got := v.visibleColumns(); !reflect.DeepEqual(got, []int{1, 0}) {
+		t.Errorf("move past end = %v", got)
+	}
+
+	v.frozen = 2
+	v.setHidden(1, true)
+	if v.frozen != 1 {
+		t.Errorf("frozen = %d, want clamped to visible column count", v.frozen)
+	}
+	v.setHidden(0, true)
+	if got := v.visibleColumns(); len(got) != 1 {
+		t.Errorf("last visible column was hidden: %v", got)
+	}
+}
+
+func TestResults_ViewData(t *testing.T) {
+	r := NewResults()
+	r.SetData([]string{"n", "name", "ok"}, viewTypes, viewRows, viewNulls)
+
+	r.view.setHidden(2, true)
+	r.view.setFilter(1, "apple")
+	r.toggleSort(0)
+
+	cols, rows, _ := r.viewData()
+	if !reflect.DeepEqual(cols, []string{"n", "name"}) {
+		t.Errorf("columns = %v", cols)
+	}
+	want := [][]string{{"9", "Apple"}, {"100", "apple pie"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+	if r.table.StickyColumnCount != 0 {
+		t.Errorf("StickyColumnCount = %d, want 0", r.table.StickyColumnCount)
+	}
+
+	r.ClearFilters()
+	if _, rows, _ := r.viewData(); len(rows) != len(viewRows) {
+		t.Errorf("after ClearFilters got %d rows, want %d", len(rows), len(viewRows))
+	}
+}
+
+func TestResults_CopySelection(t *testing.T) {
+	r := NewResults()
+	r.SetData([]string{"n", "name", "ok"}, viewTypes, viewRows, viewNulls)
+	r.toggleSort(0) // NULL, 9, 10, 100
+
+	if r.CellText() != "" || r.RowJSON() != "" {
+		t.Fatal("expected empty copies without a selection")
+	}
+
+	r.table.Select(widget.TableCellID{Row: 1, Col: 1})
+	if got := r.CellText(); got != "Apple" {
+		t.Errorf("CellText = %q, want value at the sorted position", got)
+	}
+	if got := r.RowTSV(); got != "9\tApple\t" { // NULL is an empty field, as in TSV export
+		t.Errorf("RowTSV = %q", got)
+	}
+	if got := r.RowJSON(); got != `{"n":9,"name":"Apple","ok":null}` {
+		t.Errorf("RowJSON = %s", got)
+	}
+	if got := r.ColumnText(); got != "cherry\nApple\nbanana\napple pie" {
+		t.Errorf("ColumnText = %q", got)
+	}
+
+	r.view.setHidden(0, true)
+	r.refreshView()
+	if r.selected != nil {
+		t.Error("view change should clear the selection")
+	}
+}
+
+func TestResults_CopyStringNull(t *testing.T) {
+	r := NewResults()
+	r.SetData([]string{"n", "name", "ok"}, viewTypes,
+		[][]string{{"1", "NULL", "NULL"}}, [][]bool{{false, false, true}})
+	r.table.Select(widget.TableCellID{Row: 0, Col: 1})
+
+	if got := r.RowTSV(); got != "1\tNULL\t" {
+		t.Errorf("RowTSV = %q, want the string NULL kept", got)
+	}
+	if got := r.RowJSON(); got != `{"n":1,"name":"NULL","ok":null}` {
+		t.Errorf("RowJSON = %s", got)
+	}
+	if _, _, nulls := r.viewData(); !reflect.DeepEqual(nulls, [][]bool{{false, false, true}}) {
+		t.Errorf("view nulls = %v", nulls)
+	}
+}
+
+func TestResults_RecordPanel(t *testing.T) {
+	r := NewResults()
+	r.SetData([]string{"n", "name", "ok"}, viewTypes, viewRows, viewNulls)
+	r.ToggleDetails()
+	r.table.Select(widget.TableCellID{Row: 3, Col: 0})
+
+	// Title plus name, value and separator per column.
+	if got := len(r.recordBox.Objects); got != 1+3*3 {
+		t.Fatalf("record panel has %d objects, want 10", got)
+	}
+	value := r.recordBox.Objects[5].(*widget.Label)
+	if value.Text != "apple pie" || value.Wrapping != fyne.TextWrapBreak {
+		t.Errorf("name field = %q (wrapping %v), want full wrapped value", value.Text, value.Wrapping)
+	}
+}
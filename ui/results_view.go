@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"cmp"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// resultView holds the client-side presentation of a result set: sort
+// order, filters, search and column layout. Column indices refer to the
+// columns as returned by the query; nothing here modifies the rows.
+type resultView struct {
+	sortCol  int // -1 when unsorted
+	sortDesc bool
+
+	filters map[int]string // per-column substring filter, case-insensitive
+	search  string         // highlights matching cells; does not filter
+
+	order  []int // display order of all columns, hidden ones included
+	hidden map[int]bool
+	frozen int // leading visible columns that stay in place when scrolling
+}
+
+func newResultView(numCols int) resultView {
+	order := make([]int, numCols)
+	for i := range order {
+		order[i] = i
+	}
+	return resultView{
+		sortCol: -1,
+		filters: make(map[int]string),
+		order:   order,
+		hidden:  make(map[int]bool),
+	}
+}
+
+// visibleColumns returns the displayed columns in display order.
+func (v *resultView) visibleColumns() []int {
+	cols := make([]int, 0, len(v.order))
+	for _, c := range v.order {
+		if !v.hidden[c] {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
+// rowIndices returns the indices of rows that pass the filters, in sort order.
+func (v *resultView) rowIndices(rows [][]string, types []string) []int {
+	idx := make([]int, 0, len(rows))
+	for i, row := range rows {
+		if v.keep(row) {
+			idx = append(idx, i)
+		}
+	}
+	if v.sortCol >= 0 {
+		typ := ""
+		if v.sortCol < len(types) {
+			typ = types[v.sortCol]
+		}
+		col := v.sortCol
+		sort.SliceStable(idx, func(a, b int) bool {
+			c := compareValues(cellAt(rows[idx[a]], col), cellAt(rows[idx[b]], col), typ)
+			if v.sortDesc {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+	return idx
+}
+
+func (v *resultView) keep(row []string) bool {
+	for col, f := range v.filters {
+		if !containsFold(cellAt(row, col), f) {
+			return false
+		}
+	}
+	return true
+}
+
+// toggleSort cycles a column through ascending, descending and unsorted.
+func (v *resultView) toggleSort(col int) {
+	switch {
+	case v.sortCol != col:
+		v.sortCol, v.sortDesc = col, false
+	case !v.sortDesc:
+		v.sortDesc = true
+	default:
+		v.sortCol, v.sortDesc = -1, false
+	}
+}
+
+// setFilter sets or, with an empty text, clears a column filter.
+func (v *resultView) setFilter(col int, text string) {
+	if text == "" {
+		delete(v.filters, col)
+		return
+	}
+	v.filters[col] = text
+}
+
+// moveColumn moves a column one visible position left (delta -1) or right (+1).
+func (v *resultView) moveColumn(col, delta int) {
+	visible := v.visibleColumns()
+	pos := -1
+	for i, c := range visible {
+		if c == col {
+			pos = i
+			break
+		}
+	}
+	target := pos + delta
+	if pos < 0 || target < 0 || target >= len(visible) {
+		return
+	}
+	other := visible[target]
+	var a, b int
+	for i, c := range v.order {
+		switch c {
+		case col:
+			a = i
+		case other:
+			b = i
+		}
+	}
+	v.order[a], v.order[b] = v.order[b], v.order[a]
+}
+
+// setHidden hides or shows a column. The last visible column cannot be hidden.
+func (v *resultView) setHidden(col int, hidden bool) {
+	if hidden && len(v.visibleColumns()) <= 1 {
+		return
+	}
+	if hidden {
+		v.hidden[col] = true
+	} else {
+		delete(v.hidden, col)
+	}
+	if n := len(v.visibleColumns()); v.frozen > n {
+		v.frozen = n
+	}
+}
+
+// sortIndicator returns the arrow shown after a sorted column's header.
+func (v *resultView) sortIndicator(col int) string {
+	if v.sortCol != col {
+		return ""
+	}
+	if v.sortDesc {
+		return " ▼"
+	}
+	return " ▲"
+}
+
+// compareValues orders two display values of the given BigQuery type.
+// NULL sorts before everything else; values that fail to parse as their
+// type fall back to string order.
+func compareValues(a, b, typ string) int {
+	if a == b {
+		return 0
+	}
+	if a == "NULL" {
+		return -1
+	}
+	if b == "NULL" {
+		return 1
+	}
+	switch typ {
+	case "INTEGER", "INT64":
+		ia, errA := strconv.ParseInt(a, 10, 64)
+		ib, errB := strconv.ParseInt(b, 10, 64)
+		if errA == nil && errB == nil {
+			return cmp.Compare(ia, ib)
+		}
+	case "NUMERIC", "BIGNUMERIC":
+		// Exact decimals: 38 or more digits do not fit a float64.
+		ra, okA := new(big.Rat).SetString(a)
+		rb, okB := new(big.Rat).SetString(b)
+		if okA && okB {
+			return ra.Cmp(rb)
+		}
+	case "FLOAT", "FLOAT64":
+		fa, errA := strconv.ParseFloat(a, 64)
+		fb, errB := strconv.ParseFloat(b, 64)
+		if errA == nil && errB == nil {
+			return cmp.Compare(fa, fb)
+		}
+	case "BOOLEAN", "BOOL":
+		ba, errA := strconv.ParseBool(a)
+		bb, errB := strconv.ParseBool(b)
+		if errA == nil && errB == nil {
+			switch {
+			case !ba && bb:
+				return -1
+			case ba && !bb:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func cellAt(row []string, col int) string {
+	if col < len(row) {
+		return row[col]
+	}
+	return ""
+}
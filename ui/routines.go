@@ -0,0 +1,70 @@
+package ui
+
+import "strings"
+
+// Routine is a user-defined function, table function or stored procedure
+// known to autocomplete.
+type Routine struct {
+	Project   string
+	Dataset   string
+	Name      string
+	Signature string // e.g. "add_tax(amount NUMERIC, rate FLOAT64) RETURNS NUMERIC"; empty until loaded
+}
+
+// routineIndex maps lower-case "project.dataset.routine" and
+// "dataset.routine" names to routines; the shorter form refers to the first
+// routine of that name.
+func routineIndex(routines []Routine) map[string]Routine {
+	idx := make(map[string]Routine, 2*len(routines))
+	for _, r := range routines {
+		short := strings.ToLower(r.Dataset + "." + r.Name)
+		idx[strings.ToLower(r.Project)+"."+short] = r
+		if _, ok := idx[short]; !ok {
+			idx[short] = r
+		}
+	}
+	return idx
+}
+
+// routineCallAt returns the name of the routine whose argument list holds
+// column col of line, e.g. "p.ds.f" for "SELECT `p.ds.f`(1, |", or "" when
+// the position is not inside a call of a qualified name.
+func routineCallAt(line string, col int) string {
+	if col > len(line) {
+		col = len(line)
+	}
+	depth := 0
+	open := -1
+	for i := col - 1; i >= 0 && open < 0; i-- {
+		switch line[i] {
+		case ')':
+			depth++
+		case '(':
+			if depth == 0 {
+				open = i
+			}
+			depth--
+		}
+	}
+	if open < 0 {
+		return ""
+	}
+	end := open
+	for end > 0 && line[end-1] == ' ' {
+		end--
+	}
+	start := end
+	for start > 0 {
+		b := line[start-1]
+		if isWordByte(b) || b == '.' || b == '`' || b == '-' {
+			start--
+		} else {
+			break
+		}
+	}
+	name := strings.ReplaceAll(line[start:end], "`", "")
+	if !strings.Contains(name, ".") {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestRoutineCallAt(t *testing.T) {
+	for _, tc := range []struct {
+		line string
+		want string
+	}{
+		{"SELECT p.ds.f(", "p.ds.f"},
+		{"SELECT `my-proj.ds.f`(1, ", "my-proj.ds.f"},
+		{"SELECT ds.f (a, g(b), ", "ds.f"},
+		{"SELECT p.ds.f(1)", ""},
+		{"SELECT f(", ""},
+		{"SELECT (", ""},
+		{"SELECT 1", ""},
+	} {
+		if got := routineCallAt(tc.line, len(tc.line)); got != tc.want {
+			t.Errorf("routineCallAt(%q) = %q, want %q", tc.line, got, tc.want)
+		}
+	}
+}
+
+func TestRoutineIndex(t *testing.T) {
+	idx := routineIndex([]Routine{
+		{Project: "p1", Dataset: "ds", Name: "F", Signature: "F() RETURNS INT64"},
+		{Project: "p2", Dataset: "ds", Name: "f", Signature: "f(x INT64)"},
+	})
+	if idx["p1.ds.f"].Project != "p1" || idx["p2.ds.f"].Project != "p2" {
+		t.Errorf("full names not indexed: %v", idx)
+	}
+	if idx["ds.f"].Project != "p1" {
+		t.Errorf("short name should refer to the first routine, got %q", idx["ds.f"].Project)
+	}
+}
+
+func setupEditorWithRoutines(t *testing.T) *SQLEditor {
+	t.Helper()
+	e := setupEditorWithProjectData(t)
+	e.SetRoutines([]Routine{
+		{Project: "my-project", Dataset: "dataset_a", Name: "add_tax", Signature: "add_tax(amount NUMERIC) RETURNS NUMERIC"},
+		{Project: "my-project", Dataset: "dataset_b", Name: "refresh", Signature: "refresh()"},
+	})
+	return e
+}
+
+func TestUpdateAC_DottedDataset_ShowsRoutines(t *testing.T) {
+	e := setupEditorWithRoutines(t)
+	e.lines = []string{"SELECT my-project.dataset_a."}
+	e.cursorCol = len(e.lines[0])
+
+	e.updateAutocomplete()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	got := append([]string(nil), e.acFiltered...)
+	sort.Strings(got)
+	want := []string{"add_tax", "events", "orders", "users"}
+	if len(got) != len(want) {
+		t.Fatalf("candidates = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("candidates = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestUpdateAC_RoutineCall_ShowsSignature(t *testing.T) {
+	e := setupEditorWithRoutines(t)
+	e.lines = []string{"SELECT `my-project.dataset_a.add_tax`(price, "}
+	e.cursorCol = len(e.lines[0])
+
+	e.updateAutocomplete()
+
+	e.mu.Lock()
+	if !e.acVisible || !e.acHint {
+		t.Fatalf("expected the signature hint, visible=%v hint=%v", e.acVisible, e.acHint)
+	}
+	if len(e.acFiltered) != 1 || e.acFiltered[0] != "add_tax(amount NUMERIC) RETURNS NUMERIC" {
+		t.Errorf("hint = %v", e.acFiltered)
+	}
+	e.mu.Unlock()
+
+	// The hint cannot be accepted into the text.
+	e.acceptCompletion()
+	if e.Text() != "SELECT `my-project.dataset_a.add_tax`(price, " {
+		t.Errorf("text changed to %q", e.Text())
+	}
+
+	// Typing an argument switches back to completions.
+	e.lines = []string{"SELECT my-project.dataset_a.add_tax(SEL"}
+	e.cursorCol = len(e.lines[0])
+	e.updateAutocomplete()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.acHint {
+		t.Error("expected completions, not the hint, while typing a word")
+	}
+}
+
+func TestUpdateAC_UnknownRoutineCall_NoHint(t *testing.T) {
+	e := setupEditorWithRoutines(t)
+	e.lines = []string{"SELECT my-project.dataset_a.other("}
+	e.cursorCol = len(e.lines[0])
+
+	e.updateAutocomplete()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.acVisible {
+		t.Errorf("expected no popup, got %v", e.acFiltered)
+	}
+}
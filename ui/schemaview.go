@@ -21,7 +21,11 @@ type SchemaView struct {
 	titleBar *widget.Label
 	fields   []SchemaField
 
-	OnClose   func()
+	project, dataset, tableID string
+
+	OnClose func()
+	// OnProfile is called to compute column statistics for the shown table.
+	OnProfile func(project, dataset, table string)
 	Container fyne.CanvasObject
 }
 
@@ -77,7 +81,12 @@ func NewSchemaView() *SchemaView {
 			s.OnClose()
 		}
 	})
-	topRow := container.NewBorder(nil, nil, nil, closeBtn, nil)
+	profileBtn := widget.NewButtonWithIcon("Profile", theme.Icon(theme.IconNameSearch), func() {
+		if s.OnProfile != nil && s.tableID != "" {
+			s.OnProfile(s.project, s.dataset, s.tableID)
+		}
+	})
+	topRow := container.NewBorder(nil, nil, nil, container.NewHBox(profileBtn, closeBtn), nil)
 	s.Container = container.NewBorder(topRow, nil, nil, nil, s.table)
 	return s
 }
@@ -85,6 +94,7 @@ func NewSchemaView() *SchemaView {
 func (s *SchemaView) SetSchema(project, dataset, table string, fields []SchemaField) {
 	s.fields = fields
 	fyne.Do(func() {
+		s.project, s.dataset, s.tableID = project, dataset, table
 		s.titleBar.SetText(fmt.Sprintf("%s.%s.%s", project, dataset, table))
 		s.table.Refresh()
 	})
@@ -93,6 +103,7 @@ func (s *SchemaView) SetSchema(project, dataset, table string, fields []SchemaFi
 func (s *SchemaView) Clear() {
 	s.fields = nil
 	fyne.Do(func() {
+		s.project, s.dataset, s.tableID = "", "", ""
 		s.titleBar.SetText("Select a table to view schema")
 		s.table.Refresh()
 	})
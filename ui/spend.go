@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// SpendView selects how the Spend tab groups the cost ledger.
+type SpendView int
+
+const (
+	SpendDays SpendView = iota
+	SpendProjects
+	SpendQueries
+	SpendRepeated // queries run more than once, most expensive first
+)
+
+var spendViews = []string{"By Day", "By Project", "By Query", "Repeated Queries"}
+
+var spendPeriods = []string{"Last 7 days", "Last 30 days", "This month", "Last 90 days", "All time"}
+
+var spendColumns = []string{"", "Runs", "Billed", "Est. Cost", "Avg / Run", "Share", "Projects", "Last Run"}
+
+var spendWidths = []float32{360, 60, 100, 90, 90, 150, 160, 130}
+
+// SpendRow is the spend of one day, project or query shape.
+type SpendRow struct {
+	Key         string // day, project or query fingerprint
+	SQL         string // latest query text, for query rows
+	Projects    string
+	Runs        int64
+	BytesBilled int64
+	Cost        float64
+	Last        time.Time
+}
+
+// Spend shows the estimated on-demand cost of past queries from the local
+// cost ledger.
+type Spend struct {
+	table   *widget.Table
+	view    *widget.RadioGroup
+	period  *widget.Select
+	summary *widget.Label
+	rows    []SpendRow
+	shown   []SpendRow
+	now     func() time.Time
+
+	// OnRefresh is called when the view or period changes; the app answers
+	// with SetRows for Query.
+	OnRefresh func()
+	// OnOpenQuery opens a query row's latest SQL in the editor.
+	OnOpenQuery func(sql, project string)
+
+	Container fyne.CanvasObject
+}
+
+func NewSpend() *Spend {
+	s := &Spend{
+		summary: widget.NewLabel("No queries recorded yet."),
+		now:     time.Now,
+	}
+	refresh := func(string) {
+		if s.OnRefresh != nil {
+			s.OnRefresh()
+		}
+	}
+	s.view = widget.NewRadioGroup(spendViews, refresh)
+	s.view.Horizontal = true
+	s.view.Required = true
+	s.view.Selected = spendViews[0]
+	s.period = widget.NewSelect(spendPeriods, refresh)
+	s.period.Selected = spendPeriods[1]
+
+	s.table = widget.NewTableWithHeaders(
+		func() (int, int) { return len(s.shown), len(spendColumns) },
+		func() fyne.CanvasObject {
+			l := widget.NewLabel("")
+			l.Truncation = fyne.TextTruncateEllipsis
+			return l
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			if id.Row < len(s.shown) {
+				obj.(*widget.Label).SetText(s.cell(s.shown[id.Row], id.Col))
+			}
+		},
+	)
+	s.table.UpdateHeader = func(id widget.TableCellID, template fyne.CanvasObject) {
+		label := template.(*widget.Label)
+		if id.Row < 0 && id.Col >= 0 && id.Col < len(spendColumns) {
+			text := spendColumns[id.Col]
+			if id.Col == 0 {
+				text = []string{"Day", "Project", "Query", "Query"}[s.View()]
+			}
+			label.SetText(text)
+		} else if id.Col < 0 && id.Row >= 0 {
+			label.SetText(fmt.Sprintf("%d", id.Row+1))
+		}
+	}
+	for i, w := range spendWidths {
+		s.table.SetColumnWidth(i, w)
+	}
+	s.table.OnSelected = func(id widget.TableCellID) {
+		s.table.UnselectAll()
+		if id.Row < 0 || id.Row >= len(s.shown) || s.shown[id.Row].SQL == "" || s.OnOpenQuery == nil {
+			return
+		}
+		r := s.shown[id.Row]
+		project, _, _ := strings.Cut(r.Projects, ",")
+		s.OnOpenQuery(r.SQL, project)
+	}
+
+	refreshBtn := widget.NewButton("Refresh", func() { refresh("") })
+	toolbar := container.NewHBox(s.period, s.view, refreshBtn)
+	note := widget.NewLabel("Estimates use on-demand pricing per TiB billed; set the rate under Query Settings. Select a query to open it.")
+	note.Truncation = fyne.TextTruncateEllipsis
+	s.Container = container.NewBorder(container.NewVBox(toolbar, s.summary), note, nil, nil, s.table)
+	return s
+}
+
+// View returns the selected grouping.
+func (s *Spend) View() SpendView {
+	for i, v := range spendViews {
+		if s.view.Selected == v {
+			return SpendView(i)
+		}
+	}
+	return SpendDays
+}
+
+// Since returns the start of the selected period, or the zero time for all
+// time.
+func (s *Spend) Since() time.Time {
+	now := s.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch s.period.Selected {
+	case "Last 7 days":
+		return today.AddDate(0, 0, -6)
+	case "Last 30 days":
+		return today.AddDate(0, 0, -29)
+	case "This month":
+		return today.AddDate(0, 0, 1-today.Day())
+	case "Last 90 days":
+		return today.AddDate(0, 0, -89)
+	}
+	return time.Time{}
+}
+
+// SetRows shows the ledger grouped as View asked for. For the repeated
+// queries view, pass all query rows; single runs are filtered out here.
+func (s *Spend) SetRows(rows []SpendRow) {
+	fyne.Do(func() {
+		s.rows = rows
+		s.shown = rows
+		if s.View() == SpendRepeated {
+			s.shown = nil
+			for _, r := range rows {
+				if r.Runs > 1 {
+					s.shown = append(s.shown, r)
+				}
+			}
+		}
+		s.summary.SetText(spendSummary(rows, s.period.Selected))
+		s.table.Refresh()
+	})
+}
+
+// SetStatus shows an error in place of the summary.
+func (s *Spend) SetStatus(text string) {
+	fyne.Do(func() { s.summary.SetText(text) })
+}
+
+func spendSummary(rows []SpendRow, period string) string {
+	var runs, billed int64
+	var cost float64
+	for _, r := range rows {
+		runs += r.Runs
+		billed += r.BytesBilled
+		cost += r.Cost
+	}
+	if runs == 0 {
+		return "No queries recorded in this period."
+	}
+	return fmt.Sprintf("%s: %s estimated across %d queries, %s billed", period, formatDollars(cost), runs, formatBytes(billed))
+}
+
+func (s *Spend) cell(r SpendRow, col int) string {
+	switch col {
+	case 0:
+		if r.SQL != "" {
+			return strings.Join(strings.Fields(r.SQL), " ")
+		}
+		return r.Key
+	case 1:
+		return fmt.Sprint(r.Runs)
+	case 2:
+		return formatBytes(r.BytesBilled)
+	case 3:
+		return formatDollars(r.Cost)
+	case 4:
+		if r.Runs == 0 {
+			return ""
+		}
+		return formatDollars(r.Cost / float64(r.Runs))
+	case 5:
+		var total float64
+		for _, x := range s.rows {
+			total += x.Cost
+		}
+		if total <= 0 {
+			return ""
+		}
+		share := r.Cost / total
+		return fmt.Sprintf("%s %4.1f%%", ratioBar(share), share*100)
+	case 6:
+		return r.Projects
+	case 7:
+		if r.Last.IsZero() {
+			return ""
+		}
+		return r.Last.Local().Format("2006-01-02 15:04")
+	}
+	return ""
+}
+
+func formatDollars(v float64) string {
+	if v > 0 && v < 0.01 {
+		return "<$0.01"
+	}
+	return fmt.Sprintf("$%.2f", v)
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestSpendSince(t *testing.T) {
+	s := NewSpend()
+	s.now = func() time.Time { return time.Date(2026, 3, 15, 18, 30, 0, 0, time.UTC) }
+	for period, want := range map[string]time.Time{
+		"Last 7 days":  time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC),
+		"This month":   time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		"Last 30 days": time.Date(2026, 2, 14, 0, 0, 0, 0, time.UTC),
+		"All time":     {},
+	} {
+		s.period.Selected = period
+		if got := s.Since(); !got.Equal(want) {
+			t.Errorf("Since for %q = %v, want %v", period, got, want)
+		}
+	}
+}
+
+func TestSpendRepeated(t *testing.T) {
+	s := NewSpend()
+	s.view.Selected = spendViews[SpendRepeated]
+	rows := []SpendRow{
+		{Key: "a", SQL: "select 1", Runs: 1, Cost: 5},
+		{Key: "b", SQL: "select\n  2", Runs: 3, Cost: 3, Projects: "p1,p2"},
+	}
+	s.SetRows(rows)
+	if len(s.shown) != 1 || s.shown[0].Key != "b" {
+		t.Fatalf("shown = %+v, want only repeated queries", s.shown)
+	}
+	if !strings.Contains(s.summary.Text, "$8.00") || !strings.Contains(s.summary.Text, "4 queries") {
+		t.Errorf("summary = %q, want totals over all queries", s.summary.Text)
+	}
+	if got := s.cell(s.shown[0], 0); got != "select 2" {
+		t.Errorf("query cell = %q", got)
+	}
+	if got := s.cell(s.shown[0], 5); !strings.HasSuffix(got, "37.5%") {
+		t.Errorf("share cell = %q", got)
+	}
+
+	var opened, project string
+	s.OnOpenQuery = func(sql, p string) { opened, project = sql, p }
+	s.table.Select(widget.TableCellID{Row: 0, Col: 2})
+	if opened != "select\n  2" || project != "p1" {
+		t.Errorf("opened %q in %q", opened, project)
+	}
+}
@@ -29,7 +29,9 @@ type SQLEditor struct {
 	focused   bool
 	blinkOn   bool
 	onChanged func(string)
-	OnSubmit  func() // called on Cmd+Enter / Ctrl+Enter
+	OnSubmit  func()            // called on Cmd+Enter / Ctrl+Enter
+	OnOpen    func()            // called on Cmd+O / Ctrl+O
+	OnSave    func(saveAs bool) // called on Cmd+S / Ctrl+S (saveAs with Shift)
 
 	// Selection state: anchor is where selection started, cursor is the other end.
 	hasSelection bool
@@ -58,9 +60,14 @@ type SQLEditor struct {
 	acFiltered      []string                       // filtered by current prefix
 	acVisible       bool
 	acSelected      int
+	acRoutines      map[string]Routine   // see routineIndex
+	acHint          bool                 // popup shows a routine signature, not completions
 	acLoadRequested map[string]bool      // projects we've already requested loading for
 	OnProjectNeeded func(project string) // callback: request loading data for a project
 
+	acSignatureRequested map[string]bool                        // routines whose signature we've requested
+	OnRoutineNeeded      func(project, dataset, routine string) // callback: request loading a routine's signature
+
 	// AC rendering (canvas primitives, created in CreateRenderer).
 	acBg         *canvas.Rectangle
 	acSelBg      *canvas.Rectangle
@@ -128,6 +135,22 @@ func (e *SQLEditor) SetText(text string) {
 	e.notifyChanged()
 }
 
+// CursorPosition returns the cursor's row and column.
+func (e *SQLEditor) CursorPosition() (row, col int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.cursorRow, e.cursorCol
+}
+
+// SetCursorPosition moves the cursor, clamping it to the current content.
+func (e *SQLEditor) SetCursorPosition(row, col int) {
+	e.mu.Lock()
+	e.cursorRow, e.cursorCol = e.clampPositionLocked(row, col)
+	e.hasSelection = false
+	e.mu.Unlock()
+	e.refreshContent()
+}
+
 // SetOnChanged sets a callback invoked after every edit.
 func (e *SQLEditor) SetOnChanged(fn func(string)) {
 	e.mu.Lock()
@@ -450,7 +473,12 @@ func (e *SQLEditor) TypedKey(ev *fyne.KeyEvent) {
 	e.mu.Lock()
 	acVis := e.acVisible
 	e.mu.Unlock()
-	if acVis {
+	if acVis && e.isHintShown() {
+		if ev.Name == fyne.KeyEscape {
+			e.hideACPopup()
+			return
+		}
+	} else if acVis {
 		switch ev.Name {
 		case fyne.KeyUp:
 			e.mu.Lock()
@@ -645,7 +673,7 @@ func (e *SQLEditor) clampPositionLocked(row, col int) (int, int) {
 func (e *SQLEditor) Tapped(ev *fyne.PointEvent) {
 	// Check if tap is on an autocomplete item.
 	e.mu.Lock()
-	if e.acVisible {
+	if e.acVisible && !e.acHint {
 		pos := ev.Position
 		if pos.X >= e.acDropdownX && pos.X <= e.acDropdownX+e.acDropdownW &&
 			pos.Y >= e.acDropdownY && pos.Y <= e.acDropdownY+e.acDropdownH {
@@ -752,6 +780,16 @@ func (e *SQLEditor) handleCustomShortcut(cs *desktop.CustomShortcut) {
 	hasCmdOrCtrl := cs.Modifier&(fyne.KeyModifierSuper|fyne.KeyModifierControl) != 0
 
 	switch cs.KeyName {
+	case fyne.KeyS:
+		if hasCmdOrCtrl && e.OnSave != nil {
+			e.OnSave(hasShift)
+			return
+		}
+	case fyne.KeyO:
+		if hasCmdOrCtrl && e.OnOpen != nil {
+			e.OnOpen()
+			return
+		}
 	case fyne.KeyZ:
 		if hasCmdOrCtrl {
 			if hasShift {
@@ -1220,6 +1258,20 @@ func (e *SQLEditor) SetProjectData(data map[string]map[string][]string) {
 	e.updateAutocomplete()
 }
 
+// SetRoutines stores the routines offered after "project.dataset." and
+// whose signature is shown inside their argument list.
+func (e *SQLEditor) SetRoutines(routines []Routine) {
+	e.mu.Lock()
+	e.acRoutines = routineIndex(routines)
+	e.mu.Unlock()
+}
+
+func (e *SQLEditor) isHintShown() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.acHint
+}
+
 // dottedExprBeforeCursorLocked walks left from the cursor to extract a dotted expression
 // (e.g. "project.dataset.tab"). Returns nil if no dots are found (caller should use flat completion).
 // Caller must hold mu.
@@ -1263,8 +1315,44 @@ func (e *SQLEditor) updateAutocomplete() {
 	e.mu.Lock()
 	parts := e.dottedExprBeforeCursorLocked()
 	projectData := e.acProjectData
+	routines := e.acRoutines
+	var hint string
+	var needed *Routine
+	if parts == nil && e.wordBeforeCursorLocked() == "" {
+		if name := routineCallAt(e.lines[e.cursorRow], e.cursorCol); name != "" {
+			r, ok := routines[strings.ToLower(name)]
+			hint = r.Signature
+			key := strings.ToLower(r.Project + "." + r.Dataset + "." + r.Name)
+			if ok && hint == "" && !e.acSignatureRequested[key] {
+				if e.acSignatureRequested == nil {
+					e.acSignatureRequested = make(map[string]bool)
+				}
+				e.acSignatureRequested[key] = true
+				needed = &r
+			}
+		}
+	}
+	fn := e.OnRoutineNeeded
 	e.mu.Unlock()
 
+	// Signatures are loaded on first use; the hint shows once SetRoutines
+	// brings it.
+	if needed != nil && fn != nil {
+		go fn(needed.Project, needed.Dataset, needed.Name)
+	}
+
+	// Inside a routine's argument list: show its signature.
+	if hint != "" {
+		e.mu.Lock()
+		e.acPrefix = ""
+		e.acFiltered = []string{hint}
+		e.acSelected = -1
+		e.acHint = true
+		e.mu.Unlock()
+		e.showACPopup()
+		return
+	}
+
 	// Dotted-path branch: context-aware completion for project.dataset.table
 	if parts != nil && projectData != nil {
 		var candidates []string
@@ -1288,7 +1376,12 @@ func (e *SQLEditor) updateAutocomplete() {
 			prefix = parts[2]
 			if dsMap, ok := projectData[project]; ok {
 				if tables, ok := dsMap[dataset]; ok {
-					candidates = tables
+					candidates = append(candidates, tables...)
+				}
+			}
+			for key, r := range routines {
+				if strings.Count(key, ".") == 2 && strings.EqualFold(r.Project, project) && strings.EqualFold(r.Dataset, dataset) {
+					candidates = append(candidates, r.Name)
 				}
 			}
 		}
@@ -1327,6 +1420,7 @@ func (e *SQLEditor) updateAutocomplete() {
 				e.acPrefix = prefix
 				e.acFiltered = filtered
 				e.acSelected = 0
+				e.acHint = false
 				e.mu.Unlock()
 				e.showACPopup()
 				return
@@ -1363,6 +1457,7 @@ func (e *SQLEditor) updateAutocomplete() {
 	e.mu.Lock()
 	e.acFiltered = filtered
 	e.acSelected = 0
+	e.acHint = false
 	e.mu.Unlock()
 
 	e.showACPopup()
@@ -1379,15 +1474,26 @@ func (e *SQLEditor) showACPopup() {
 	if n > maxACDisplay {
 		n = maxACDisplay
 	}
+	hint := ""
+	if e.acHint && n > 0 {
+		hint = e.acFiltered[0]
+	}
 	e.mu.Unlock()
 
 	charSize := fyne.MeasureText("M", theme.TextSize(), fyne.TextStyle{Monospace: true})
 	itemH := charSize.Height + theme.Padding()
+	w := float32(220)
+	if hint != "" {
+		// Signatures are wider than names; fit the popup to the text.
+		if tw := fyne.MeasureText(hint, theme.TextSize(), fyne.TextStyle{}).Width + 2*theme.Padding(); tw > w {
+			w = tw
+		}
+	}
 
 	e.mu.Lock()
 	e.acDropdownX = float32(curCol-len(prefix)) * charSize.Width
 	e.acDropdownY = float32(curRow+1) * charSize.Height
-	e.acDropdownW = float32(220)
+	e.acDropdownW = w
 	e.acDropdownH = float32(n) * itemH
 	e.acItemHeight = itemH
 	e.mu.Unlock()
@@ -1399,6 +1505,7 @@ func (e *SQLEditor) showACPopup() {
 func (e *SQLEditor) hideACPopup() {
 	e.mu.Lock()
 	e.acVisible = false
+	e.acHint = false
 	e.mu.Unlock()
 	e.refreshAC()
 }
@@ -1494,7 +1601,7 @@ func (e *SQLEditor) refreshAC() {
 // acceptCompletion inserts the remaining suffix of the selected completion at the cursor.
 func (e *SQLEditor) acceptCompletion() {
 	e.mu.Lock()
-	if !e.acVisible || len(e.acFiltered) == 0 {
+	if !e.acVisible || e.acHint || len(e.acFiltered) == 0 {
 		e.mu.Unlock()
 		return
 	}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/farbodahm/delephon/bq"
+	"github.com/farbodahm/delephon/store"
+	"github.com/farbodahm/delephon/ui"
+)
+
+// Choices offered by the "Upload File" dialog.
+var (
+	uploadFormatLabels = map[string]string{
+		"CSV":                    bq.FormatCSV,
+		"Newline-delimited JSON": bq.FormatJSON,
+		"Avro":                   bq.FormatAvro,
+		"Parquet":                bq.FormatParquet,
+	}
+	uploadFormatOptions = []string{"CSV", "Newline-delimited JSON", "Avro", "Parquet"}
+
+	delimiterLabels = map[string]string{
+		"Comma":     ",",
+		"Tab":       "\t",
+		"Semicolon": ";",
+		"Pipe":      "|",
+	}
+	delimiterOptions = []string{"Comma", "Tab", "Semicolon", "Pipe"}
+
+	uploadExtensions = []string{".csv", ".tsv", ".txt", ".json", ".ndjson", ".jsonl", ".avro", ".parquet", ".parq"}
+)
+
+// uploadPreviewRows is how many rows the upload dialog reads to preview a
+// file and infer its schema.
+const uploadPreviewRows = 100
+
+// uploadFile asks for a local file and loads it into a table of dataset,
+// or into table when one is given.
+func (a *App) uploadFile(project, dataset, table string) {
+	d := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+		if err != nil {
+			a.showError("Upload File", err)
+			return
+		}
+		if rc == nil {
+			return
+		}
+		path := rc.URI().Path()
+		rc.Close()
+		a.showUploadDialog(project, dataset, table, path)
+	}, a.window)
+	d.SetFilter(storage.NewExtensionFileFilter(uploadExtensions))
+	d.Resize(fyne.NewSize(800, 560))
+	d.Show()
+}
+
+// defaultTableName derives a table name from a file name, e.g.
+// "Country Codes 2024.csv" becomes "Country_Codes_2024".
+func defaultTableName(path string) string {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return bq.ColumnNames([]string{base})[0]
+}
+
+// showUploadDialog previews path and asks where and how to load it.
+func (a *App) showUploadDialog(project, dataset, table, path string) {
+	if !a.projectWritable(project, auditUploadFile, project+"."+dataset) {
+		return
+	}
+
+	format := bq.DetectFormat(path)
+	formatSelect := widget.NewSelect(uploadFormatOptions, nil)
+	for label, f := range uploadFormatLabels {
+		if f == format {
+			formatSelect.Selected = label
+		}
+	}
+	if formatSelect.Selected == "" {
+		formatSelect.Selected = uploadFormatOptions[0]
+	}
+
+	tableEntry := widget.NewEntry()
+	tableEntry.SetPlaceHolder("table")
+	if table != "" {
+		tableEntry.SetText(table)
+	} else {
+		tableEntry.SetText(defaultTableName(path))
+	}
+	writeSelect := widget.NewSelect(writeDispositionOptions, nil)
+	writeSelect.SetSelected("Append to the table")
+
+	delimiterSelect := widget.NewSelect(delimiterOptions, nil)
+	delimiterSelect.Selected = "Comma"
+	if strings.EqualFold(filepath.Ext(path), ".tsv") {
+		delimiterSelect.Selected = "Tab"
+	}
+	headerCheck := widget.NewCheck("First row holds column names", nil)
+	headerCheck.Checked = true
+	schemaCheck := widget.NewCheck("Use the previewed column types (otherwise BigQuery detects them)", nil)
+	// An existing table keeps its own schema unless the user asks otherwise.
+	schemaCheck.Checked = table == ""
+	badRowsEntry := widget.NewEntry()
+	badRowsEntry.SetText("0")
+	badRowsEntry.Validator = func(s string) error {
+		if n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64); err != nil || n < 0 {
+			return errors.New("enter a number of rows")
+		}
+		return nil
+	}
+
+	var preview *bq.FilePreview
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+	previewTable := widget.NewTableWithHeaders(
+		func() (int, int) {
+			if preview == nil {
+				return 0, 0
+			}
+			return len(preview.Rows), len(preview.Fields)
+		},
+		func() fyne.CanvasObject {
+			l := widget.NewLabel("")
+			l.Truncation = fyne.TextTruncateEllipsis
+			return l
+		},
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			if preview != nil && id.Row < len(preview.Rows) && id.Col < len(preview.Rows[id.Row]) {
+				obj.(*widget.Label).SetText(preview.Rows[id.Row][id.Col])
+			}
+		},
+	)
+	previewTable.UpdateHeader = func(id widget.TableCellID, template fyne.CanvasObject) {
+		label := template.(*widget.Label)
+		if preview != nil && id.Row < 0 && id.Col >= 0 && id.Col < len(preview.Fields) {
+			f := preview.Fields[id.Col]
+			label.SetText(f.Name + " " + f.Type)
+		} else if id.Col < 0 && id.Row >= 0 {
+			label.SetText(strconv.Itoa(id.Row + 1))
+		}
+	}
+
+	refreshPreview := func() {
+		format := uploadFormatLabels[formatSelect.Selected]
+		isCSV := format == bq.FormatCSV
+		for _, w := range []fyne.Disableable{delimiterSelect, headerCheck, schemaCheck} {
+			if isCSV {
+				w.Enable()
+			} else {
+				w.Disable()
+			}
+		}
+		preview = nil
+		switch {
+		case bq.SelfDescribing(format):
+			status.SetText(fmt.Sprintf("%s files carry their own schema; BigQuery reads it from the file.", formatSelect.Selected))
+		default:
+			p, err := bq.PreviewFile(path, format, delimiterLabels[delimiterSelect.Selected], headerCheck.Checked, uploadPreviewRows)
+			if err != nil {
+				status.SetText(fmt.Sprintf("Preview failed: %v", err))
+				break
+			}
+			preview = p
+			if isCSV {
+				status.SetText(fmt.Sprintf("Column types inferred from the first %d rows.", len(p.Rows)))
+			} else {
+				status.SetText(fmt.Sprintf("First %d rows; BigQuery detects the schema, including nested fields.", len(p.Rows)))
+			}
+		}
+		if preview != nil {
+			for i := range preview.Fields {
+				previewTable.SetColumnWidth(i, 140)
+			}
+		}
+		previewTable.Refresh()
+	}
+	formatSelect.OnChanged = func(string) { refreshPreview() }
+	delimiterSelect.OnChanged = func(string) { refreshPreview() }
+	headerCheck.OnChanged = func(bool) { refreshPreview() }
+	refreshPreview()
+
+	form := widget.NewForm(
+		widget.NewFormItem("File", widget.NewLabel(filepath.Base(path))),
+		widget.NewFormItem("Format", formatSelect),
+		widget.NewFormItem("Table", container.NewBorder(nil, nil, widget.NewLabel(project+"."+dataset+"."), nil, tableEntry)),
+		widget.NewFormItem("If table exists", writeSelect),
+		widget.NewFormItem("Delimiter", delimiterSelect),
+		widget.NewFormItem("", headerCheck),
+		widget.NewFormItem("", schemaCheck),
+		widget.NewFormItem("Skip bad rows", badRowsEntry),
+	)
+	content := container.NewBorder(container.NewVBox(form, status), nil, nil, nil, previewTable)
+
+	d := dialog.NewCustomConfirm("Upload File", "Upload", "Cancel", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		dst := bq.TableDestination{
+			ProjectID:        project,
+			DatasetID:        dataset,
+			TableID:          strings.TrimSpace(tableEntry.Text),
+			WriteDisposition: writeDispositionLabels[writeSelect.Selected],
+		}
+		if err := validateDestination(dst); err != nil {
+			a.showError("Upload File", err)
+			return
+		}
+		if err := badRowsEntry.Validate(); err != nil {
+			a.showError("Upload File", fmt.Errorf("skip bad rows: %w", err))
+			return
+		}
+		badRows, _ := strconv.ParseInt(strings.TrimSpace(badRowsEntry.Text), 10, 64)
+		opts := bq.LoadOptions{
+			Format:        uploadFormatLabels[formatSelect.Selected],
+			MaxBadRecords: badRows,
+			Labels:        a.queryOptions(project, sourceUpload).Labels,
+		}
+		if opts.Format == bq.FormatCSV {
+			opts.FieldDelimiter = delimiterLabels[delimiterSelect.Selected]
+			if headerCheck.Checked {
+				opts.SkipLeadingRows = 1
+			}
+			if schemaCheck.Checked && preview != nil {
+				opts.Schema = preview.Fields
+			}
+		}
+		run := func() { a.runUpload(dst, path, opts) }
+		if dst.WriteDisposition == bq.WriteTruncate {
+			a.confirmTyped("Overwrite Table", "Overwrite",
+				fmt.Sprintf("All rows of %s will be replaced with those of %s.\n\nType %s.%s to overwrite it.",
+					dst.FullName(), filepath.Base(path), dst.DatasetID, dst.TableID),
+				dst.DatasetID+"."+dst.TableID, run)
+			return
+		}
+		go run()
+	}, a.window)
+	d.Resize(fyne.NewSize(760, 620))
+	d.Show()
+}
+
+// runUpload runs the load as a background job shown in the Jobs tab.
+func (a *App) runUpload(dst bq.TableDestination, path string, opts bq.LoadOptions) {
+	ctx, cancel := context.WithCancel(a.ctx)
+	defer cancel()
+
+	id := a.jobs.Start(fmt.Sprintf("Upload %s to %s", filepath.Base(path), dst.FullName()), cancel)
+	fyne.Do(func() { a.bottomTabs.Select(a.jobsTab) })
+
+	lastPercent := int64(-1)
+	res, err := a.bqMgr.LoadFile(ctx, dst, path, opts, func(done, total int64) {
+		if total <= 0 {
+			return
+		}
+		if p := done * 100 / total; p != lastPercent {
+			lastPercent = p
+			a.jobs.SetProgress(id, fmt.Sprintf("uploading %d%% of %s", p, formatByteSize(total)))
+		}
+	}, func(jobID string) {
+		a.jobs.SetProgress(id, "job "+jobID+" loading")
+	})
+	if errors.Is(err, context.Canceled) {
+		err = errors.New("cancelled")
+	}
+	e := store.AuditEntry{Action: auditUploadFile, Project: dst.ProjectID, Target: dst.FullName(), Detail: "from " + path}
+	if dst.WriteDisposition == bq.WriteTruncate {
+		e.Detail += " (overwrite)"
+	}
+	if err != nil {
+		e.Error = err.Error()
+		a.audit(e)
+		a.jobs.Finish(id, err, "")
+		var le *bq.LoadError
+		if errors.As(err, &le) && len(le.Rows) > 0 {
+			a.showLoadErrors("Upload Failed", fmt.Sprintf("Loading %s failed:", filepath.Base(path)), le.Rows)
+		}
+		return
+	}
+
+	detail := fmt.Sprintf("%d rows loaded | %d rows in table | job %s", res.OutputRows, res.TotalRows, res.JobID)
+	if len(res.BadRows) > 0 {
+		detail += fmt.Sprintf(" | %d bad rows skipped", len(res.BadRows))
+		a.showLoadErrors("Bad Rows Skipped", fmt.Sprintf("%d rows of %s could not be loaded and were skipped:",
+			len(res.BadRows), filepath.Base(path)), res.BadRows)
+	}
+	a.jobs.Finish(id, nil, detail)
+	e.Detail += " | " + detail
+	a.audit(e)
+	a.explorer.ReloadChildren(ui.DatasetNodeID(dst.ProjectID, dst.DatasetID))
+}
+
+// showLoadErrors lists the errors BigQuery reported for rows of a load.
+func (a *App) showLoadErrors(title, intro string, rows []string) {
+	fyne.Do(func() {
+		text := widget.NewLabel(strings.Join(rows, "\n"))
+		text.Wrapping = fyne.TextWrapWord
+		text.Selectable = true
+		content := container.NewBorder(widget.NewLabel(intro), nil, nil, nil, container.NewVScroll(text))
+		d := dialog.NewCustom(title, "Close", content, a.window)
+		d.Resize(fyne.NewSize(640, 400))
+		d.Show()
+	})
+}
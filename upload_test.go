@@ -0,0 +1,16 @@
+package main
+
+import "testing"
+
+func TestDefaultTableName(t *testing.T) {
+	for path, want := range map[string]string{
+		"/home/me/Country Codes 2024.csv": "Country_Codes_2024",
+		"lookup.ndjson":                   "lookup",
+		"2024-prices.parquet":             "_2024_prices",
+		"/tmp/.csv":                       "column_1",
+	} {
+		if got := defaultTableName(path); got != want {
+			t.Errorf("defaultTableName(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
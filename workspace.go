@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/farbodahm/delephon/ui"
+)
+
+const (
+	defaultWorkspace = "default"
+
+	// workspaceAutosaveInterval is how often the workspace is checked for
+	// changes and written to the store, bounding what a crash can lose.
+	workspaceAutosaveInterval = 3 * time.Second
+)
+
+// workspaceState is the serialized layout of the main window: editor tabs,
+// split offsets and the selected bottom tab.
+type workspaceState struct {
+	Tabs        []ui.TabState `json:"tabs"`
+	SelectedTab int           `json:"selected_tab"`
+	MainSplit   float64       `json:"main_split"`
+	RightSplit  float64       `json:"right_split"`
+	SchemaSplit float64       `json:"schema_split"`
+	BottomTab   int           `json:"bottom_tab"`
+}
+
+// captureWorkspace snapshots the current window state. Must run on the UI goroutine.
+func (a *App) captureWorkspace() workspaceState {
+	tabs, selected := a.editor.Session()
+	return workspaceState{
+		Tabs:        tabs,
+		SelectedTab: selected,
+		MainSplit:   a.mainSplit.Offset,
+		RightSplit:  a.rightSplit.Offset,
+		SchemaSplit: a.editorSchemaSplit.Offset,
+		BottomTab:   a.bottomTabs.SelectedIndex(),
+	}
+}
+
+// applyWorkspace restores a saved window state. Must run on the UI goroutine.
+func (a *App) applyWorkspace(st workspaceState) {
+	a.editor.RestoreSession(st.Tabs, st.SelectedTab)
+	if st.MainSplit > 0 {
+		a.mainSplit.SetOffset(st.MainSplit)
+	}
+	if st.RightSplit > 0 {
+		a.rightSplit.SetOffset(st.RightSplit)
+	}
+	if st.SchemaSplit > 0 {
+		a.editorSchemaSplit.SetOffset(st.SchemaSplit)
+	}
+	if st.BottomTab >= 0 && st.BottomTab < len(a.bottomTabs.Items) {
+		a.bottomTabs.SelectIndex(st.BottomTab)
+	}
+}
+
+// saveWorkspace writes the state captured from workspace name, skipping the
+// write when nothing changed since the last save. A state captured before
+// switching to another workspace is dropped; the switch saved it already.
+func (a *App) saveWorkspace(name string, st workspaceState) {
+	data, err := json.Marshal(st)
+	if err != nil {
+		log.Printf("workspace: encode: %v", err)
+		return
+	}
+	a.workspaceMu.Lock()
+	defer a.workspaceMu.Unlock()
+	if name != a.workspaceName || string(data) == a.lastSavedWorkspace {
+		return
+	}
+	if err := a.store.SaveWorkspace(name, string(data)); err != nil {
+		log.Printf("workspace: save %q: %v", name, err)
+		return
+	}
+	a.lastSavedWorkspace = string(data)
+}
+
+// loadWorkspace reads a workspace from the store. A workspace that was never
+// saved yields an empty state (a single scratch tab).
+func (a *App) loadWorkspace(name string) (workspaceState, error) {
+	var st workspaceState
+	data, err := a.store.GetWorkspace(name)
+	if err != nil || data == "" {
+		return st, err
+	}
+	if err := json.Unmarshal([]byte(data), &st); err != nil {
+		return st, fmt.Errorf("decode workspace %q: %w", name, err)
+	}
+	return st, nil
+}
+
+// RestoreWorkspace reopens the last active workspace and starts autosaving it.
+// Call after BuildUI.
+func (a *App) RestoreWorkspace() {
+	name, _ := a.store.GetSetting("active_workspace")
+	if name == "" {
+		name = defaultWorkspace
+	}
+	a.workspaceName = name
+
+	st, err := a.loadWorkspace(name)
+	if err != nil {
+		log.Printf("workspace: %v", err)
+	}
+	if len(st.Tabs) > 0 {
+		a.applyWorkspace(st)
+	}
+	a.refreshWorkspaceList()
+
+	a.window.SetOnClosed(func() {
+		a.saveWorkspace(a.workspaceName, a.captureWorkspace())
+	})
+	go a.autosaveWorkspace()
+}
+
+func (a *App) autosaveWorkspace() {
+	ticker := time.NewTicker(workspaceAutosaveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			var name string
+			var st workspaceState
+			fyne.DoAndWait(func() { name, st = a.workspaceName, a.captureWorkspace() })
+			a.saveWorkspace(name, st)
+		}
+	}
+}
+
+// switchWorkspace saves the current workspace and opens another one,
+// creating it empty if it does not exist yet.
+func (a *App) switchWorkspace(name string) {
+	if name == "" || name == a.workspaceName {
+		return
+	}
+	a.saveWorkspace(a.workspaceName, a.captureWorkspace())
+
+	st, err := a.loadWorkspace(name)
+	if err != nil {
+		a.showError("Workspace Error", err)
+		return
+	}
+	a.workspaceMu.Lock()
+	a.workspaceName = name
+	a.lastSavedWorkspace = ""
+	a.workspaceMu.Unlock()
+	_ = a.store.SetSetting("active_workspace", name)
+
+	a.applyWorkspace(st)
+	a.saveWorkspace(name, a.captureWorkspace())
+	a.refreshWorkspaceList()
+}
+
+// refreshWorkspaceList updates the workspace picker with stored names.
+func (a *App) refreshWorkspaceList() {
+	names, err := a.store.ListWorkspaces()
+	if err != nil {
+		log.Printf("workspace: list: %v", err)
+	}
+	found := false
+	for _, n := range names {
+		if n == a.workspaceName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		names = append(names, a.workspaceName)
+		sort.Strings(names)
+	}
+	a.workspaceSelect.Options = names
+	a.workspaceSelect.Selected = a.workspaceName
+	a.workspaceSelect.Refresh()
+}
+
+func (a *App) newWorkspace() {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("e.g. billing investigation")
+	dialog.ShowForm("New Workspace", "Create", "Cancel",
+		[]*widget.FormItem{widget.NewFormItem("Name", entry)},
+		func(ok bool) {
+			name := strings.TrimSpace(entry.Text)
+			if !ok || name == "" {
+				return
+			}
+			a.switchWorkspace(name)
+		},
+		a.window,
+	)
+}
+
+func (a *App) deleteWorkspace() {
+	name := a.workspaceName
+	if name == defaultWorkspace {
+		return
+	}
+	dialog.ShowConfirm("Delete Workspace",
+		fmt.Sprintf("Delete workspace %q and its saved tabs?", name),
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			a.switchWorkspace(defaultWorkspace)
+			if err := a.store.DeleteWorkspace(name); err != nil {
+				a.showError("Workspace Error", err)
+			}
+			a.refreshWorkspaceList()
+		},
+		a.window,
+	)
+}